@@ -43,15 +43,27 @@ func ensureTypeIndex() {
 	})
 }
 
-// TypeIndex provides fast lookup of type definitions by package and type name.
+// TypeIndex provides fast lookup of type definitions by import path and type name.
+//
+// Types are keyed by the full import path of their package so that distinct
+// packages sharing a name (two "models" packages, say) never overwrite each
+// other. Identifiers are resolved through the import table of the file they
+// appear in, which is what makes an aliased `order.Item` land on the right struct.
 type TypeIndex struct {
-	types              map[string]map[string]*ast.TypeSpec // package -> type -> spec
+	types              map[string]map[string]*ast.TypeSpec // import path -> type -> spec
 	files              map[string]*ast.File                // file path -> parsed file
 	externalKnownTypes map[string]*Schema                  // external known types
-	qualifiedTypes     map[string]*ast.TypeSpec            // qualified type name -> spec (e.g., "order.CreateReq")
-	packageImports     map[string]string                   // import path -> package name (e.g., "github.com/user/sqlc" -> "sqlc")
-	loadedExternalPkgs map[string]bool                     // package alias -> attempted (to avoid repeated go list calls)
-	typeJSONHints      map[string]typeJSONHint             // qualified type name -> marshaler interface hints
+	packageNames       map[string]string                   // import path -> declared package name
+	packagesByName     map[string][]string                 // package name -> import paths declaring it
+	fileImports        map[string]map[string]string        // file path -> import alias -> import path
+	filePackages       map[string]string                   // file path -> import path of the file's package
+	specFiles          map[*ast.TypeSpec]string            // type spec -> declaring file path
+	externalPkgs       map[string]bool                     // import paths indexed from outside the project
+	loadedExternalPkgs map[string]bool                     // import path -> attempted (to avoid repeated go list calls)
+	typeJSONHints      map[string]typeJSONHint             // "import/path.Type" -> marshaler interface hints
+	specDocs           map[*ast.TypeSpec]*ast.CommentGroup // type spec -> doc comment (own or declaration's)
+	methodSets         map[string]map[string]bool          // "import/path.Type" -> declared method names
+	implementations    map[string]Implementations          // interface type name -> registered implementations
 }
 
 type typeJSONHint struct {
@@ -67,12 +79,22 @@ func BuildTypeIndex() *TypeIndex {
 		types:              make(map[string]map[string]*ast.TypeSpec),
 		files:              make(map[string]*ast.File),
 		externalKnownTypes: make(map[string]*Schema),
-		qualifiedTypes:     make(map[string]*ast.TypeSpec),
-		packageImports:     make(map[string]string),
+		packageNames:       make(map[string]string),
+		packagesByName:     make(map[string][]string),
+		fileImports:        make(map[string]map[string]string),
+		filePackages:       make(map[string]string),
+		specFiles:          make(map[*ast.TypeSpec]string),
+		externalPkgs:       make(map[string]bool),
 		loadedExternalPkgs: make(map[string]bool),
 		typeJSONHints:      make(map[string]typeJSONHint),
+		specDocs:           make(map[*ast.TypeSpec]*ast.CommentGroup),
+		methodSets:         make(map[string]map[string]bool),
+		implementations:    make(map[string]Implementations),
 	}
 
+	// Import paths of project packages are derived from the module path.
+	loadModulePath()
+
 	// Find project root by looking for go.mod
 	projectRoot := findProjectRoot()
 	if projectRoot == "" {
@@ -90,15 +112,38 @@ func BuildTypeIndex() *TypeIndex {
 			return err
 		}
 
-		return idx.indexFile(path)
+		return idx.indexFile(path, projectImportPath(projectRoot, filepath.Dir(path)))
 	})
 
 	slog.Debug("[annot8] BuildTypeIndex: completed", "totalPackages", len(idx.types), "totalFiles", len(idx.files))
 	return idx
 }
 
-// indexFile processes a single Go file and indexes its types
-func (idx *TypeIndex) indexFile(filePath string) error {
+// projectImportPath derives the import path of a project directory from the
+// module path. Without a go.mod the slash-separated relative directory is used,
+// and indexFile falls back to the package name for the root itself.
+func projectImportPath(projectRoot, dir string) string {
+	rel, err := filepath.Rel(projectRoot, dir)
+	if err != nil {
+		rel = dir
+	}
+	rel = filepath.ToSlash(rel)
+	if rel == "." {
+		rel = ""
+	}
+
+	switch {
+	case modulePath != "" && rel != "":
+		return modulePath + "/" + rel
+	case modulePath != "":
+		return modulePath
+	default:
+		return rel
+	}
+}
+
+// indexFile processes a single Go file and indexes its types under importPath.
+func (idx *TypeIndex) indexFile(filePath, importPath string) error {
 	fset := token.NewFileSet()
 	file, err := parser.ParseFile(fset, filePath, nil, parser.ParseComments)
 	if err != nil {
@@ -110,21 +155,30 @@ func (idx *TypeIndex) indexFile(filePath string) error {
 	normalizedPath := filepath.ToSlash(filePath)
 	idx.files[normalizedPath] = file
 	pkg := file.Name.Name
+	if importPath == "" {
+		importPath = pkg
+	}
 
-	// Record package imports for external vs internal classification
+	idx.filePackages[normalizedPath] = importPath
+	idx.registerPackage(importPath, pkg)
+
+	// Record the file's import table; identifiers are resolved per file.
+	imports := make(map[string]string, len(file.Imports))
 	for _, imp := range file.Imports {
-		importPath := strings.Trim(imp.Path.Value, `"`)
-		var alias string
+		ip := strings.Trim(imp.Path.Value, `"`)
+		alias := guessPackageName(ip)
 		if imp.Name != nil && imp.Name.Name != "" {
+			if imp.Name.Name == "_" || imp.Name.Name == "." {
+				continue
+			}
 			alias = imp.Name.Name
-		} else {
-			alias = path.Base(importPath)
 		}
-		idx.packageImports[importPath] = alias
+		imports[alias] = ip
 	}
+	idx.fileImports[normalizedPath] = imports
 
-	if _, ok := idx.types[pkg]; !ok {
-		idx.types[pkg] = make(map[string]*ast.TypeSpec)
+	if _, ok := idx.types[importPath]; !ok {
+		idx.types[importPath] = make(map[string]*ast.TypeSpec)
 	}
 
 	// Index type declarations
@@ -133,17 +187,19 @@ func (idx *TypeIndex) indexFile(filePath string) error {
 			for _, spec := range gd.Specs {
 				if ts, isTypeSpec := spec.(*ast.TypeSpec); isTypeSpec {
 					typeName := ts.Name.Name
-					qualifiedName := idx.getQualifiedTypeName(pkg, typeName)
-
-					// Store in both maps
-					idx.types[pkg][typeName] = ts
-					idx.qualifiedTypes[qualifiedName] = ts
+					idx.types[importPath][typeName] = ts
+					idx.specFiles[ts] = normalizedPath
+					if doc := ts.Doc; doc != nil {
+						idx.specDocs[ts] = doc
+					} else if len(gd.Specs) == 1 && gd.Doc != nil {
+						idx.specDocs[ts] = gd.Doc
+					}
 
 					slog.Debug(
 						"[annot8] BuildTypeIndex: indexed type",
 						"package", pkg,
+						"importPath", importPath,
 						"type", typeName,
-						"qualified", qualifiedName,
 						"file", filePath,
 					)
 				}
@@ -154,13 +210,44 @@ func (idx *TypeIndex) indexFile(filePath string) error {
 		if !ok {
 			continue
 		}
-		idx.indexMethodHint(pkg, fd)
+		idx.indexMethodHint(importPath, fd)
+		idx.indexMethodName(importPath, fd)
 	}
 
 	return nil
 }
 
-func (idx *TypeIndex) indexMethodHint(pkg string, fd *ast.FuncDecl) {
+// registerPackage records the declared name of the package at importPath.
+// A stray `package main` generator file (usually build-ignored) never
+// renames a library package it shares a directory with.
+func (idx *TypeIndex) registerPackage(importPath, pkg string) {
+	if existing, ok := idx.packageNames[importPath]; ok && existing != pkg {
+		if pkg == "main" {
+			return
+		}
+		idx.packagesByName[existing] = removeString(idx.packagesByName[existing], importPath)
+	}
+	idx.packageNames[importPath] = pkg
+	for _, existing := range idx.packagesByName[pkg] {
+		if existing == importPath {
+			return
+		}
+	}
+	idx.packagesByName[pkg] = append(idx.packagesByName[pkg], importPath)
+	sort.Strings(idx.packagesByName[pkg])
+}
+
+func removeString(values []string, target string) []string {
+	out := values[:0]
+	for _, v := range values {
+		if v != target {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func (idx *TypeIndex) indexMethodHint(importPath string, fd *ast.FuncDecl) {
 	if fd == nil || fd.Recv == nil || fd.Name == nil || len(fd.Recv.List) != 1 {
 		return
 	}
@@ -170,8 +257,8 @@ func (idx *TypeIndex) indexMethodHint(pkg string, fd *ast.FuncDecl) {
 		return
 	}
 
-	qualifiedName := idx.getQualifiedTypeName(pkg, receiverName)
-	hint := idx.typeJSONHints[qualifiedName]
+	key := importPath + "." + receiverName
+	hint := idx.typeJSONHints[key]
 
 	switch fd.Name.Name {
 	case "MarshalJSON":
@@ -186,7 +273,21 @@ func (idx *TypeIndex) indexMethodHint(pkg string, fd *ast.FuncDecl) {
 		return
 	}
 
-	idx.typeJSONHints[qualifiedName] = hint
+	idx.typeJSONHints[key] = hint
+}
+
+// indexMethodName records fd in its receiver type's method set, used to find
+// the implementations of an interface.
+func (idx *TypeIndex) indexMethodName(importPath string, fd *ast.FuncDecl) {
+	receiverName := receiverTypeName(fd)
+	if receiverName == "" || fd.Name == nil {
+		return
+	}
+	key := importPath + "." + receiverName
+	if idx.methodSets[key] == nil {
+		idx.methodSets[key] = make(map[string]bool)
+	}
+	idx.methodSets[key][fd.Name.Name] = true
 }
 
 func GetTypeIndex() *TypeIndex {
@@ -200,6 +301,8 @@ func GetTypeIndex() *TypeIndex {
 }
 
 // LookupType returns the TypeSpec for a given package and type name, or nil if not found.
+// pkg may be a full import path or a package name; an ambiguous package name
+// resolves the same way LookupUnqualifiedType does (internal packages first).
 func (idx *TypeIndex) LookupType(pkg, typeName string) *ast.TypeSpec {
 	if idx == nil {
 		return nil
@@ -207,15 +310,23 @@ func (idx *TypeIndex) LookupType(pkg, typeName string) *ast.TypeSpec {
 	if pkgTypes, ok := idx.types[pkg]; ok {
 		return pkgTypes[typeName]
 	}
+	if importPath := idx.importPathForPackageName(pkg, typeName); importPath != "" {
+		return idx.types[importPath][typeName]
+	}
 	return nil
 }
 
-// LookupQualifiedType returns the TypeSpec for a qualified type name (e.g., "order.CreateReq")
+// LookupQualifiedType returns the TypeSpec for a qualified type name, either
+// short ("order.CreateReq") or import-path qualified ("example.com/app/order.CreateReq").
 func (idx *TypeIndex) LookupQualifiedType(qualifiedName string) *ast.TypeSpec {
 	if idx == nil {
 		return nil
 	}
-	return idx.qualifiedTypes[qualifiedName]
+	importPath, typeName := idx.resolveID(qualifiedName)
+	if importPath == "" {
+		return nil
+	}
+	return idx.types[importPath][typeName]
 }
 
 // LookupFile returns the AST for a given file path, handling normalization and case-insensitivity on Windows.
@@ -223,18 +334,26 @@ func (idx *TypeIndex) LookupFile(filePath string) *ast.File {
 	if idx == nil {
 		return nil
 	}
+	if key := idx.fileKey(filePath); key != "" {
+		return idx.files[key]
+	}
+	return nil
+}
+
+// fileKey returns the key under which filePath was indexed, or "" if it was not.
+func (idx *TypeIndex) fileKey(filePath string) string {
 	normalized := filepath.ToSlash(filePath)
-	if f, ok := idx.files[normalized]; ok {
-		return f
+	if _, ok := idx.files[normalized]; ok {
+		return normalized
 	}
 
 	// Case-insensitive fallback for Windows
-	for p, f := range idx.files {
+	for p := range idx.files {
 		if strings.EqualFold(p, normalized) {
-			return f
+			return p
 		}
 	}
-	return nil
+	return ""
 }
 
 // LookupUnqualifiedType searches for a type across all packages and returns the first match along with qualified name
@@ -249,33 +368,31 @@ func (idx *TypeIndex) LookupUnqualifiedType(typeName string) (*ast.TypeSpec, str
 	}
 
 	// Collect candidate packages that define this type. Map iteration order
-	// is nondeterministic, so we gather and sort package names to be stable.
+	// is nondeterministic, so we gather and sort import paths to be stable.
 	var candidates []string
-	for pkgName, pkgTypes := range idx.types {
+	for importPath, pkgTypes := range idx.types {
 		if _, exists := pkgTypes[typeName]; exists {
-			candidates = append(candidates, pkgName)
+			candidates = append(candidates, importPath)
 		}
 	}
 	if len(candidates) == 0 {
 		return nil, ""
 	}
 
-	sort.Strings(candidates)
+	importPath := idx.preferInternal(candidates)
+	return idx.types[importPath][typeName], idx.typeID(importPath, typeName)
+}
 
-	// Prefer internal (non-external) packages over external ones.
-	for _, pkgName := range candidates {
-		if !idx.isExternalPackage(pkgName) {
-			typeSpec := idx.types[pkgName][typeName]
-			qualifiedName := idx.getQualifiedTypeName(pkgName, typeName)
-			return typeSpec, qualifiedName
+// preferInternal picks the first project package from candidates, falling back
+// to the first external one. Candidates are sorted for determinism.
+func (idx *TypeIndex) preferInternal(candidates []string) string {
+	sort.Strings(candidates)
+	for _, importPath := range candidates {
+		if !idx.isExternalPackage(importPath) {
+			return importPath
 		}
 	}
-
-	// No internal candidate found; return the first external candidate (deterministic due to sorting)
-	pkgName := candidates[0]
-	typeSpec := idx.types[pkgName][typeName]
-	qualifiedName := idx.getQualifiedTypeName(pkgName, typeName)
-	return typeSpec, qualifiedName
+	return candidates[0]
 }
 
 // GetQualifiedTypeName returns the appropriate qualified name for a type
@@ -294,6 +411,295 @@ func (idx *TypeIndex) GetQualifiedTypeName(typeName string) string {
 	return typeName
 }
 
+// typeID returns the schema identifier for a type. It is the short "pkg.Type"
+// form unless another indexed package with the same name also declares
+// typeName, in which case the full "import/path.Type" keeps the two apart.
+// Final component names are derived later by the generator's ModelNameFunc.
+func (idx *TypeIndex) typeID(importPath, typeName string) string {
+	pkg := idx.packageName(importPath)
+	for _, other := range idx.packagesByName[pkg] {
+		if other != importPath && idx.types[other][typeName] != nil {
+			return importPath + "." + typeName
+		}
+	}
+	return pkg + "." + typeName
+}
+
+// resolveID maps a schema identifier (short or import-path qualified) back to
+// the import path and type name it denotes. The import path is "" when the
+// identifier names no indexed package.
+func (idx *TypeIndex) resolveID(id string) (string, string) {
+	prefix, typeName := splitQualifiedName(strings.TrimLeft(id, "*"))
+	if prefix == "" {
+		return "", typeName
+	}
+	if _, ok := idx.types[prefix]; ok {
+		return prefix, typeName
+	}
+	return idx.importPathForPackageName(prefix, typeName), typeName
+}
+
+// schemaIdentity reports the package name, import path and type name behind a
+// schema identifier, for naming components. Identifiers that do not resolve to
+// an indexed package (external known types, unknown names) keep their prefix
+// as the package name and an empty import path.
+func (idx *TypeIndex) schemaIdentity(id string) (pkg, importPath, typeName string) {
+	prefix, typeName := splitQualifiedName(id)
+	if idx == nil || prefix == "" {
+		return prefix, "", typeName
+	}
+	if _, ok := idx.types[prefix]; ok || strings.Contains(prefix, "/") {
+		return idx.packageName(prefix), prefix, typeName
+	}
+	return prefix, idx.importPathForPackageName(prefix, typeName), typeName
+}
+
+// importPathForPackageName finds the indexed package named pkg that declares
+// typeName, preferring project packages when several do.
+func (idx *TypeIndex) importPathForPackageName(pkg, typeName string) string {
+	var candidates []string
+	for _, importPath := range idx.packagesByName[pkg] {
+		if idx.types[importPath][typeName] != nil {
+			candidates = append(candidates, importPath)
+		}
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+	return idx.preferInternal(candidates)
+}
+
+// packageName returns the declared name of the package at importPath, or a
+// best guess from the path when the package has not been indexed.
+func (idx *TypeIndex) packageName(importPath string) string {
+	if name, ok := idx.packageNames[importPath]; ok {
+		return name
+	}
+	return guessPackageName(importPath)
+}
+
+// guessPackageName derives the conventional package name from an import path,
+// skipping major-version suffixes ("github.com/jackc/pgx/v5" -> "pgx",
+// "gopkg.in/yaml.v3" -> "yaml").
+func guessPackageName(importPath string) string {
+	base := path.Base(importPath)
+	if isMajorVersionSuffix(base) {
+		if parent := path.Dir(importPath); parent != "." && parent != "/" {
+			base = path.Base(parent)
+		}
+	}
+	if dot := strings.Index(base, ".v"); dot > 0 && isMajorVersionSuffix(base[dot+1:]) {
+		base = base[:dot]
+	}
+	return strings.ReplaceAll(base, "-", "")
+}
+
+func isMajorVersionSuffix(s string) bool {
+	if len(s) < 2 || s[0] != 'v' {
+		return false
+	}
+	for _, r := range s[1:] {
+		if !isASCIIDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// fileImportPath returns the import path bound to alias in the given file.
+// Unaliased imports are matched on the package's declared name once it has
+// been indexed, covering packages whose name differs from their path.
+func (idx *TypeIndex) fileImportPath(filePath, alias string) string {
+	key := idx.fileKey(filePath)
+	if key == "" {
+		return ""
+	}
+	imports := idx.fileImports[key]
+	if importPath, ok := imports[alias]; ok {
+		return importPath
+	}
+	for _, importPath := range imports {
+		if name, ok := idx.packageNames[importPath]; ok && name == alias {
+			return importPath
+		}
+	}
+	return ""
+}
+
+// filePackage returns the import path of the package declaring filePath.
+func (idx *TypeIndex) filePackage(filePath string) string {
+	if key := idx.fileKey(filePath); key != "" {
+		return idx.filePackages[key]
+	}
+	return ""
+}
+
+// resolveQualified resolves a selector such as `models.Item`, as written in
+// fromFile ("" when there is no source context), to a schema identifier.
+// The file's own import table wins; package names are used only as a fallback,
+// and packages outside the project are loaded on demand.
+func (idx *TypeIndex) resolveQualified(alias, typeName, fromFile string) string {
+	var importPath string
+	switch {
+	case idx.types[alias] != nil || strings.Contains(alias, "/"):
+		importPath = alias
+	case fromFile != "" && idx.fileImportPath(fromFile, alias) != "":
+		importPath = idx.fileImportPath(fromFile, alias)
+	default:
+		importPath = idx.importPathForPackageName(alias, typeName)
+	}
+
+	if importPath == "" {
+		if idx.isExternalKnown(alias, alias, typeName) {
+			return alias + "." + typeName
+		}
+		// No source context names the package; try every import bound to the alias.
+		for _, candidate := range idx.importPathsForAlias(alias) {
+			if idx.loadExternalPackage(candidate) && idx.types[candidate][typeName] != nil {
+				importPath = candidate
+				break
+			}
+		}
+		if importPath == "" {
+			return alias + "." + typeName
+		}
+	}
+
+	if idx.types[importPath] == nil {
+		// Mappings keyed by full import path keep that identity, so the
+		// mapping is found again without the package being indexed.
+		full := importPath + "." + typeName
+		if _, ok := idx.externalKnownTypes[full]; ok {
+			return full
+		}
+		if _, ok := encodingJSONTypes[full]; ok {
+			return full
+		}
+		if !idx.isExternalKnown(importPath, idx.packageName(importPath), typeName) {
+			_ = idx.loadExternalPackage(importPath)
+		}
+	}
+	return idx.typeID(importPath, typeName)
+}
+
+// importPathsForAlias collects, in sorted order, every import path bound to
+// alias by any indexed file.
+func (idx *TypeIndex) importPathsForAlias(alias string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, imports := range idx.fileImports {
+		if importPath, ok := imports[alias]; ok && !seen[importPath] {
+			seen[importPath] = true
+			out = append(out, importPath)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// maxTypeChain bounds alias and defined-type chain walks. Valid Go cannot
+// declare cycles through them; the limit only guards malformed sources.
+const maxTypeChain = 32
+
+// resolveAlias follows alias declarations (type A = B) from id to the named
+// type they denote, so aliases never become components of their own. An alias
+// of a predeclared or composite type is returned unchanged; GenerateSchema
+// inlines its right-hand side instead.
+func (idx *TypeIndex) resolveAlias(id string) string {
+	for range maxTypeChain {
+		ts := idx.LookupQualifiedType(id)
+		if ts == nil || !ts.Assign.IsValid() {
+			return id
+		}
+		target := idx.namedTarget(id, ts)
+		if target == "" {
+			return id
+		}
+		id = target
+	}
+	return id
+}
+
+// namedTarget returns the identifier of the named type on the right-hand
+// side of ts (type A B or type A = B), resolved in the package and file that
+// declare it. It returns "" for predeclared and composite types.
+func (idx *TypeIndex) namedTarget(id string, ts *ast.TypeSpec) string {
+	importPath, _ := idx.resolveID(id)
+	switch t := ts.Type.(type) {
+	case *ast.Ident:
+		if idx.types[importPath][t.Name] != nil {
+			return idx.typeID(importPath, t.Name)
+		}
+	case *ast.SelectorExpr:
+		if pkg, ok := t.X.(*ast.Ident); ok {
+			return idx.resolveQualified(pkg.Name, t.Sel.Name, idx.specFiles[ts])
+		}
+	}
+	return ""
+}
+
+// underlyingBasicType follows alias and defined-type chains from id and
+// returns the predeclared type they bottom out in ("string" for
+// type Status Code where type Code string), or "" when the chain ends in a
+// struct, collection or unknown type.
+func (idx *TypeIndex) underlyingBasicType(id string) string {
+	for range maxTypeChain {
+		ts := idx.LookupQualifiedType(id)
+		if ts == nil {
+			return ""
+		}
+		target := idx.namedTarget(id, ts)
+		if target == "" {
+			if ident, ok := ts.Type.(*ast.Ident); ok {
+				return ident.Name
+			}
+			return ""
+		}
+		id = target
+	}
+	return ""
+}
+
+// isExternalKnown reports whether a type has a configured external mapping
+// under either its full import path or its short package-qualified name, or
+// a built-in encoding/json schema.
+func (idx *TypeIndex) isExternalKnown(importPath, pkg, typeName string) bool {
+	if _, ok := encodingJSONTypes[importPath+"."+typeName]; ok {
+		return true
+	}
+	_, ok := idx.externalKnownType(importPath + "." + typeName)
+	if !ok {
+		_, ok = idx.externalKnownType(pkg + "." + typeName)
+	}
+	return ok
+}
+
+// externalKnownType looks up a configured external mapping for a schema
+// identifier. Mappings may be keyed by the short "pkg.Type" name or by the
+// full "import/path.Type" name; both spellings of the identifier are tried.
+// A leading "*" is preserved so pointer-specific mappings stay distinct.
+func (idx *TypeIndex) externalKnownType(id string) (*Schema, bool) {
+	if idx == nil {
+		return nil, false
+	}
+	if schema, ok := idx.externalKnownTypes[id]; ok {
+		return schema, true
+	}
+
+	base := strings.TrimLeft(id, "*")
+	pointer := id[:len(id)-len(base)]
+	pkg, importPath, typeName := idx.schemaIdentity(base)
+	if importPath == "" {
+		return nil, false
+	}
+	for _, key := range []string{importPath + "." + typeName, pkg + "." + typeName} {
+		if schema, ok := idx.externalKnownTypes[pointer+key]; ok {
+			return schema, true
+		}
+	}
+	return nil, false
+}
+
 func AddExternalKnownType(name string, schema *Schema) {
 	ensureTypeIndex() // Ensure typeIndex is initialized
 	if typeIndex == nil {
@@ -351,18 +757,41 @@ func (idx *TypeIndex) AddExternalKnownTypes(schemas map[string]*Schema) {
 	}
 }
 
-func (idx *TypeIndex) inferMarshalerSchema(qualifiedName string) *Schema {
+// addMissingExternalTypes copies mappings for types that neither have a
+// configured mapping nor are declared in the project, so that explicit
+// configuration and parsed source always win.
+func (idx *TypeIndex) addMissingExternalTypes(schemas map[string]*Schema) {
+	if idx == nil {
+		return
+	}
+	missing := make(map[string]*Schema)
+	for name, schema := range schemas {
+		if _, ok := idx.externalKnownType(name); ok {
+			continue
+		}
+		if idx.LookupQualifiedType(strings.TrimLeft(name, "*")) != nil {
+			continue
+		}
+		missing[name] = cloneSchema(schema)
+	}
+	idx.AddExternalKnownTypes(missing)
+}
+
+// inferMarshalerSchema describes a type with JSON or text marshaling
+// methods. mapType maps the predeclared type it is defined over; nil uses
+// mapGoTypeToOpenAPI.
+func (idx *TypeIndex) inferMarshalerSchema(qualifiedName string, mapType func(string) (string, string)) *Schema {
 	if idx == nil {
 		return nil
 	}
 
 	baseQualified := strings.TrimLeft(qualifiedName, "*")
-	alias := externalPackageAlias(baseQualified)
-	if alias != "" {
-		_ = idx.loadExternalPackage(alias)
+	importPath, typeName := idx.resolveID(baseQualified)
+	if importPath == "" {
+		return nil
 	}
 
-	hint, ok := idx.typeJSONHints[baseQualified]
+	hint, ok := idx.typeJSONHints[importPath+"."+typeName]
 	if !ok {
 		return nil
 	}
@@ -378,7 +807,7 @@ func (idx *TypeIndex) inferMarshalerSchema(qualifiedName string) *Schema {
 		return s
 	}
 
-	if s := idx.inferPrimitiveAliasSchema(baseQualified); s != nil {
+	if s := idx.inferPrimitiveAliasSchema(baseQualified, mapType); s != nil {
 		if hasType(s, "string") && s.Format == "" {
 			s.Format = inferStringFormat(baseQualified)
 		}
@@ -411,18 +840,11 @@ func marshalerDescription(qualifiedName, mode string) string {
 	}
 }
 
-func (idx *TypeIndex) inferPrimitiveAliasSchema(qualifiedName string) *Schema {
-	ts := idx.LookupQualifiedType(qualifiedName)
-	if ts == nil {
-		return nil
+func (idx *TypeIndex) inferPrimitiveAliasSchema(qualifiedName string, mapType func(string) (string, string)) *Schema {
+	if mapType == nil {
+		mapType = mapGoTypeToOpenAPI
 	}
-
-	ident, ok := ts.Type.(*ast.Ident)
-	if !ok {
-		return nil
-	}
-
-	openapiType, openapiFormat := mapGoTypeToOpenAPI(ident.Name)
+	openapiType, openapiFormat := mapType(idx.underlyingBasicType(qualifiedName))
 	if openapiType == "object" {
 		return nil
 	}
@@ -519,55 +941,24 @@ func isASCIIDigit(r rune) bool {
 	return r >= '0' && r <= '9'
 }
 
-// getQualifiedTypeName creates a qualified type name for indexing.
-// For external packages (like sqlc, pgtype), use the package name as-is.
-// For internal project types, use package.TypeName format.
-func (idx *TypeIndex) getQualifiedTypeName(pkg, typeName string) string {
-	// Check if this is an external/third-party package
-	if idx.isExternalPackage(pkg) {
-		return pkg + "." + typeName
-	}
-
-	// For internal project types, use package.TypeName format
-	return pkg + "." + typeName
-}
-
-// isExternalPackage determines if a package is external/third-party
-func (idx *TypeIndex) isExternalPackage(pkg string) bool {
-	// If an import alias maps to a path outside the current module, treat as external
-	for importPath, alias := range idx.packageImports {
-		if alias == pkg {
-			if modulePath != "" && strings.HasPrefix(importPath, modulePath) {
-				return false
-			}
-			return true
-		}
-	}
-	// Default to internal
-	return false
+// isExternalPackage reports whether importPath was indexed from outside the
+// project, i.e. loaded on demand from the module cache or GOROOT.
+func (idx *TypeIndex) isExternalPackage(importPath string) bool {
+	return idx.externalPkgs[importPath]
 }
 
-// loadExternalPackage finds the source directory for the external package identified by pkgAlias
-// (e.g. "pgtype"), parses its Go files, and indexes the discovered types.
-// Returns true if the package was successfully located and indexed.
-// Each alias is attempted at most once; subsequent calls are no-ops.
-func (idx *TypeIndex) loadExternalPackage(pkgAlias string) bool {
-	if idx.loadedExternalPkgs[pkgAlias] {
+// loadExternalPackage finds the source directory for the package at importPath
+// (e.g. "github.com/jackc/pgx/v5/pgtype"), parses its Go files, and indexes the
+// discovered types. Returns true if the package was successfully located and indexed.
+// Each import path is attempted at most once; subsequent calls are no-ops, and
+// packages already indexed from the project are never reloaded.
+func (idx *TypeIndex) loadExternalPackage(importPath string) bool {
+	if importPath == "" || idx.loadedExternalPkgs[importPath] {
 		return false // already attempted
 	}
-	idx.loadedExternalPkgs[pkgAlias] = true
-
-	// Reverse-lookup: find the full import path for this alias.
-	importPath := ""
-	for ip, alias := range idx.packageImports {
-		if alias == pkgAlias {
-			importPath = ip
-			break
-		}
-	}
-	if importPath == "" {
-		slog.Debug("[annot8] loadExternalPackage: no import path found for alias", "alias", pkgAlias)
-		return false
+	idx.loadedExternalPkgs[importPath] = true
+	if _, indexed := idx.types[importPath]; indexed {
+		return len(idx.types[importPath]) > 0
 	}
 
 	// Ask the Go toolchain for the source directory of this package.
@@ -597,18 +988,19 @@ func (idx *TypeIndex) loadExternalPackage(pkgAlias string) bool {
 		return false
 	}
 
+	idx.externalPkgs[importPath] = true
 	indexed := 0
 	for _, entry := range entries {
 		name := entry.Name()
 		if !entry.IsDir() && strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go") {
-			if indexErr := idx.indexFile(filepath.Join(info.Dir, name)); indexErr == nil {
+			if indexErr := idx.indexFile(filepath.Join(info.Dir, name), importPath); indexErr == nil {
 				indexed++
 			}
 		}
 	}
 
 	slog.Debug("[annot8] loadExternalPackage: indexed external package",
-		"alias", pkgAlias, "importPath", importPath, "dir", info.Dir, "files", indexed)
+		"importPath", importPath, "dir", info.Dir, "files", indexed)
 	return indexed > 0
 }
 
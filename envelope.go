@@ -0,0 +1,143 @@
+package annot8
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// EnvelopeConfig describes the envelope {data} responses are wrapped in
+// (@Success 200 {data} T). The default, DefaultEnvelopeConfig, is
+// {message, data} with a PaginationMeta meta property for slices.
+type EnvelopeConfig struct {
+	// Type names a Go type, as annotations do ("httpx.Response"), whose
+	// schema is the envelope. It takes precedence over Template.
+	Type string
+	// Template is the envelope schema when Type is empty.
+	Template *Schema
+	// DataField names the property that holds the payload.
+	DataField string
+	// MetaField names the property that holds pagination metadata, and Meta
+	// its schema; g.GenerateSchema("pkg.Meta") references a Go type. A nil
+	// Meta keeps the schema the envelope type declares.
+	MetaField string
+	Meta      *Schema
+	// Paginate reports whether the payload type, as written in the
+	// annotation, is paginated and gets the meta property. Nil never adds it.
+	Paginate func(dataType string) bool
+}
+
+// DefaultEnvelopeConfig returns annot8's default response envelope.
+func DefaultEnvelopeConfig() EnvelopeConfig {
+	return EnvelopeConfig{
+		Template: &Schema{
+			Type:       "object",
+			Required:   []string{"message"},
+			Properties: map[string]*Schema{"message": {Type: "string"}},
+		},
+		DataField: "data",
+		MetaField: "meta",
+		Meta:      &Schema{Ref: "#/components/schemas/PaginationMeta"},
+		Paginate:  PaginateSlices,
+	}
+}
+
+// PaginateSlices paginates slice payloads ({data} []T).
+func PaginateSlices(dataType string) bool {
+	return strings.HasPrefix(strings.TrimPrefix(dataType, "*"), "[]")
+}
+
+// SetEnvelopeConfig overrides the envelope {data} responses are wrapped in.
+func (g *Generator) SetEnvelopeConfig(cfg EnvelopeConfig) {
+	g.envelope = cfg
+}
+
+// SetStandardSchemas replaces the components added to every spec, which
+// default to DefaultStandardSchemas. An empty map adds none; the components
+// responses reference (ProblemDetails, the envelope's Meta) must then come
+// from elsewhere.
+func (g *Generator) SetStandardSchemas(schemas map[string]Schema) {
+	g.standardSchemas = schemas
+}
+
+// DefaultStandardSchemas returns the ProblemDetails component failures
+// reference and the PaginationMeta component the default envelope does.
+func DefaultStandardSchemas() map[string]Schema {
+	return map[string]Schema{
+		"ProblemDetails": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"type":     {Type: "string", Description: "A URI reference identifying the problem type"},
+				"title":    {Type: "string", Description: "A short, human-readable summary of the problem"},
+				"status":   {Type: "integer", Description: "The HTTP status code"},
+				"detail":   {Type: "string", Description: "Detailed explanation of the problem"},
+				"instance": {Type: "string", Description: "A URI reference identifying the specific instance of the problem"},
+			},
+			Required: []string{"type", "title", "status"},
+		},
+		"PaginationMeta": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"has_next":              {Type: "boolean"},
+				"next_after_id":         {Type: "string", Description: "Opaque ID for pagination"},
+				"next_after_created_at": {Type: "string", Format: "date-time"},
+				"limit":                 {Type: "integer"},
+				"records":               {Type: "integer"},
+			},
+		},
+	}
+}
+
+// envelopeSchema wraps the payload schema of a {data} response of dataType.
+func (g *Generator) envelopeSchema(payload *Schema, dataType string) *Schema {
+	cfg := g.envelope
+	envelope := g.envelopeTemplate()
+	if envelope.Properties == nil {
+		envelope.Properties = make(map[string]*Schema)
+	}
+
+	dataField := cfg.DataField
+	if dataField == "" {
+		dataField = "data"
+	}
+	envelope.Properties[dataField] = payload
+
+	if cfg.MetaField == "" {
+		return envelope
+	}
+	switch {
+	case cfg.Paginate == nil || !cfg.Paginate(dataType):
+		// Unpaginated payloads carry no metadata, whatever the template declares.
+		delete(envelope.Properties, cfg.MetaField)
+		envelope.Required = removeString(slices.Clone(envelope.Required), cfg.MetaField)
+	case cfg.Meta != nil:
+		envelope.Properties[cfg.MetaField] = cloneSchema(cfg.Meta)
+	}
+	return envelope
+}
+
+// envelopeTemplate returns a copy of the envelope schema. A Go type's
+// component is inlined, since each response fills its payload slot with a
+// different schema.
+func (g *Generator) envelopeTemplate() *Schema {
+	cfg := g.envelope
+	if cfg.Type == "" {
+		if cfg.Template == nil {
+			return &Schema{Type: "object"}
+		}
+		return cloneSchema(cfg.Template)
+	}
+
+	schema := g.schemaGen.GenerateSchema(cfg.Type)
+	if id, ok := strings.CutPrefix(schema.Ref, schemaRefPrefix); ok {
+		g.schemaGen.mutex.Lock()
+		component := g.schemaGen.schemas[id]
+		g.schemaGen.mutex.Unlock()
+		if component == nil {
+			slog.Warn("[annot8] envelopeTemplate: envelope type has no schema", "type", cfg.Type)
+			return &Schema{Type: "object"}
+		}
+		schema = component
+	}
+	return cloneSchema(schema)
+}
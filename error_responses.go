@@ -0,0 +1,184 @@
+package annot8
+
+import (
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DefaultErrorResponse documents an error response the policy adds to
+// operations.
+type DefaultErrorResponse struct {
+	Description string  // defaults to the status text
+	MediaType   string  // defaults to application/problem+json
+	Schema      *Schema // defaults to the ProblemDetails component
+	// Name is the components.responses entry the response is documented as
+	// when the policy uses component references; it defaults to the status
+	// text without spaces (NotFound).
+	Name string
+}
+
+// ErrorResponsePolicy controls the error responses added to operations
+// beside those declared with @Failure. Each code is added only where it can
+// occur:
+//
+//   - 401 and 403 when the operation has security
+//   - 404 when the route has path parameters
+//   - 400 and 422 when the operation has parameters or a request body
+//   - 429 when a rate-limit middleware (RateLimitMiddleware, RateLimiters)
+//     guards the route
+//   - any other code (500) everywhere
+type ErrorResponsePolicy struct {
+	// Disabled adds no error responses; only @Failure ones are documented.
+	Disabled bool
+	// Responses lists the codes the policy may add and how each is documented.
+	Responses map[int]DefaultErrorResponse
+	// ComponentRefs documents each response once under components.responses
+	// and references it from operations.
+	ComponentRefs bool
+	// RateLimiters lists the rate-limit middlewares, as function values: the
+	// middleware itself or the constructor whose closures it is
+	// (middleware.ThrottleWithOpts, httprate.Limit). Middlewares wrapped
+	// with RateLimitMiddleware need no entry.
+	RateLimiters []any
+	// RateLimitContains opts into matching middleware runtime names by
+	// substring, case-insensitively. Names change with renames and wrappers,
+	// so prefer RateLimiters.
+	RateLimitContains []string
+}
+
+// DefaultErrorResponsePolicy returns annot8's default error responses:
+// 400, 401, 403, 404, 429 and 500 as ProblemDetails.
+func DefaultErrorResponsePolicy() ErrorResponsePolicy {
+	return ErrorResponsePolicy{
+		Responses: map[int]DefaultErrorResponse{
+			http.StatusBadRequest:          {},
+			http.StatusUnauthorized:        {},
+			http.StatusForbidden:           {},
+			http.StatusNotFound:            {},
+			http.StatusTooManyRequests:     {},
+			http.StatusInternalServerError: {},
+		},
+	}
+}
+
+// SetErrorResponsePolicy overrides which error responses operations get
+// beside their @Failure annotations.
+func (g *Generator) SetErrorResponsePolicy(policy ErrorResponsePolicy) {
+	g.errorPolicy = policy
+}
+
+// addDefaultErrorResponses adds the policy's error responses that apply to
+// op and that it does not already declare.
+func (g *Generator) addDefaultErrorResponses(op *Operation, middlewares []func(http.Handler) http.Handler) {
+	policy := g.errorPolicy
+	if policy.Disabled {
+		return
+	}
+	for code, def := range policy.Responses {
+		key := strconv.Itoa(code)
+		if _, exists := op.Responses[key]; exists || !policy.applies(code, op, middlewares) {
+			continue
+		}
+		if policy.ComponentRefs {
+			op.Responses[key] = Response{Ref: "#/components/responses/" + def.name(code)}
+			continue
+		}
+		op.Responses[key] = def.response(code)
+	}
+}
+
+// applies reports whether code can occur for op.
+func (p ErrorResponsePolicy) applies(code int, op *Operation, middlewares []func(http.Handler) http.Handler) bool {
+	switch code {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return requiresSecurity(op.Security)
+	case http.StatusNotFound:
+		return slices.ContainsFunc(op.Parameters, func(param Parameter) bool { return param.In == "path" })
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return len(op.Parameters) > 0 || op.RequestBody != nil
+	case http.StatusTooManyRequests:
+		return slices.ContainsFunc(middlewares, p.rateLimited)
+	}
+	return true
+}
+
+func (p ErrorResponsePolicy) rateLimited(mw func(http.Handler) http.Handler) bool {
+	if mw == nil {
+		return false
+	}
+	if reflect.ValueOf(mw).Pointer() == rateLimitMiddlewarePC {
+		return true
+	}
+	name := middlewareRuntimeName(mw)
+	if slices.ContainsFunc(p.RateLimiters, func(target any) bool { return middlewareIdentifiedBy(name, target) }) {
+		return true
+	}
+	lower := strings.ToLower(name)
+	return slices.ContainsFunc(p.RateLimitContains, func(pattern string) bool {
+		return pattern != "" && strings.Contains(lower, strings.ToLower(pattern))
+	})
+}
+
+// RateLimitMiddleware returns mw marked as rate limiting, so that the error
+// response policy documents 429 on the routes it guards. The result behaves
+// exactly as mw.
+//
+//	r.With(annot8.RateLimitMiddleware(middleware.Throttle(100))).Get("/search", search)
+func RateLimitMiddleware(mw func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return mw(next)
+	}
+}
+
+// rateLimitMiddlewarePC identifies the closures RateLimitMiddleware returns.
+var rateLimitMiddlewarePC = reflect.ValueOf(RateLimitMiddleware(nil)).Pointer()
+
+// addErrorResponseComponents documents the policy responses operations
+// reference under components.responses.
+func (g *Generator) addErrorResponseComponents(spec *Spec) {
+	if !g.errorPolicy.ComponentRefs || g.errorPolicy.Disabled {
+		return
+	}
+	used := make(map[string]bool)
+	for _, entry := range collectOperations(spec) {
+		for _, resp := range entry.op.Responses {
+			if name, ok := strings.CutPrefix(resp.Ref, "#/components/responses/"); ok {
+				used[name] = true
+			}
+		}
+	}
+	for code, def := range g.errorPolicy.Responses {
+		if name := def.name(code); used[name] {
+			spec.Components.Responses[name] = def.response(code)
+		}
+	}
+}
+
+func (d DefaultErrorResponse) name(code int) string {
+	if d.Name != "" {
+		return d.Name
+	}
+	return strings.ReplaceAll(http.StatusText(code), " ", "")
+}
+
+func (d DefaultErrorResponse) response(code int) Response {
+	description := d.Description
+	if description == "" {
+		description = http.StatusText(code)
+	}
+	mediaType := d.MediaType
+	if mediaType == "" {
+		mediaType = "application/problem+json"
+	}
+	schema := cloneSchema(d.Schema)
+	if schema == nil {
+		schema = &Schema{Ref: "#/components/schemas/ProblemDetails"}
+	}
+	return Response{
+		Description: description,
+		Content:     map[string]MediaTypeObject{mediaType: {Schema: schema}},
+	}
+}
@@ -19,6 +19,16 @@ type Generator struct {
 	aclSlugMap    map[string]string
 	modelNameFunc ModelNameFunc
 	securityCfg   SecurityInferenceConfig
+
+	schemaVariants SchemaVariants
+	wildcardParam  string
+
+	envelope        EnvelopeConfig
+	standardSchemas map[string]Schema
+	errorPolicy     ErrorResponsePolicy
+
+	tagStrategy TagStrategy
+	tagGroups   TagGroups
 }
 
 // ModelNameFunc defines a strategy for converting Go package and type names into OpenAPI model names.
@@ -42,6 +52,10 @@ func NewGeneratorWithCache(typeIndex *TypeIndex) *Generator {
 		handlerCache:  make(map[uintptr]*HandlerInfo),
 		modelNameFunc: DefaultModelNameFunc,
 		securityCfg:   DefaultSecurityInferenceConfig(),
+
+		envelope:        DefaultEnvelopeConfig(),
+		standardSchemas: DefaultStandardSchemas(),
+		errorPolicy:     DefaultErrorResponsePolicy(),
 	}
 }
 
@@ -61,6 +75,55 @@ func (g *Generator) SetSecurityInferenceConfig(cfg SecurityInferenceConfig) {
 	g.securityCfg = cfg
 }
 
+// SetEmbeddedStructMode selects how embedded struct fields are documented.
+// The default, EmbedFlatten, mirrors encoding/json; EmbedAllOf keeps each
+// embedded type as an allOf member.
+func (g *Generator) SetEmbeddedStructMode(mode EmbeddedStructMode) {
+	g.schemaGen.SetEmbeddedStructMode(mode)
+}
+
+// SetInt64Representation selects how int64 and uint64 values are documented.
+// The default, Int64AsString, documents them as strings; Int64AsInteger
+// documents the JSON numbers encoding/json emits. Fields with the ",string"
+// json option are documented as strings either way.
+func (g *Generator) SetInt64Representation(rep Int64Representation) {
+	g.schemaGen.SetInt64Representation(rep)
+}
+
+// SetRequiredPolicy selects what drives the required list of object schemas.
+// The default, RequiredFromJSON, follows pointers and omitempty;
+// RequiredFromValidate follows validate:"required".
+func (g *Generator) SetRequiredPolicy(policy RequiredPolicy) {
+	g.schemaGen.SetRequiredPolicy(policy)
+}
+
+// SetClosedObjects documents struct schemas with additionalProperties (or,
+// for allOf composition, unevaluatedProperties) set to false.
+func (g *Generator) SetClosedObjects(closed bool) {
+	g.schemaGen.SetClosedObjects(closed)
+}
+
+// SetNullabilityStrategy selects how nullable values are documented. The
+// default, NullTypeArray, uses type arrays; NullAnyOf suits tools that only
+// understand anyOf.
+func (g *Generator) SetNullabilityStrategy(strategy NullabilityStrategy) {
+	g.schemaGen.SetNullabilityStrategy(strategy)
+}
+
+// SetWildcardParam names the path parameter that documents chi catch-all
+// routes: /static/* becomes /static/{name}. The default is
+// DefaultWildcardParam.
+func (g *Generator) SetWildcardParam(name string) {
+	g.wildcardParam = name
+}
+
+func (g *Generator) wildcardParamName() string {
+	if g.wildcardParam == "" {
+		return DefaultWildcardParam
+	}
+	return g.wildcardParam
+}
+
 // GenerateSchema manually adds a type to the internal schema generator.
 // This is useful for including types that are not automatically discovered via routes.
 func (g *Generator) GenerateSchema(typeName string) *Schema {
@@ -79,6 +142,10 @@ func (g *Generator) GenerateSpec(router chi.Router, cfg Config) Spec {
 	if cfg.SecurityInference != nil {
 		securityCfg = *cfg.SecurityInference
 	}
+	if _, declared := cfg.SecuritySchemes[securityCfg.ACLScheme]; securityCfg.EnableACLBearerFromPermissions &&
+		securityCfg.ACLScheme != "" && !declared {
+		slog.Warn("[annot8] GenerateSpec: ACL fallback scheme is not declared in Config.SecuritySchemes", "scheme", securityCfg.ACLScheme)
+	}
 
 	spec := Spec{
 		OpenAPI:           "3.1.0",
@@ -118,40 +185,31 @@ func (g *Generator) GenerateSpec(router chi.Router, cfg Config) Spec {
 		}
 	}
 
-	spec.Components.SecuritySchemes["BearerAuth"] = SecurityScheme{
-		Type:         "http",
-		Scheme:       "bearer",
-		BearerFormat: "JWT",
-		Description:  "JWT token authentication",
-	}
-	spec.Components.SecuritySchemes["RefreshTokenCookieAuth"] = SecurityScheme{
-		Type:        "apiKey",
-		Name:        "refresh_token",
-		In:          "cookie",
-		Description: "Refresh token passed in HttpOnly cookie",
-	}
-	spec.Components.SecuritySchemes["TerminalTokenAuth"] = SecurityScheme{
-		Type:        "apiKey",
-		Name:        "X-Terminal-Token",
-		In:          "header",
-		Description: "Terminal token passed in X-Terminal-Token header",
-	}
-
+	declareSecuritySchemes(&spec, cfg)
 	g.addStandardSchemas(&spec)
 
 	tags := make(map[string]bool)
+	grouper := newTagGrouper(g.tagGroups)
 	routes, err := DiscoverRoutes(router)
 	if err != nil {
 		slog.Warn("[annot8] GenerateSpec: InspectRoutes error", "error", err)
 	}
 
 	for _, ri := range routes {
+		if ri.Doc != nil && !ri.Doc.documents(ri.Method) {
+			continue
+		}
 		method := ri.Method
 		route := ri.Pattern
 		handler := ri.HandlerFunc
-		pathKey := convertRouteToOpenAPIPath(route)
+		pathKey := convertRouteToOpenAPIPath(route, g.wildcardParamName())
 
-		operation := g.buildOperation(handler, route, method, ri.Middlewares, securityCfg)
+		operation := g.buildOperation(handler, route, method, ri.Middlewares, ri.Doc, securityCfg)
+		tagCtx := TagContext{Method: method, Route: route, Mount: ri.Mount, Handler: ri.HandlerName}
+		if len(operation.Tags) == 0 {
+			operation.Tags = g.operationTags(tagCtx)
+		}
+		grouper.observe(tagCtx, operation.Tags)
 
 		pathItem := spec.Paths[pathKey]
 		switch strings.ToUpper(method) {
@@ -179,39 +237,31 @@ func (g *Generator) GenerateSpec(router chi.Router, cfg Config) Spec {
 		}
 	}
 
+	// Middleware rules match by identity only; surface auth middleware that
+	// name matching used to pick up and no rule or wrapper identifies now.
+	for _, unmatched := range UnmatchedSecurityMiddleware(&spec) {
+		slog.Warn("[annot8] GenerateSpec: unmatched security middleware", "report", unmatched)
+	}
+
 	spec.Tags = g.buildTags(tags)
+	spec.TagGroups = grouper.groups(spec.Tags)
+	g.addErrorResponseComponents(&spec)
+	pruneSecuritySchemes(&spec)
 
 	// Post-process schemas to apply the naming strategy and resolve conflicts
 	g.finalizeSchemas(&spec)
+	spec.schemaDiagnostics = g.schemaGen.Diagnostics()
 
 	slog.Debug("[annot8] GenerateSpec: completed", "path_count", len(spec.Paths))
 	return spec
 }
 
 // addStandardSchemas seeds portable schemas used by generated responses.
-// Applications may use their own problem types in @Failure annotations.
+// Applications may use their own problem types in @Failure annotations, or
+// replace these with SetStandardSchemas.
 func (g *Generator) addStandardSchemas(spec *Spec) {
-	spec.Components.Schemas["ProblemDetails"] = Schema{
-		Type: "object",
-		Properties: map[string]*Schema{
-			"type":     {Type: "string", Description: "A URI reference identifying the problem type"},
-			"title":    {Type: "string", Description: "A short, human-readable summary of the problem"},
-			"status":   {Type: "integer", Description: "The HTTP status code"},
-			"detail":   {Type: "string", Description: "Detailed explanation of the problem"},
-			"instance": {Type: "string", Description: "A URI reference identifying the specific instance of the problem"},
-		},
-		Required: []string{"type", "title", "status"},
-	}
-
-	spec.Components.Schemas["PaginationMeta"] = Schema{
-		Type: "object",
-		Properties: map[string]*Schema{
-			"has_next":              {Type: "boolean"},
-			"next_after_id":         {Type: "string", Description: "Opaque ID for pagination"},
-			"next_after_created_at": {Type: "string", Format: "date-time"},
-			"limit":                 {Type: "integer"},
-			"records":               {Type: "integer"},
-		},
+	for name, schema := range g.standardSchemas {
+		spec.Components.Schemas[name] = *cloneSchema(&schema)
 	}
 }
 
@@ -233,9 +283,9 @@ func (g *Generator) finalizeSchemas(spec *Spec) {
 	}
 	sort.Strings(internalIDs)
 
+	proposed := g.proposeSchemaNames(internalIDs)
 	for _, id := range internalIDs {
-		pkg, name := splitQualifiedName(id)
-		final := g.modelNameFunc(pkg, name)
+		final := proposed[id]
 
 		if count, exists := usedNames[final]; exists {
 			usedNames[final] = count + 1
@@ -263,6 +313,103 @@ func (g *Generator) finalizeSchemas(spec *Spec) {
 	g.updateRefs(spec, refMapping)
 }
 
+// proposeSchemaNames runs the naming strategy over the short package name of
+// every schema. Names claimed by types from different import paths (two
+// "models" packages both declaring Item) are derived again with just enough
+// of each import path to tell them apart, still through the naming strategy.
+// Anything that collides after that is left to numeric suffixing.
+func (g *Generator) proposeSchemaNames(ids []string) map[string]string {
+	type identity struct{ pkg, importPath, name string }
+
+	identities := make(map[string]identity, len(ids))
+	proposed := make(map[string]string, len(ids))
+	claims := make(map[string][]string)
+	var variants []string
+	for _, id := range ids {
+		if _, _, ok := g.schemaGen.variantBase(id); ok {
+			variants = append(variants, id)
+			continue
+		}
+		pkg, importPath, name := g.schemaGen.typeIndex.schemaIdentity(id)
+		identities[id] = identity{pkg: pkg, importPath: importPath, name: name}
+		proposed[id] = g.modelNameFunc(pkg, name)
+		claims[proposed[id]] = append(claims[proposed[id]], id)
+	}
+
+	for _, claimants := range claims {
+		if len(claimants) < 2 {
+			continue
+		}
+		paths := make([]string, len(claimants))
+		for i, id := range claimants {
+			paths[i] = identities[id].importPath
+		}
+		qualifiers := disambiguatingQualifiers(paths)
+		if qualifiers == nil {
+			continue
+		}
+		for i, id := range claimants {
+			proposed[id] = g.modelNameFunc(qualifiers[i], identities[id].name)
+		}
+	}
+
+	// Variants are named after their base component: OrderInput, OrderOutput,
+	// OrderXMLOutput. A base that is only used through variants is named as
+	// a standalone component would be.
+	var nameFor func(id string) string
+	nameFor = func(id string) string {
+		if name, ok := proposed[id]; ok {
+			return name
+		}
+		base, suffix, ok := g.schemaGen.variantBase(id)
+		if !ok {
+			pkg, _, name := g.schemaGen.typeIndex.schemaIdentity(id)
+			return g.modelNameFunc(pkg, name)
+		}
+		return nameFor(base) + suffix
+	}
+	for _, id := range variants {
+		proposed[id] = nameFor(id)
+	}
+
+	return proposed
+}
+
+// disambiguatingQualifiers returns, for each import path, the shortest
+// trailing run of path segments (joined with ".") that is unique among paths:
+// ".../orders/models" and ".../users/models" become "orders.models" and
+// "users.models". It returns nil when the paths are unknown or not distinct.
+func disambiguatingQualifiers(paths []string) []string {
+	seen := make(map[string]bool, len(paths))
+	maxSegments := 0
+	for _, p := range paths {
+		if p == "" || seen[p] {
+			return nil
+		}
+		seen[p] = true
+		if n := strings.Count(p, "/") + 1; n > maxSegments {
+			maxSegments = n
+		}
+	}
+
+	for n := 1; n <= maxSegments; n++ {
+		qualifiers := make([]string, len(paths))
+		unique := make(map[string]bool, len(paths))
+		for i, p := range paths {
+			segments := strings.Split(p, "/")
+			if len(segments) > n {
+				segments = segments[len(segments)-n:]
+			}
+			qualifiers[i] = strings.Join(segments, ".")
+			unique[qualifiers[i]] = true
+		}
+		if len(unique) == len(paths) {
+			return qualifiers
+		}
+	}
+	return nil
+}
+
 // splitQualifiedName splits "pkg.Name" into ("pkg", "Name").
 func splitQualifiedName(id string) (string, string) {
 	idx := strings.LastIndex(id, ".")
@@ -281,6 +428,13 @@ func (g *Generator) updateRefs(spec *Spec, mapping map[string]string) {
 		spec.Components.Schemas[name] = s
 	}
 
+	for name := range spec.Components.Responses {
+		resp := spec.Components.Responses[name]
+		for mk := range resp.Content {
+			g.updateSchemaRefs(resp.Content[mk].Schema, mapping)
+		}
+	}
+
 	// Update all paths
 	for path := range spec.Paths {
 		pi := spec.Paths[path]
@@ -328,9 +482,39 @@ func (g *Generator) updateSchemaRefs(s *Schema, mapping map[string]string) {
 		g.updateSchemaRefs(s.Not, mapping)
 	}
 
+	if s.PropertyNames != nil {
+		g.updateSchemaRefs(s.PropertyNames, mapping)
+	}
+
+	for _, sub := range s.PrefixItems {
+		g.updateSchemaRefs(sub, mapping)
+	}
+	for _, subs := range []map[string]*Schema{s.Defs, s.PatternProperties, s.DependentSchemas} {
+		for _, sub := range subs {
+			g.updateSchemaRefs(sub, mapping)
+		}
+	}
+
+	for _, sub := range []*Schema{s.Contains, s.If, s.Then, s.Else} {
+		if sub != nil {
+			g.updateSchemaRefs(sub, mapping)
+		}
+	}
+
+	if s.Discriminator != nil {
+		for value, ref := range s.Discriminator.Mapping {
+			if newRef, ok := mapping[ref]; ok {
+				s.Discriminator.Mapping[value] = newRef
+			}
+		}
+	}
+
 	if ap, ok := s.AdditionalProperties.(*Schema); ok && ap != nil {
 		g.updateSchemaRefs(ap, mapping)
 	}
+	if up, ok := s.UnevaluatedProperties.(*Schema); ok && up != nil {
+		g.updateSchemaRefs(up, mapping)
+	}
 }
 
 func (g *Generator) updatePathItemRefs(pi *PathItem, mapping map[string]string) {
@@ -5,4 +5,5 @@ go 1.25
 require (
 	github.com/MarceloPetrucio/go-scalar-api-reference v0.0.0-20240521013641-ce5d2efe0e06
 	github.com/go-chi/chi/v5 v5.2.4
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -14,27 +14,40 @@ import (
 	"github.com/go-chi/chi/v5"
 )
 
+// GenerateParams configures GenerateOpenAPISpecFile.
 type GenerateParams struct {
-	Router         chi.Router
-	Config         Config
-	FilePath       string
+	Router   chi.Router
+	Config   Config
+	FilePath string
+	// Generator generates the spec with the options set on it (envelope,
+	// error responses, int64 representation, nullability, tags, sqlc config,
+	// ...). Nil uses a default generator.
+	Generator *Generator
+	// RenameFunction, when set, overrides the Generator's model naming for
+	// this file only.
 	RenameFunction ModelNameFunc
-	Validate       bool
+	// Validate fails generation on annotation, operation ID, ambiguous path
+	// and $ref violations. Path parameter, security scheme and schema tag
+	// violations are logged as warnings.
+	Validate bool
+	// StrictValidate makes Validate fail on the violations it otherwise
+	// logs as warnings.
+	StrictValidate bool
 }
 
 // GenerateOpenAPISpecFile generates the OpenAPI spec and writes it to the given file path.
 func GenerateOpenAPISpecFile(p *GenerateParams) error {
 	slog.Debug("[annot8] GenerateOpenAPISpecFile: generating OpenAPI spec", "filePath", p.FilePath)
 
-	ensureTypeIndex()
-
-	renameFunc := p.RenameFunction
-	if renameFunc == nil {
-		renameFunc = DefaultModelNameFunc
+	gen := p.Generator
+	if gen == nil {
+		gen = NewGenerator()
+	}
+	if p.RenameFunction != nil {
+		// The generator may be shared: restore its naming once the file is built.
+		defer gen.SetModelNameFunc(gen.modelNameFunc)
+		gen.SetModelNameFunc(p.RenameFunction)
 	}
-
-	gen := NewGeneratorWithCache(typeIndex)
-	gen.SetModelNameFunc(renameFunc)
 
 	spec := gen.GenerateSpec(p.Router, p.Config)
 
@@ -44,6 +57,18 @@ func GenerateOpenAPISpecFile(p *GenerateParams) error {
 		violations = append(violations, ValidateOperationIDs(&spec)...)
 		violations = append(violations, ValidateAmbiguousPaths(&spec)...)
 		violations = append(violations, ValidateRefs(&spec)...)
+
+		var warnings []string
+		warnings = append(warnings, ValidatePathParameters(&spec)...)
+		warnings = append(warnings, ValidateSecuritySchemes(&spec)...)
+		warnings = append(warnings, ValidateSchemaTags(&spec)...)
+		if p.StrictValidate {
+			violations = append(violations, warnings...)
+		} else {
+			for _, warning := range warnings {
+				slog.Warn("[annot8] GenerateOpenAPISpecFile: validation warning", "violation", warning)
+			}
+		}
 		if len(violations) > 0 {
 			return &ValidationError{Violations: violations}
 		}
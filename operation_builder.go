@@ -5,6 +5,7 @@ import (
 	"go/ast"
 	"log/slog"
 	"net/http"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -12,20 +13,29 @@ import (
 	"unicode/utf8"
 )
 
-// buildOperation turns a Chi route into an OpenAPI operation.
+// buildOperation turns a Chi route into an OpenAPI operation. A code-first
+// doc, when the route has one, stands in for the handler's annotations.
 func (g *Generator) buildOperation(
 	handler http.Handler,
 	route, method string,
 	middlewares []func(http.Handler) http.Handler,
+	doc *OperationDoc,
 	securityCfg SecurityInferenceConfig,
 ) Operation {
 	slog.Debug("[annot8] buildOperation: called", "route", route, "method", method)
 
 	handlerInfo := g.extractHandlerInfo(handler, route)
+	if handlerInfo != nil {
+		// Type names in annotations resolve through the handler file's imports.
+		defer g.schemaGen.enterFile(handlerInfo.File)()
+	}
 
 	var annotations *Annotation
 	var annotationParseErrors []string
-	if handlerInfo != nil && handlerInfo.File != "" {
+	switch {
+	case doc != nil:
+		annotations = doc.annotation()
+	case handlerInfo != nil && handlerInfo.File != "":
 		var err error
 		annotations, err = ParseAnnotations(handlerInfo.File, handlerInfo.FunctionName)
 		if err != nil {
@@ -35,7 +45,7 @@ func (g *Generator) buildOperation(
 	}
 
 	op := Operation{
-		OperationID:           generateOperationID(method, route),
+		OperationID:           generateOperationID(method, route, g.wildcardParamName()),
 		Responses:             g.buildResponses(annotations),
 		routePattern:          route,
 		httpMethod:            strings.ToUpper(method),
@@ -43,7 +53,7 @@ func (g *Generator) buildOperation(
 	}
 
 	// Merge path parameters derived from the route itself.
-	op.Parameters = append(op.Parameters, extractPathParameters(route)...)
+	op.Parameters = append(op.Parameters, extractPathParameters(route, g.wildcardParamName())...)
 
 	// Apply annotation-derived metadata.
 	if annotations != nil {
@@ -67,22 +77,28 @@ func (g *Generator) buildOperation(
 				continue
 			}
 
+			schema := normalizeParameterSchema(param.In, g.schemaGen.GenerateSchema(param.Type))
+			if param.In == "path" {
+				var conflict string
+				schema, conflict = reconcilePathParameter(findParameterSchema(op.Parameters, param.Name, "path"), schema, param.Name)
+				if conflict != "" {
+					slog.Warn("[annot8] buildOperation: "+conflict, "route", route)
+					op.pathParamConflicts = append(op.pathParamConflicts, conflict)
+				}
+			}
+
 			op.Parameters = upsertParameter(op.Parameters, Parameter{
 				Name:        param.Name,
 				In:          param.In,
 				Description: param.Description,
 				Required:    param.Required,
-				Schema:      normalizeParameterSchema(param.In, g.schemaGen.GenerateSchema(param.Type)),
+				Schema:      schema,
 			})
 		}
 
 	}
 
-	if len(op.Tags) == 0 {
-		op.Tags = []string{extractResourceFromRoute(route)}
-	}
-
-	if requestBody := g.buildRequestBody(annotations); requestBody != nil {
+	if requestBody := g.buildRequestBody(annotations, method); requestBody != nil {
 		op.RequestBody = requestBody
 	}
 
@@ -94,15 +110,13 @@ func (g *Generator) buildOperation(
 	// the only way to document authorization enforced inside handler bodies,
 	// which the middleware scan cannot see.
 	if annotations != nil && len(annotations.Security) > 0 {
-		op.Security = nil
-		for _, scheme := range annotations.Security {
-			op.Security = append(op.Security, SecurityRequirement{scheme: {}})
-		}
+		op.Security = annotatedSecurity(op.Security, annotations.Security)
 	}
 
 	if perms := g.resolveACLPermissions(route, method, handlerInfo, middlewares); len(perms) > 0 {
-		if securityCfg.EnableACLBearerFromPermissions && len(op.Security) == 0 && aclPermissionsRequireBearer(perms) {
-			op.Security = []SecurityRequirement{{"BearerAuth": {}}}
+		if securityCfg.EnableACLBearerFromPermissions && securityCfg.ACLScheme != "" &&
+			len(op.Security) == 0 && aclPermissionsRequireBearer(perms) {
+			op.Security = []SecurityRequirement{{securityCfg.ACLScheme: {}}}
 		}
 
 		aclInfo := "\n\nAccess control:\n- " + strings.Join(perms, "\n- ")
@@ -113,6 +127,11 @@ func (g *Generator) buildOperation(
 		}
 	}
 
+	if requiresSecurity(op.Security) {
+		op.unmatchedMiddleware = securityCfg.unmatchedMiddleware(middlewares)
+	}
+
+	g.addDefaultErrorResponses(&op, middlewares)
 	sortOperationParameters(op.Parameters)
 
 	slog.Debug("[annot8] buildOperation: completed", "operationId", op.OperationID)
@@ -175,6 +194,7 @@ func (g *Generator) expandQueryObjectParam(param ParamAnnotation) []Parameter {
 			if !ast.IsExported(fieldName.Name) {
 				continue
 			}
+			restore := g.schemaGen.enterFieldInt64Override(field.Tag)
 			params = append(params, Parameter{
 				Name:        name,
 				In:          "query",
@@ -182,6 +202,7 @@ func (g *Generator) expandQueryObjectParam(param ParamAnnotation) []Parameter {
 				Required:    fieldRequired(field),
 				Schema:      normalizeParameterSchema("query", g.schemaGen.convertFieldType(field.Type)),
 			})
+			restore()
 			break
 		}
 	}
@@ -248,7 +269,9 @@ func normalizeParameterSchema(in string, schema *Schema) *Schema {
 		return schema
 	}
 	copied := cloneSchema(schema)
-	removeNullability(copied)
+	if stripNull(copied) {
+		trimNullableWording(copied)
+	}
 	return copied
 }
 
@@ -287,85 +310,118 @@ func cloneSchema(s *Schema) *Schema {
 	if s.Not != nil {
 		out.Not = cloneSchema(s.Not)
 	}
+	if s.PropertyNames != nil {
+		out.PropertyNames = cloneSchema(s.PropertyNames)
+	}
+	out.Defs = cloneSchemaMap(s.Defs)
+	out.PatternProperties = cloneSchemaMap(s.PatternProperties)
+	out.DependentSchemas = cloneSchemaMap(s.DependentSchemas)
+	out.PrefixItems = cloneSchemaSlice(s.PrefixItems)
+	out.Contains = cloneSchema(s.Contains)
+	out.If, out.Then, out.Else = cloneSchema(s.If), cloneSchema(s.Then), cloneSchema(s.Else)
+	if s.DependentRequired != nil {
+		out.DependentRequired = make(map[string][]string, len(s.DependentRequired))
+		for k, v := range s.DependentRequired {
+			out.DependentRequired[k] = append([]string(nil), v...)
+		}
+	}
+	if s.Discriminator != nil {
+		d := *s.Discriminator
+		if d.Mapping != nil {
+			d.Mapping = make(map[string]string, len(s.Discriminator.Mapping))
+			for k, v := range s.Discriminator.Mapping {
+				d.Mapping[k] = v
+			}
+		}
+		out.Discriminator = &d
+	}
 	if ap, ok := s.AdditionalProperties.(*Schema); ok {
 		out.AdditionalProperties = cloneSchema(ap)
 	}
+	if up, ok := s.UnevaluatedProperties.(*Schema); ok {
+		out.UnevaluatedProperties = cloneSchema(up)
+	}
 	return &out
 }
 
-func removeNullability(s *Schema) {
-	if s == nil {
-		return
+func cloneSchemaMap(m map[string]*Schema) map[string]*Schema {
+	if m == nil {
+		return nil
 	}
+	out := make(map[string]*Schema, len(m))
+	for k, v := range m {
+		out[k] = cloneSchema(v)
+	}
+	return out
+}
 
-	switch t := s.Type.(type) {
-	case []string:
-		filtered := make([]string, 0, len(t))
-		for _, v := range t {
-			if v != "null" {
-				filtered = append(filtered, v)
-			}
-		}
-		switch len(filtered) {
-		case 0:
-			s.Type = nil
-		case 1:
-			s.Type = filtered[0]
-		default:
-			s.Type = filtered
-		}
-	case []any:
-		filtered := make([]any, 0, len(t))
-		for _, v := range t {
-			if str, ok := v.(string); ok && str == "null" {
-				continue
-			}
-			filtered = append(filtered, v)
-		}
-		switch len(filtered) {
-		case 0:
-			s.Type = nil
-		case 1:
-			s.Type = filtered[0]
-		default:
-			s.Type = filtered
-		}
+func cloneSchemaSlice(schemas []*Schema) []*Schema {
+	if schemas == nil {
+		return nil
+	}
+	out := make([]*Schema, 0, len(schemas))
+	for _, v := range schemas {
+		out = append(out, cloneSchema(v))
 	}
+	return out
+}
 
-	if len(s.AnyOf) > 0 {
-		filtered := make([]*Schema, 0, len(s.AnyOf))
-		for _, sub := range s.AnyOf {
-			if sub != nil && sub.Type == "null" {
-				continue
-			}
-			removeNullability(sub)
-			filtered = append(filtered, sub)
-		}
-		if len(filtered) == 1 && s.Ref == "" && s.Type == nil && len(s.Properties) == 0 && s.Items == nil {
-			*s = *filtered[0]
-		} else {
-			s.AnyOf = filtered
-		}
+// successMediaTypes resolves the media types for success responses from
+// @Produce annotations (e.g. "text/event-stream" for SSE endpoints);
+// endpoints without one keep the JSON default.
+func successMediaTypes(annotations *Annotation) []string {
+	if annotations == nil {
+		return declaredMediaTypes(nil)
 	}
+	return declaredMediaTypes(annotations.Produce)
+}
+
+// requestMediaTypes resolves the media types for request bodies from
+// @Accept annotations, defaulting to JSON.
+func requestMediaTypes(annotations *Annotation) []string {
+	if annotations == nil {
+		return declaredMediaTypes(nil)
+	}
+	return declaredMediaTypes(annotations.Accept)
+}
 
-	if strings.HasPrefix(s.Description, "Nullable ") {
-		s.Description = strings.TrimPrefix(s.Description, "Nullable ")
-		if s.Description != "" {
-			runes := []rune(s.Description)
-			runes[0] = unicode.ToUpper(runes[0])
-			s.Description = string(runes)
+// declaredMediaTypes returns the declared media types in order without
+// duplicates, or application/json when none are declared.
+func declaredMediaTypes(declared []string) []string {
+	var out []string
+	for _, mediaType := range declared {
+		if mediaType != "" && !slices.Contains(out, mediaType) {
+			out = append(out, mediaType)
 		}
 	}
+	if len(out) == 0 {
+		return []string{"application/json"}
+	}
+	return out
 }
 
-// successMediaType resolves the media type for success responses. The first
-// @Produce annotation wins (e.g. "text/event-stream" for SSE endpoints);
-// endpoints without one keep the JSON default.
-func successMediaType(annotations *Annotation) string {
-	if annotations != nil && len(annotations.Produce) > 0 && annotations.Produce[0] != "" {
-		return annotations.Produce[0]
+// contentFor builds a content map with one entry per media type. build runs
+// once for JSON-like media types and once, in XML mode, for XML ones, so the
+// same Go type is documented with json or xml member names as appropriate.
+func (g *Generator) contentFor(mediaTypes []string, build func() *Schema) map[string]MediaTypeObject {
+	content := make(map[string]MediaTypeObject, len(mediaTypes))
+	var plain, xml *Schema
+	for _, mediaType := range mediaTypes {
+		if !isXMLMediaType(mediaType) {
+			if plain == nil {
+				plain = build()
+			}
+			content[mediaType] = MediaTypeObject{Schema: plain}
+			continue
+		}
+		if xml == nil {
+			restore := g.schemaGen.enterXMLMode(true)
+			xml = build()
+			restore()
+		}
+		content[mediaType] = MediaTypeObject{Schema: xml}
 	}
-	return "application/json"
+	return content
 }
 
 // buildResponses assembles HTTP responses using annotations as hints.
@@ -382,32 +438,11 @@ func (g *Generator) buildResponses(annotations *Annotation) Responses {
 				continue
 			}
 
-			schema := g.generateResponseSchema(success.DataType)
-			if success.IsWrapped {
-				props := map[string]*Schema{
-					"message": {Type: "string"},
-					"data":    schema,
-				}
-
-				// Only include meta if the data type is a slice (implies pagination).
-				if strings.HasPrefix(strings.TrimPrefix(success.DataType, "*"), "[]") {
-					props["meta"] = &Schema{Ref: "#/components/schemas/PaginationMeta"}
-				}
-
-				schema = &Schema{
-					Type:       "object",
-					Required:   []string{"message"},
-					Properties: props,
-				}
-			}
-
 			responses[statusCode] = Response{
 				Description: success.Description,
-				Content: map[string]MediaTypeObject{
-					successMediaType(annotations): {
-						Schema: schema,
-					},
-				},
+				Content: g.contentFor(successMediaTypes(annotations), func() *Schema {
+					return g.successSchema(success)
+				}),
 			}
 		}
 	} else {
@@ -427,6 +462,9 @@ func (g *Generator) buildResponses(annotations *Annotation) Responses {
 			var failureSchema *Schema
 			if failure.Type != "" {
 				failureSchema = g.schemaGen.GenerateSchema(failure.Type)
+				if variant, ok := g.responseVariant(); ok {
+					failureSchema = g.schemaGen.schemaVariantOf(failureSchema, variant)
+				}
 			} else {
 				failureSchema = &Schema{Ref: "#/components/schemas/ProblemDetails"}
 			}
@@ -441,38 +479,29 @@ func (g *Generator) buildResponses(annotations *Annotation) Responses {
 		}
 	}
 
-	standardErrors := map[string]Response{
-		"400": {Description: "Bad Request", Content: problemJSON()},
-		"401": {Description: "Unauthorized", Content: problemJSON()},
-		"403": {Description: "Forbidden", Content: problemJSON()},
-		"404": {Description: "Not Found", Content: problemJSON()},
-		"500": {Description: "Internal Server Error", Content: problemJSON()},
-	}
-
-	for code, response := range standardErrors {
-		if _, exists := responses[code]; !exists {
-			responses[code] = response
-		}
-	}
-
 	slog.Debug("[annot8] buildResponses: completed", "response_count", len(responses))
 	return responses
 }
 
-func problemJSON() map[string]MediaTypeObject {
-	return map[string]MediaTypeObject{
-		"application/problem+json": {
-			Schema: &Schema{Ref: "#/components/schemas/ProblemDetails"},
-		},
+// successSchema builds the body schema of a success response.
+func (g *Generator) successSchema(success SuccessResponse) *Schema {
+	schema := g.generateResponseSchema(success.DataType)
+	if variant, ok := g.responseVariant(); ok {
+		schema = g.schemaGen.schemaVariantOf(schema, variant)
 	}
+	if !success.IsWrapped {
+		return schema
+	}
+	return g.envelopeSchema(schema, success.DataType)
 }
 
-// buildRequestBody constructs a request body definition.
-func (g *Generator) buildRequestBody(annotations *Annotation) *RequestBody {
+// buildRequestBody constructs a request body definition. With schema
+// variants enabled the body references the variant for method.
+func (g *Generator) buildRequestBody(annotations *Annotation, method string) *RequestBody {
 	slog.Debug("[annot8] buildRequestBody: called")
 
 	var (
-		schema      *Schema
+		bodyType    string
 		description = "Request body"
 		required    bool
 	)
@@ -484,7 +513,7 @@ func (g *Generator) buildRequestBody(annotations *Annotation) *RequestBody {
 			}
 			slog.Debug("[annot8] buildRequestBody: found body parameter", "type", param.Type)
 
-			schema = g.schemaGen.GenerateSchema(param.Type)
+			bodyType = param.Type
 			if param.Description != "" {
 				description = param.Description
 			}
@@ -493,16 +522,20 @@ func (g *Generator) buildRequestBody(annotations *Annotation) *RequestBody {
 		}
 	}
 
-	if schema == nil {
+	if bodyType == "" {
 		return nil
 	}
 
 	return &RequestBody{
 		Description: description,
 		Required:    required,
-		Content: map[string]MediaTypeObject{
-			"application/json": {Schema: schema},
-		},
+		Content: g.contentFor(requestMediaTypes(annotations), func() *Schema {
+			schema := g.schemaGen.GenerateSchema(bodyType)
+			if variant, ok := g.requestVariant(method); ok {
+				schema = g.schemaGen.schemaVariantOf(schema, variant)
+			}
+			return schema
+		}),
 	}
 }
 
@@ -544,18 +577,10 @@ func (g *Generator) buildTags(tagNames map[string]bool) []Tag {
 	return tags
 }
 
-// convertRouteToOpenAPIPath removes regex constraints from Chi-style parameters.
-func convertRouteToOpenAPIPath(route string) string {
-	parts := strings.Split(route, "/")
-	for i, part := range parts {
-		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
-			inner := strings.Trim(part, "{}")
-			if before, _, ok := strings.Cut(inner, ":"); ok {
-				parts[i] = "{" + before + "}"
-			}
-		}
-	}
-	path := strings.Join(parts, "/")
+// convertRouteToOpenAPIPath removes regex constraints from Chi-style parameters
+// and names the catch-all * after wildcard.
+func convertRouteToOpenAPIPath(route, wildcard string) string {
+	path, _ := parseRouteParams(route, wildcard)
 	if path == "" {
 		return "/"
 	}
@@ -568,34 +593,22 @@ func convertRouteToOpenAPIPath(route string) string {
 	return path
 }
 
-// extractPathParameters converts route parameters into OpenAPI parameters.
-func extractPathParameters(route string) []Parameter {
+// extractPathParameters converts route parameters into OpenAPI parameters,
+// typed by their regex constraints.
+func extractPathParameters(route, wildcard string) []Parameter {
 	var params []Parameter
 
-	for _, part := range strings.Split(route, "/") {
-		if !strings.HasPrefix(part, "{") || !strings.HasSuffix(part, "}") {
-			continue
-		}
-
-		paramName := strings.Trim(part, "{}")
-		if colon := strings.Index(paramName, ":"); colon != -1 {
-			paramName = paramName[:colon]
-		}
-
-		params = append(params, Parameter{
-			Name:     paramName,
-			In:       "path",
-			Required: true,
-			Schema:   &Schema{Type: "string"},
-		})
+	_, routeParams := parseRouteParams(route, wildcard)
+	for _, rp := range routeParams {
+		params = append(params, routeParamParameter(rp))
 	}
 
 	return params
 }
 
 // generateOperationID creates a stable operation ID based on method and route.
-func generateOperationID(method, route string) string {
-	normalizedRoute := convertRouteToOpenAPIPath(route)
+func generateOperationID(method, route, wildcard string) string {
+	normalizedRoute := convertRouteToOpenAPIPath(route, wildcard)
 	segments := strings.Split(strings.Trim(normalizedRoute, "/"), "/")
 
 	parts := make([]string, 0, len(segments)+1)
@@ -687,10 +700,8 @@ func splitIdentifierWords(s string) []string {
 
 // extractResourceFromRoute returns the first meaningful route segment.
 func extractResourceFromRoute(route string) string {
-	for _, part := range strings.Split(strings.Trim(route, "/"), "/") {
-		if part != "" && part != "api" && part != "v1" && !strings.Contains(part, "{") {
-			return part
-		}
+	if segments := resourceSegments(route); len(segments) > 0 {
+		return segments[0]
 	}
 	return "default"
 }
@@ -704,6 +715,16 @@ func capitalize(s string) string {
 	return string(unicode.ToUpper(r)) + s[size:]
 }
 
+// findParameterSchema returns the schema of the parameter named name in in.
+func findParameterSchema(params []Parameter, name, in string) *Schema {
+	for _, p := range params {
+		if p.Name == name && p.In == in {
+			return p.Schema
+		}
+	}
+	return nil
+}
+
 // upsertParameter merges a parameter into an existing slice.
 func upsertParameter(params []Parameter, p Parameter) []Parameter {
 	for i, existing := range params {
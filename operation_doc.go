@@ -0,0 +1,193 @@
+package annot8
+
+import (
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// OperationDoc documents a route from code, for handlers without annotated
+// doc comments: http.FileServer, http.StripPrefix, reverse proxies,
+// grpc-gateway muxes and other third-party handlers. Attach it with Describe
+// or the Doc middleware. A registered doc replaces the handler's annotations.
+//
+// Types (Body, ParamDoc.Type, ResponseDoc.Body) are given as a value of the
+// type, such as CreateOrderRequest{} or []Order(nil), or as a type name
+// string written as in annotations ("order.CreateOrderRequest").
+type OperationDoc struct {
+	Summary     string
+	Description string
+	Tags        []string
+	Accept      []string // request media types, as @Accept
+	Produce     []string // success response media types, as @Produce
+	Security    []string // security scheme names, as @Security
+	Params      []ParamDoc
+	Body        any // required request body; nil for none
+	Responses   []ResponseDoc
+
+	// Methods limits a handler chi registers for every method (r.Handle,
+	// r.Mount) to the listed methods; empty documents each method.
+	Methods []string
+}
+
+// ParamDoc documents a path, query, header or cookie parameter.
+type ParamDoc struct {
+	Name        string
+	In          string
+	Type        any // defaults to string
+	Required    bool
+	Description string
+}
+
+// ResponseDoc documents a response. Status codes of 400 and above are
+// documented as failures, which default to ProblemDetails when Body is nil.
+type ResponseDoc struct {
+	Status      int
+	Body        any
+	Description string // defaults to the status text
+	Wrapped     bool   // wrap Body in the {message, data} envelope, as {data}
+}
+
+// describedHandler carries an OperationDoc alongside the handler it documents.
+type describedHandler struct {
+	http.Handler
+	doc OperationDoc
+}
+
+// Describe returns handler documented by doc. Register the returned handler
+// with the router; it serves requests exactly as handler does. Describe the
+// outermost handler (the http.StripPrefix, not the http.FileServer inside
+// it); use Doc for r.Mount, which hides the mounted handler.
+func Describe(handler http.Handler, doc OperationDoc) http.Handler {
+	return &describedHandler{Handler: handler, doc: doc}
+}
+
+// Doc returns a middleware marking the routes it wraps as documented by doc:
+//
+//	r.With(annot8.Doc(annot8.OperationDoc{Summary: "Static assets"})).Mount("/static", fs)
+//
+// A Describe on the handler itself takes precedence.
+func Doc(doc OperationDoc) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return Describe(next, doc)
+	}
+}
+
+// docMiddlewarePC identifies the closures Doc returns, which share their code.
+var docMiddlewarePC = reflect.ValueOf(Doc(OperationDoc{})).Pointer()
+
+// middlewareDoc returns the doc of the innermost Doc middleware, or nil.
+func middlewareDoc(middlewares []func(http.Handler) http.Handler) *OperationDoc {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		mw := middlewares[i]
+		if mw == nil || reflect.ValueOf(mw).Pointer() != docMiddlewarePC {
+			continue
+		}
+		if d, ok := mw(http.NotFoundHandler()).(*describedHandler); ok {
+			return &d.doc
+		}
+	}
+	return nil
+}
+
+// documents reports whether the doc covers method.
+func (doc *OperationDoc) documents(method string) bool {
+	return len(doc.Methods) == 0 || slices.ContainsFunc(doc.Methods, func(m string) bool {
+		return strings.EqualFold(m, method)
+	})
+}
+
+// annotation converts the doc into the annotation it stands in for, so the
+// operation is built as an annotated one would be.
+func (doc *OperationDoc) annotation() *Annotation {
+	a := &Annotation{
+		Summary:     doc.Summary,
+		Description: doc.Description,
+		Tags:        doc.Tags,
+		Accept:      doc.Accept,
+		Produce:     doc.Produce,
+		Security:    doc.Security,
+	}
+	for _, p := range doc.Params {
+		typeName := docTypeName(p.Type)
+		if typeName == "" {
+			typeName = "string"
+		}
+		a.Parameters = append(a.Parameters, ParamAnnotation{
+			Name:        p.Name,
+			In:          p.In,
+			Type:        typeName,
+			Required:    p.Required || p.In == "path",
+			Description: p.Description,
+		})
+	}
+	if doc.Body != nil {
+		a.Parameters = append(a.Parameters, ParamAnnotation{
+			Name:     "body",
+			In:       "body",
+			Type:     docTypeName(doc.Body),
+			Required: true,
+		})
+	}
+	for _, r := range doc.Responses {
+		description := r.Description
+		if description == "" {
+			description = http.StatusText(r.Status)
+		}
+		if r.Status >= http.StatusBadRequest {
+			a.Failures = append(a.Failures, ErrorResponse{
+				StatusCode:  r.Status,
+				Type:        docTypeName(r.Body),
+				Description: description,
+			})
+			continue
+		}
+		a.Successes = append(a.Successes, SuccessResponse{
+			StatusCode:  r.Status,
+			DataType:    docTypeName(r.Body),
+			Description: description,
+			IsWrapped:   r.Wrapped,
+		})
+	}
+	return a
+}
+
+// docTypeName returns the annotation type name for a doc type: a string is
+// used as written, a reflect.Type or value names its Go type.
+func docTypeName(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case reflect.Type:
+		return reflectTypeName(v)
+	}
+	return reflectTypeName(reflect.TypeOf(v))
+}
+
+// reflectTypeName names t as annotations do, qualifying named types with
+// their import path so they resolve without a source file's imports.
+// Pointers name their element type; slices, arrays and maps are named from
+// their element types. Generic instantiations name the generic type, whose
+// schema struct fields of that type use too.
+func reflectTypeName(t reflect.Type) string {
+	switch {
+	case t.Kind() == reflect.Pointer:
+		return reflectTypeName(t.Elem())
+	case t.Name() != "" && t.PkgPath() != "":
+		name, _, _ := strings.Cut(t.Name(), "[")
+		return t.PkgPath() + "." + name
+	case t.Name() != "":
+		return t.Name()
+	case t.Kind() == reflect.Slice:
+		return "[]" + reflectTypeName(t.Elem())
+	case t.Kind() == reflect.Array:
+		return "[" + strconv.Itoa(t.Len()) + "]" + reflectTypeName(t.Elem())
+	case t.Kind() == reflect.Map:
+		return "map[" + reflectTypeName(t.Key()) + "]" + reflectTypeName(t.Elem())
+	}
+	return t.String()
+}
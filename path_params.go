@@ -0,0 +1,279 @@
+package annot8
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"regexp/syntax"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DefaultWildcardParam names the path parameter chi catch-all routes
+// (/static/*) are documented with.
+const DefaultWildcardParam = "path"
+
+// routeParam is a chi route parameter: {name}, {name:regexp} or the
+// catch-all *.
+type routeParam struct {
+	name     string
+	pattern  string // regexp constraint, "" when unconstrained
+	wildcard bool
+}
+
+// parseRouteParams returns the route with regexp constraints removed and its
+// parameters in order. Constraints may contain braces ({4}) and slashes, so
+// a parameter ends at the brace closing its opening one, as in chi. A
+// catch-all * becomes the parameter wildcard, renamed if the route already
+// uses that name.
+func parseRouteParams(route, wildcard string) (string, []routeParam) {
+	var path strings.Builder
+	var params []routeParam
+	for i := 0; i < len(route); i++ {
+		if route[i] == '*' {
+			name := uniqueRouteParamName(params, wildcard)
+			params = append(params, routeParam{name: name, wildcard: true})
+			path.WriteString("{" + name + "}")
+			continue
+		}
+		if route[i] != '{' {
+			path.WriteByte(route[i])
+			continue
+		}
+		depth, end := 0, -1
+		for j := i; j < len(route) && end < 0; j++ {
+			switch route[j] {
+			case '{':
+				depth++
+			case '}':
+				depth--
+				if depth == 0 {
+					end = j
+				}
+			}
+		}
+		if end < 0 {
+			path.WriteString(route[i:])
+			break
+		}
+		name, pattern, _ := strings.Cut(route[i+1:end], ":")
+		params = append(params, routeParam{name: name, pattern: pattern})
+		path.WriteString("{" + name + "}")
+		i = end
+	}
+	return path.String(), params
+}
+
+func uniqueRouteParamName(params []routeParam, name string) string {
+	candidate := name
+	for n := 2; slices.ContainsFunc(params, func(p routeParam) bool { return p.name == candidate }); n++ {
+		candidate = name + strconv.Itoa(n)
+	}
+	return candidate
+}
+
+// routeParamParameter documents a route parameter.
+func routeParamParameter(rp routeParam) Parameter {
+	if rp.wildcard {
+		return Parameter{
+			Name:          rp.name,
+			In:            "path",
+			Description:   "Remainder of the path matched by the * wildcard; may contain slashes",
+			Required:      true,
+			Schema:        &Schema{Type: "string"},
+			AllowReserved: true,
+			Wildcard:      true,
+		}
+	}
+	return Parameter{
+		Name:     rp.name,
+		In:       "path",
+		Required: true,
+		Schema:   routeParamSchema(rp.pattern),
+	}
+}
+
+// routeParamSchema describes a path parameter constrained by pattern.
+// Unbounded digit runs ([0-9]+, \d+) become integers; other patterns keep
+// the (anchored) pattern, since a zip code or a zero-padded code is not a
+// number, and those accepting only UUIDs or dates gain the uuid or date
+// format.
+func routeParamSchema(pattern string) *Schema {
+	if pattern == "" {
+		return &Schema{Type: "string"}
+	}
+	// chi anchors constraints; JSON Schema patterns are unanchored.
+	anchored := pattern
+	if !strings.HasPrefix(anchored, "^") {
+		anchored = "^" + anchored
+	}
+	if !strings.HasSuffix(anchored, "$") {
+		anchored += "$"
+	}
+
+	schema := &Schema{Type: "string", Pattern: anchored}
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		slog.Warn("[annot8] routeParamSchema: invalid route pattern", "pattern", pattern, "error", err)
+		return schema
+	}
+	re = re.Simplify()
+
+	switch {
+	case unboundedDigits(re):
+		return &Schema{Type: "integer"}
+	case acceptsOnly(re, "0123456789abcdefABCDEF-") && fixedLength(re) == 36 &&
+		regexp.MustCompile(anchored).MatchString("123e4567-e89b-12d3-a456-426614174000"):
+		schema.Format = "uuid"
+	case acceptsOnly(re, "0123456789-") && fixedLength(re) == 10 &&
+		regexp.MustCompile(anchored).MatchString("2024-01-31"):
+		schema.Format = "date"
+	}
+	return schema
+}
+
+// unboundedDigits reports whether re matches any run of digits ([0-9]+,
+// \d+) or any run without a leading zero ([1-9][0-9]*): the numbers an
+// integer parameter accepts.
+func unboundedDigits(re *syntax.Regexp) bool {
+	var subs []*syntax.Regexp
+	for _, sub := range flattenConcat(re) {
+		switch sub.Op {
+		case syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText:
+		default:
+			subs = append(subs, sub)
+		}
+	}
+	digits := func(re *syntax.Regexp, lo rune) bool {
+		return re.Op == syntax.OpCharClass && slices.Equal(re.Rune, []rune{lo, '9'})
+	}
+	switch len(subs) {
+	case 1:
+		return subs[0].Op == syntax.OpPlus && digits(subs[0].Sub[0], '0')
+	case 2:
+		return digits(subs[0], '1') && subs[1].Op == syntax.OpStar && digits(subs[1].Sub[0], '0')
+	}
+	return false
+}
+
+// flattenConcat returns the parts of a concatenation, looking through
+// capture groups.
+func flattenConcat(re *syntax.Regexp) []*syntax.Regexp {
+	switch re.Op {
+	case syntax.OpCapture:
+		return flattenConcat(re.Sub[0])
+	case syntax.OpConcat:
+		var parts []*syntax.Regexp
+		for _, sub := range re.Sub {
+			parts = append(parts, flattenConcat(sub)...)
+		}
+		return parts
+	}
+	return []*syntax.Regexp{re}
+}
+
+// acceptsOnly reports whether every character re matches is in chars.
+func acceptsOnly(re *syntax.Regexp, chars string) bool {
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			if !strings.ContainsRune(chars, r) {
+				return false
+			}
+		}
+		return len(re.Rune) > 0
+	case syntax.OpCharClass:
+		for i := 0; i+1 < len(re.Rune); i += 2 {
+			if int(re.Rune[i+1]-re.Rune[i]) >= len(chars) {
+				return false
+			}
+			for r := re.Rune[i]; r <= re.Rune[i+1]; r++ {
+				if !strings.ContainsRune(chars, r) {
+					return false
+				}
+			}
+		}
+		return len(re.Rune) > 0
+	case syntax.OpCapture, syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		return acceptsOnly(re.Sub[0], chars)
+	case syntax.OpConcat, syntax.OpAlternate:
+		for _, sub := range re.Sub {
+			if !acceptsOnly(sub, chars) {
+				return false
+			}
+		}
+		return len(re.Sub) > 0
+	case syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText:
+		return true
+	}
+	return false
+}
+
+// fixedLength returns the length of every string re matches, or -1 when it
+// varies.
+func fixedLength(re *syntax.Regexp) int {
+	switch re.Op {
+	case syntax.OpLiteral:
+		return len(re.Rune)
+	case syntax.OpCharClass:
+		return 1
+	case syntax.OpCapture:
+		return fixedLength(re.Sub[0])
+	case syntax.OpRepeat:
+		if n := fixedLength(re.Sub[0]); n >= 0 && re.Min == re.Max {
+			return n * re.Min
+		}
+	case syntax.OpConcat:
+		total := 0
+		for _, sub := range re.Sub {
+			n := fixedLength(sub)
+			if n < 0 {
+				return -1
+			}
+			total += n
+		}
+		return total
+	case syntax.OpAlternate:
+		length := -1
+		for i, sub := range re.Sub {
+			n := fixedLength(sub)
+			if n < 0 || (i > 0 && n != length) {
+				return -1
+			}
+			length = n
+		}
+		return length
+	case syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText:
+		return 0
+	}
+	return -1
+}
+
+// reconcilePathParameter checks an @Param path declaration against the
+// schema derived from the route's constraint. The declaration wins; a
+// declared string without a pattern keeps the route's, and a differing type
+// or format is returned as a conflict.
+func reconcilePathParameter(derived, declared *Schema, name string) (*Schema, string) {
+	if derived == nil || declared == nil || declared.Ref != "" || (derived.Pattern == "" && derived.Type == "string") {
+		return declared, ""
+	}
+	if primaryType(declared) != primaryType(derived) ||
+		(declared.Format != "" && derived.Format != "" && declared.Format != derived.Format) {
+		return declared, fmt.Sprintf("path parameter %q: @Param documents %s but the route constraint implies %s",
+			name, describeSchemaType(declared), describeSchemaType(derived))
+	}
+	if primaryType(declared) == "string" && declared.Pattern == "" {
+		out := *declared
+		out.Pattern = derived.Pattern
+		return &out, ""
+	}
+	return declared, ""
+}
+
+func describeSchemaType(s *Schema) string {
+	if s.Format != "" {
+		return primaryType(s) + " (" + s.Format + ")"
+	}
+	return primaryType(s)
+}
@@ -0,0 +1,221 @@
+package annot8
+
+import "log/slog"
+
+// Preset is a named bundle of external type mappings for a library whose
+// types are not parsed from source. Keys are full import paths
+// ("github.com/google/uuid.UUID"); a "*" prefix maps the pointer type.
+type Preset struct {
+	Name  string
+	Types map[string]*Schema
+}
+
+// Presets holds the curated bundles. Every schema describes what the type's
+// encoding/json output actually is, including null for invalid values.
+var Presets = struct {
+	// StdLib covers time.Time, database/sql Null* types, net/netip and net/url.
+	StdLib Preset
+	// PgxV5 covers github.com/jackc/pgx/v5/pgtype.
+	PgxV5 Preset
+	// GoogleUUID covers github.com/google/uuid.
+	GoogleUUID Preset
+	// ShopspringDecimal covers github.com/shopspring/decimal.
+	ShopspringDecimal Preset
+}{
+	StdLib:            stdLibPreset(),
+	PgxV5:             pgxV5Preset(),
+	GoogleUUID:        googleUUIDPreset(),
+	ShopspringDecimal: shopspringDecimalPreset(),
+}
+
+// ApplyPresets merges the presets' mappings into this index. Schemas are
+// copied, so later edits to a preset do not leak into generated specs.
+func (idx *TypeIndex) ApplyPresets(presets ...Preset) {
+	if idx == nil {
+		return
+	}
+	for _, preset := range presets {
+		mappings := make(map[string]*Schema, len(preset.Types))
+		for name, schema := range preset.Types {
+			mappings[name] = cloneSchema(schema)
+		}
+		idx.AddExternalKnownTypes(mappings)
+		slog.Debug("[annot8] ApplyPresets: applied preset", "preset", preset.Name, "types", len(mappings))
+	}
+}
+
+// ApplyPresets merges the presets' mappings into the generator's TypeIndex.
+func (g *Generator) ApplyPresets(presets ...Preset) {
+	g.schemaGen.typeIndex.ApplyPresets(presets...)
+}
+
+// ApplyPresets merges the presets' mappings into the shared TypeIndex.
+func ApplyPresets(presets ...Preset) {
+	ensureTypeIndex()
+	if typeIndex == nil {
+		slog.Error("[annot8] ApplyPresets: typeIndex is nil")
+		return
+	}
+	typeIndex.ApplyPresets(presets...)
+}
+
+func nullable(types ...string) []string {
+	return append(types, "null")
+}
+
+// sqlNullSchema describes a database/sql Null* struct. These types have no
+// JSON methods and marshal as {"<Field>": value, "Valid": bool}.
+func sqlNullSchema(field string, value *Schema) *Schema {
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			field:   value,
+			"Valid": {Type: "boolean"},
+		},
+		Required:    []string{field, "Valid"},
+		Description: "database/sql nullable value; " + field + " is meaningful only when Valid is true",
+	}
+}
+
+func stdLibPreset() Preset {
+	ipAddress := &Schema{Type: "string", Description: "IPv4 or IPv6 address; empty for the zero value"}
+	return Preset{
+		Name: "stdlib",
+		Types: map[string]*Schema{
+			"time.Time":  {Type: "string", Format: "date-time", Description: "RFC3339 date-time"},
+			"*time.Time": {Type: nullable("string"), Format: "date-time", Description: "Nullable RFC3339 date-time"},
+
+			"database/sql.NullString":  sqlNullSchema("String", &Schema{Type: "string"}),
+			"database/sql.NullBool":    sqlNullSchema("Bool", &Schema{Type: "boolean"}),
+			"database/sql.NullByte":    sqlNullSchema("Byte", &Schema{Type: "integer", Format: "uint8"}),
+			"database/sql.NullInt16":   sqlNullSchema("Int16", &Schema{Type: "integer", Format: "int16"}),
+			"database/sql.NullInt32":   sqlNullSchema("Int32", &Schema{Type: "integer", Format: "int32"}),
+			"database/sql.NullInt64":   sqlNullSchema("Int64", &Schema{Type: "integer", Format: "int64"}),
+			"database/sql.NullFloat64": sqlNullSchema("Float64", &Schema{Type: "number", Format: "double"}),
+			"database/sql.NullTime":    sqlNullSchema("Time", &Schema{Type: "string", Format: "date-time"}),
+
+			"net/netip.Addr":     ipAddress,
+			"net/netip.AddrPort": {Type: "string", Description: "IP address and port; empty for the zero value"},
+			"net/netip.Prefix":   {Type: "string", Description: "IP network prefix in CIDR notation; empty for the zero value"},
+
+			// url.URL has no JSON methods and marshals as its exported fields.
+			"net/url.URL": {
+				Type: "object",
+				Properties: map[string]*Schema{
+					"Scheme":      {Type: "string"},
+					"Opaque":      {Type: "string"},
+					"User":        {Type: nullable("object"), Description: "Userinfo has no exported fields"},
+					"Host":        {Type: "string"},
+					"Path":        {Type: "string"},
+					"RawPath":     {Type: "string"},
+					"OmitHost":    {Type: "boolean"},
+					"ForceQuery":  {Type: "boolean"},
+					"RawQuery":    {Type: "string"},
+					"Fragment":    {Type: "string"},
+					"RawFragment": {Type: "string"},
+				},
+				Description: "net/url.URL marshaled field by field",
+			},
+		},
+	}
+}
+
+func pgxV5Preset() Preset {
+	const pgtype = "github.com/jackc/pgx/v5/pgtype."
+	return Preset{
+		Name: "pgx/v5",
+		Types: map[string]*Schema{
+			pgtype + "Text":   {Type: nullable("string"), Description: "Nullable text"},
+			pgtype + "Bool":   {Type: nullable("boolean"), Description: "Nullable boolean"},
+			pgtype + "Int2":   {Type: nullable("integer"), Format: "int16", Description: "Nullable smallint"},
+			pgtype + "Int4":   {Type: nullable("integer"), Format: "int32", Description: "Nullable integer"},
+			pgtype + "Int8":   {Type: nullable("integer"), Format: "int64", Description: "Nullable bigint"},
+			pgtype + "Float4": {Type: nullable("number"), Format: "float", Description: "Nullable real"},
+			pgtype + "Float8": {Type: nullable("number"), Format: "double", Description: "Nullable double precision"},
+			pgtype + "Numeric": {
+				AnyOf: []*Schema{
+					{Type: "number"},
+					{Type: "string", Enum: []any{"NaN", "Infinity", "-Infinity"}},
+					{Type: "null"},
+				},
+				Description: "Nullable numeric; special values are strings",
+			},
+			pgtype + "Date": pgInfinite(&Schema{Type: "string", Format: "date"}, "Nullable date"),
+			pgtype + "Timestamp": pgInfinite(
+				&Schema{Type: "string", Pattern: `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?$`},
+				"Nullable timestamp without time zone, written without an offset",
+			),
+			pgtype + "Timestamptz": pgInfinite(
+				&Schema{Type: "string", Format: "date-time"},
+				"Nullable timestamp with time zone",
+			),
+			pgtype + "UUID": {Type: nullable("string"), Format: "uuid", Description: "Nullable UUID"},
+			// Interval and Range have no JSON methods and marshal as their fields.
+			pgtype + "Interval": {
+				Type: "object",
+				Properties: map[string]*Schema{
+					"Microseconds": {Type: "integer", Format: "int64"},
+					"Days":         {Type: "integer", Format: "int32"},
+					"Months":       {Type: "integer", Format: "int32"},
+					"Valid":        {Type: "boolean"},
+				},
+				Required:    []string{"Microseconds", "Days", "Months", "Valid"},
+				Description: "PostgreSQL interval marshaled field by field",
+			},
+			pgtype + "Range": {
+				Type: "object",
+				Properties: map[string]*Schema{
+					"Lower":     {Description: "Lower bound as its element type marshals it; null when unbounded"},
+					"Upper":     {Description: "Upper bound as its element type marshals it; null when unbounded"},
+					"LowerType": {Type: "integer", Description: "Bound type as a rune: 'i' inclusive, 'e' exclusive, 'U' unbounded, 'E' empty"},
+					"UpperType": {Type: "integer", Description: "Bound type as a rune: 'i' inclusive, 'e' exclusive, 'U' unbounded, 'E' empty"},
+					"Valid":     {Type: "boolean"},
+				},
+				Required:    []string{"Lower", "Upper", "LowerType", "UpperType", "Valid"},
+				Description: "PostgreSQL range marshaled field by field",
+			},
+		},
+	}
+}
+
+// pgInfinite describes a nullable pgtype date or timestamp, which marshals
+// its infinite values as "infinity" and "-infinity".
+func pgInfinite(finite *Schema, description string) *Schema {
+	return &Schema{
+		AnyOf: []*Schema{
+			finite,
+			{Type: "string", Enum: []any{"infinity", "-infinity"}},
+			{Type: "null"},
+		},
+		Description: description + "; infinite values are \"infinity\" or \"-infinity\"",
+	}
+}
+
+func googleUUIDPreset() Preset {
+	return Preset{
+		Name: "github.com/google/uuid",
+		Types: map[string]*Schema{
+			"github.com/google/uuid.UUID":     {Type: "string", Format: "uuid", Description: "UUID"},
+			"github.com/google/uuid.NullUUID": {Type: nullable("string"), Format: "uuid", Description: "Nullable UUID"},
+		},
+	}
+}
+
+func shopspringDecimalPreset() Preset {
+	const pattern = `^-?[0-9]+(\.[0-9]+)?$`
+	return Preset{
+		Name: "github.com/shopspring/decimal",
+		Types: map[string]*Schema{
+			"github.com/shopspring/decimal.Decimal": {
+				Type:        "string",
+				Pattern:     pattern,
+				Description: "Arbitrary-precision decimal encoded as a string",
+			},
+			"github.com/shopspring/decimal.NullDecimal": {
+				Type:        nullable("string"),
+				Pattern:     pattern,
+				Description: "Nullable arbitrary-precision decimal encoded as a string",
+			},
+		},
+	}
+}
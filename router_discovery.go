@@ -18,6 +18,10 @@ type RouteInfo struct {
 	HandlerName string
 	HandlerFunc http.HandlerFunc
 	Middlewares []func(http.Handler) http.Handler
+	Doc         *OperationDoc // set by Describe or the Doc middleware
+	// Mount is the prefix of the innermost r.Route or r.Mount subrouter the
+	// route was registered on, empty for routes on r itself.
+	Mount string
 }
 
 // RouteDiscoveryError represents an error that occurred during route discovery.
@@ -44,10 +48,17 @@ func InspectRoutes(r chi.Router) ([]RouteInfo, error) {
 		}
 	}
 
+	mounts := mountPrefixes(r, "")
+
 	var routes []RouteInfo
 	err := chi.Walk(
 		r,
 		func(method string, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+			doc := middlewareDoc(middlewares)
+			if d, ok := handler.(*describedHandler); ok {
+				handler, doc = d.Handler, &d.doc
+			}
+
 			// Attempt to extract http.HandlerFunc
 			var hf http.HandlerFunc
 			switch h := handler.(type) {
@@ -64,6 +75,8 @@ func InspectRoutes(r chi.Router) ([]RouteInfo, error) {
 				HandlerName: name,
 				HandlerFunc: hf,
 				Middlewares: middlewares,
+				Doc:         doc,
+				Mount:       routeMount(mounts, route),
 			})
 			return nil
 		},
@@ -79,6 +92,32 @@ func InspectRoutes(r chi.Router) ([]RouteInfo, error) {
 	return routes, nil
 }
 
+// mountPrefixes lists the route prefixes of the subrouters below r, joined
+// as chi.Walk joins route patterns.
+func mountPrefixes(r chi.Routes, parent string) []string {
+	var prefixes []string
+	for _, route := range r.Routes() {
+		if route.SubRoutes == nil {
+			continue
+		}
+		pattern := parent + route.Pattern
+		prefixes = append(prefixes, strings.TrimSuffix(strings.ReplaceAll(pattern, "/*/", "/"), "/*"))
+		prefixes = append(prefixes, mountPrefixes(route.SubRoutes, pattern)...)
+	}
+	return prefixes
+}
+
+// routeMount returns the longest prefix in mounts that route was registered under.
+func routeMount(mounts []string, route string) string {
+	var mount string
+	for _, prefix := range mounts {
+		if len(prefix) > len(mount) && (route == prefix || strings.HasPrefix(route, prefix+"/")) {
+			mount = prefix
+		}
+	}
+	return mount
+}
+
 // DiscoverRoutes returns only non-internal routes for OpenAPI spec assembly.
 // This function filters out routes that are part of the OpenAPI tooling itself
 // (such as /swagger and /openapi endpoints) to avoid circular references in the specification.
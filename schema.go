@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"go/ast"
 	"log/slog"
-	"strings"
 	"sync"
 )
 
@@ -14,7 +13,20 @@ type SchemaGenerator struct {
 	schemas        map[string]*Schema
 	typeIndex      *TypeIndex
 	mutex          sync.Mutex
-	currentPackage string // tracks the package of the struct being processed
+	currentPackage string // import path of the package whose type is being processed
+	currentFile    string // file whose import table resolves qualified identifiers
+	embedMode      EmbeddedStructMode
+	int64Rep       Int64Representation
+	int64Override  *Int64Representation // field or type override of int64Rep
+	requiredPolicy RequiredPolicy
+	closedObjects  bool
+	nullability    NullabilityStrategy
+	currentType    string // schema whose struct tags are being applied
+	currentField   string // JSON name of the field whose struct tags are being applied
+	diagnostics    []string
+	variants       map[string]string                // derived variant ID -> base component ID
+	xmlMode        bool                             // name members after xml tags (XML media types)
+	sqlcTables     map[string]map[string]*sqlcModel // import path -> sqlKey(model name) -> model
 }
 
 // NewSchemaGenerator creates a new schema generator. Optionally accepts a TypeIndex.
@@ -35,6 +47,12 @@ func NewSchemaGenerator(opts ...*TypeIndex) *SchemaGenerator {
 	}
 }
 
+// SetEmbeddedStructMode selects how embedded struct fields are documented.
+// The default, EmbedFlatten, mirrors encoding/json.
+func (sg *SchemaGenerator) SetEmbeddedStructMode(mode EmbeddedStructMode) {
+	sg.embedMode = mode
+}
+
 // GenerateSchema creates a JSON schema for the given type name.
 // All types are stored using qualified names (e.g., "order.CreateReq", "sqlc.User").
 func (sg *SchemaGenerator) GenerateSchema(typeName string) *Schema {
@@ -55,24 +73,38 @@ func (sg *SchemaGenerator) GenerateSchema(typeName string) *Schema {
 	qualifiedName := sg.getQualifiedTypeName(typeName)
 	slog.Debug("[annot8] GenerateSchema: type name conversion", "typeName", typeName, "qualifiedName", qualifiedName)
 
+	// Aliases of predeclared or composite types (type Tags = []string) have no
+	// name of their own; like the Go type, their schema is used in place.
+	if sg.typeIndex != nil {
+		if ts := sg.typeIndex.LookupQualifiedType(qualifiedName); ts != nil && ts.Assign.IsValid() {
+			slog.Debug("[annot8] GenerateSchema: inlining type alias", "qualifiedName", qualifiedName)
+			return sg.buildFromTypeSpec(qualifiedName, ts)
+		}
+	}
+
 	// 4) Check external known types. Callers decorate the returned schema in
 	// place (nullability wrapping, validation tags), so hand out clones —
 	// returning the shared canonical pointer once made a single *pgtype.Numeric
 	// field mark every Numeric occurrence in the spec nullable.
 	if sg.typeIndex != nil {
-		if schema, ok := sg.typeIndex.externalKnownTypes[qualifiedName]; ok {
+		if schema, ok := sg.typeIndex.externalKnownType(qualifiedName); ok {
 			slog.Debug("[annot8] GenerateSchema: using externalKnownTypes", "qualifiedName", qualifiedName)
 			// Store in sg.schemas so it can be post-processed (renamed) if needed,
 			// but only if it's not a reference itself.
 			sg.mutex.Lock()
 			if _, exists := sg.schemas[qualifiedName]; !exists && schema.Ref == "" {
-				sg.schemas[qualifiedName] = cloneSchema(schema)
+				sg.schemas[qualifiedName] = sg.normalizeNullability(cloneSchema(schema))
 			}
 			sg.mutex.Unlock()
-			return cloneSchema(schema)
+			return sg.normalizeNullability(cloneSchema(schema))
+		}
+
+		if schema, ok := sg.typeIndex.builtinJSONSchema(qualifiedName); ok {
+			slog.Debug("[annot8] GenerateSchema: using encoding/json builtin schema", "qualifiedName", qualifiedName)
+			return schema
 		}
 
-		if schema := sg.typeIndex.inferMarshalerSchema(qualifiedName); schema != nil {
+		if schema := sg.marshalerSchema(qualifiedName); schema != nil {
 			slog.Debug("[annot8] GenerateSchema: using marshaler-derived schema", "qualifiedName", qualifiedName)
 			sg.mutex.Lock()
 			if _, exists := sg.schemas[qualifiedName]; !exists && schema.Ref == "" {
@@ -83,70 +115,40 @@ func (sg *SchemaGenerator) GenerateSchema(typeName string) *Schema {
 		}
 	}
 
+	// XML mode documents element types under their own key.
+	key := sg.componentKey(qualifiedName)
+
 	// 5) Check if schema already exists (avoid duplicate work)
 	sg.mutex.Lock()
-	if existingSchema, exists := sg.schemas[qualifiedName]; exists {
+	if existingSchema, exists := sg.schemas[key]; exists {
 		sg.mutex.Unlock()
 		if existingSchema == nil {
 			// Currently being processed, return reference
-			return &Schema{Ref: fmt.Sprintf("#/components/schemas/%s", qualifiedName)}
+			return &Schema{Ref: fmt.Sprintf("#/components/schemas/%s", key)}
 		}
 		slog.Debug("[annot8] GenerateSchema: schema already exists", "qualifiedName", qualifiedName)
-		return &Schema{Ref: fmt.Sprintf("#/components/schemas/%s", qualifiedName)}
+		return &Schema{Ref: fmt.Sprintf("#/components/schemas/%s", key)}
 	}
 
 	// 6) Reserve placeholder to prevent infinite recursion
-	sg.schemas[qualifiedName] = nil
+	sg.schemas[key] = nil
 	sg.mutex.Unlock()
 
 	// 7) Check if it's an enum type
 	if enumSchema := sg.handleEnumType(qualifiedName); enumSchema != nil {
 		slog.Debug("[annot8] GenerateSchema: detected enum type", "qualifiedName", qualifiedName)
 		sg.mutex.Lock()
-		sg.schemas[qualifiedName] = enumSchema
+		sg.schemas[key] = enumSchema
 		sg.mutex.Unlock()
-		return &Schema{Ref: fmt.Sprintf("#/components/schemas/%s", qualifiedName)}
+		return &Schema{Ref: fmt.Sprintf("#/components/schemas/%s", key)}
 	}
 
 	// 8) Generate the actual schema
 	var built *Schema
 	if sg.typeIndex != nil {
-		// tryBuildFromAST performs the AST-based struct/type conversion for ts.
-		tryBuildFromAST := func(ts *ast.TypeSpec) *Schema {
-			oldPkg := sg.currentPackage
-			if idx := strings.LastIndex(qualifiedName, "."); idx != -1 {
-				sg.currentPackage = qualifiedName[:idx]
-			}
-			var s *Schema
-			if _, ok := ts.Type.(*ast.StructType); ok {
-				s = sg.convertStructToSchema(ts.Type.(*ast.StructType))
-			} else {
-				s = sg.convertFieldType(ts.Type)
-			}
-			sg.currentPackage = oldPkg
-			return s
-		}
-
-		// Try qualified lookup first.
 		if ts := sg.typeIndex.LookupQualifiedType(qualifiedName); ts != nil {
 			slog.Debug("[annot8] GenerateSchema: found type in TypeIndex", "qualifiedName", qualifiedName)
-			built = tryBuildFromAST(ts)
-		}
-
-		// On a miss, attempt to load the external package on-demand and retry.
-		if built == nil {
-			if alias := externalPackageAlias(qualifiedName); alias != "" {
-				if sg.typeIndex.loadExternalPackage(alias) {
-					if ts := sg.typeIndex.LookupQualifiedType(qualifiedName); ts != nil {
-						slog.Debug(
-							"[annot8] GenerateSchema: found type after on-demand load",
-							"qualifiedName",
-							qualifiedName,
-						)
-						built = tryBuildFromAST(ts)
-					}
-				}
-			}
+			built = sg.buildFromTypeSpec(qualifiedName, ts)
 		}
 	}
 
@@ -171,26 +173,80 @@ func (sg *SchemaGenerator) GenerateSchema(typeName string) *Schema {
 	// 10) Store the built schema
 	sg.mutex.Lock()
 	slog.Debug("[annot8] GenerateSchema: storing schema", "qualifiedName", qualifiedName, "originalTypeName", typeName)
-	sg.schemas[qualifiedName] = built
+	sg.schemas[key] = built
 	sg.mutex.Unlock()
 
 	// 11) Always return a reference
-	return &Schema{Ref: fmt.Sprintf("#/components/schemas/%s", qualifiedName)}
+	return &Schema{Ref: fmt.Sprintf("#/components/schemas/%s", key)}
+}
+
+// buildFromTypeSpec performs the AST-based struct/type conversion for ts,
+// resolving identifiers inside it against the package and file declaring it.
+// A defined type over another project type (type Admin User) takes that
+// type's underlying schema, as encoding/json does; methods are not inherited.
+func (sg *SchemaGenerator) buildFromTypeSpec(qualifiedName string, ts *ast.TypeSpec) *Schema {
+	if !ts.Assign.IsValid() {
+		if target := sg.typeIndex.resolveAlias(sg.typeIndex.namedTarget(qualifiedName, ts)); target != "" {
+			if underlying := sg.typeIndex.LookupQualifiedType(target); underlying != nil {
+				return sg.buildFromTypeSpec(target, underlying)
+			}
+		}
+	}
+
+	oldPkg, oldFile, oldType := sg.currentPackage, sg.currentFile, sg.currentType
+	sg.currentPackage, _ = sg.typeIndex.resolveID(qualifiedName)
+	sg.currentFile = sg.typeIndex.specFiles[ts]
+	sg.currentType = qualifiedName
+	defer func() { sg.currentPackage, sg.currentFile, sg.currentType = oldPkg, oldFile, oldType }()
+	if !ts.Assign.IsValid() {
+		// Aliases are inlined and keep the caller's int64 representation.
+		defer sg.enterTypeInt64Override(ts)()
+	}
+
+	switch t := ts.Type.(type) {
+	case *ast.StructType:
+		schema := sg.convertStructToSchema(t)
+		if sg.typeClosed(ts) {
+			closeObject(schema)
+		}
+		return schema
+	case *ast.InterfaceType:
+		return sg.interfaceSchema(qualifiedName, ts, t)
+	}
+	return sg.convertFieldType(ts.Type)
+}
+
+// enterFile makes filePath the context for resolving type names (for example
+// the handler file an annotation was written in) and returns a func restoring
+// the previous context.
+func (sg *SchemaGenerator) enterFile(filePath string) func() {
+	oldPkg, oldFile := sg.currentPackage, sg.currentFile
+	if sg.typeIndex != nil && filePath != "" {
+		sg.currentPackage = sg.typeIndex.filePackage(filePath)
+		sg.currentFile = filePath
+	}
+	return func() { sg.currentPackage, sg.currentFile = oldPkg, oldFile }
 }
 
 // getQualifiedTypeName returns the qualified type name for schema keys.
-// It uses the TypeIndex if available, otherwise falls back to the original name.
+// Qualified names are resolved through the current file's import table;
+// unqualified names prefer the current package before searching the index.
 func (sg *SchemaGenerator) getQualifiedTypeName(typeName string) string {
-	// If already qualified, return as-is
-	if strings.Contains(typeName, ".") {
-		slog.Debug("[annot8] getQualifiedTypeName: already qualified", "typeName", typeName)
+	if sg.typeIndex == nil {
+		slog.Debug("[annot8] getQualifiedTypeName: no typeIndex, using original", "typeName", typeName)
 		return typeName
 	}
 
+	if alias, name := splitQualifiedName(typeName); alias != "" {
+		qualified := sg.typeIndex.resolveAlias(sg.typeIndex.resolveQualified(alias, name, sg.currentFile))
+		slog.Debug("[annot8] getQualifiedTypeName: resolved selector", "typeName", typeName, "qualifiedName", qualified)
+		return qualified
+	}
+
 	// If we're inside a package context, try to qualify with that package first
-	if sg.currentPackage != "" && sg.typeIndex != nil {
+	if sg.currentPackage != "" {
 		if _, exists := sg.typeIndex.types[sg.currentPackage][typeName]; exists {
-			qualified := sg.currentPackage + "." + typeName
+			qualified := sg.typeIndex.resolveAlias(sg.typeIndex.typeID(sg.currentPackage, typeName))
 			slog.Debug(
 				"[annot8] getQualifiedTypeName: qualified using currentPackage",
 				"typeName",
@@ -204,13 +260,9 @@ func (sg *SchemaGenerator) getQualifiedTypeName(typeName string) string {
 		}
 	}
 
-	if sg.typeIndex != nil {
-		qualified := sg.typeIndex.GetQualifiedTypeName(typeName)
-		slog.Debug("[annot8] getQualifiedTypeName: converted", "typeName", typeName, "qualifiedName", qualified)
-		return qualified
-	}
-	slog.Debug("[annot8] getQualifiedTypeName: no typeIndex, using original", "typeName", typeName)
-	return typeName
+	qualified := sg.typeIndex.resolveAlias(sg.typeIndex.GetQualifiedTypeName(typeName))
+	slog.Debug("[annot8] getQualifiedTypeName: converted", "typeName", typeName, "qualifiedName", qualified)
+	return qualified
 }
 
 // GetSchemas returns all generated schemas.
@@ -222,14 +274,3 @@ func (sg *SchemaGenerator) GetSchemas() map[string]Schema {
 	}
 	return result
 }
-
-// externalPackageAlias extracts the package alias from a qualified type name such as
-// "pgtype.Vec2" or "*pgtype.Vec2", returning "pgtype". Returns "" for unqualified names.
-func externalPackageAlias(qualifiedName string) string {
-	// Strip leading pointer sigil(s)
-	name := strings.TrimLeft(qualifiedName, "*")
-	if dot := strings.LastIndex(name, "."); dot > 0 {
-		return name[:dot]
-	}
-	return ""
-}
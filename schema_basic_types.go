@@ -2,6 +2,7 @@
 package annot8
 
 import (
+	"strconv"
 	"strings"
 )
 
@@ -58,10 +59,10 @@ func isBasicType(typeName string) bool {
 	case "int", "int8", "int16", "int32", "int64",
 		"uint", "uint8", "uint16", "uint32", "uint64",
 		"rune", "byte", "float32", "float64",
-		"string", "bool":
+		"string", "bool", "any", "interface{}":
 		return true
 	}
-	if strings.HasPrefix(typeName, "[]") || strings.HasPrefix(typeName, "*") || strings.HasPrefix(typeName, "map[") {
+	if strings.HasPrefix(typeName, "[") || strings.HasPrefix(typeName, "*") || strings.HasPrefix(typeName, "map[") {
 		return true
 	}
 	return false
@@ -70,10 +71,27 @@ func isBasicType(typeName string) bool {
 // generateBasicTypeSchema returns a Schema for basic Go types (primitives, slices, pointers, maps).
 // It handles arrays, pointers, and maps by delegating to GenerateSchema for element types.
 func (sg *SchemaGenerator) generateBasicTypeSchema(typeName string) *Schema {
+	if isAnyType(typeName) {
+		// The empty interface marshals whatever it holds: any JSON value.
+		return &Schema{}
+	}
 	if strings.HasPrefix(typeName, "[]") {
 		elem := strings.TrimPrefix(typeName, "[]")
+		if isByteType(elem) {
+			return sg.byteSliceSchema()
+		}
 		return &Schema{Type: "array", Items: sg.GenerateSchema(elem)}
 	}
+	if strings.HasPrefix(typeName, "[") {
+		// Fixed-size arrays ([3]T) hold exactly their length in items.
+		length, elem, _ := strings.Cut(strings.TrimPrefix(typeName, "["), "]")
+		schema := &Schema{Type: "array", Items: sg.GenerateSchema(elem)}
+		if n, err := strconv.Atoi(length); err == nil {
+			schema.MinItems = &n
+			schema.MaxItems = &n
+		}
+		return schema
+	}
 	if strings.HasPrefix(typeName, "map[") {
 		// Parse map[KeyType]ValueType — keys are always strings in JSON objects.
 		// Find the closing bracket of the key type (keys cannot themselves be maps/slices).
@@ -81,44 +99,37 @@ func (sg *SchemaGenerator) generateBasicTypeSchema(typeName string) *Schema {
 		bracketIdx := strings.Index(rest, "]")
 		if bracketIdx != -1 {
 			valueType := rest[bracketIdx+1:]
-			return &Schema{Type: "object", AdditionalProperties: sg.GenerateSchema(valueType)}
+			return &Schema{
+				Type:                 "object",
+				AdditionalProperties: sg.GenerateSchema(valueType),
+				PropertyNames:        sg.mapKeySchema(rest[:bracketIdx]),
+			}
 		}
 		return &Schema{Type: "object"}
 	}
 	if strings.HasPrefix(typeName, "*") {
 		// Try to see if the pointer type is known externally first (e.g. *time.Time)
-		qualified := sg.getQualifiedTypeName(typeName)
-		if sg.typeIndex != nil {
-			if schema, ok := sg.typeIndex.externalKnownTypes[qualified]; ok {
-				return schema
-			}
+		qualified := "*" + sg.getQualifiedTypeName(strings.TrimPrefix(typeName, "*"))
+		if schema, ok := sg.typeIndex.externalKnownType(qualified); ok {
+			return sg.normalizeNullability(cloneSchema(schema))
 		}
 
 		clean := strings.TrimPrefix(typeName, "*")
-		// For basic primitives, use the new 3.1 multi-type array
+		if isAnyType(clean) {
+			return &Schema{}
+		}
+		// Basic primitives accept null in the strategy's form
 		if !strings.Contains(clean, ".") && isBasicType(clean) && !strings.HasPrefix(clean, "[]") &&
 			!strings.HasPrefix(clean, "map[") {
-			underlyingType, underlyingFormat := mapGoTypeToOpenAPI(clean)
-			schema := &Schema{
-				Type: []string{underlyingType, "null"},
-			}
-			if underlyingFormat != "" {
-				schema.Format = underlyingFormat
-			}
-			return schema
+			underlyingType, underlyingFormat := sg.mapGoType(clean)
+			return sg.nullable(&Schema{Type: underlyingType, Format: underlyingFormat})
 		}
 
-		// For complex types or slices/maps, use anyOf to avoid type conflicts
-		underlying := sg.GenerateSchema(clean)
-		return &Schema{
-			AnyOf: []*Schema{
-				underlying,
-				{Type: "null"},
-			},
-		}
+		// Complex types, slices and maps are wrapped in anyOf (or allOf)
+		return sg.nullable(sg.GenerateSchema(clean))
 	}
 	// Fallback to mapping
-	openapiType, openapiFormat := mapGoTypeToOpenAPI(typeName)
+	openapiType, openapiFormat := sg.mapGoType(typeName)
 	desc := openapiType + " type" + "(" + typeName + ")"
 	if openapiFormat != "" {
 		desc += " with format " + openapiFormat
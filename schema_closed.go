@@ -0,0 +1,174 @@
+package annot8
+
+import (
+	"go/ast"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// openVariant derives the component that allOf composition references in
+// place of a closed one: a closed member would reject the properties its
+// siblings contribute.
+var openVariant = schemaVariant{suffix: "Open"}
+
+// SetClosedObjects documents struct schemas as closed: additionalProperties
+// is false, or unevaluatedProperties when the schema is composed with allOf.
+// This matches handlers that decode with DisallowUnknownFields. Types
+// override it with an "@closed" or "@open" doc directive and fields with an
+// openapi:"closed=true|false" tag.
+func (sg *SchemaGenerator) SetClosedObjects(closed bool) {
+	sg.closedObjects = closed
+}
+
+// typeClosed reports whether the struct declared by ts is documented as
+// closed: its own "@closed" or "@open" directive, then the generator option.
+func (sg *SchemaGenerator) typeClosed(ts *ast.TypeSpec) bool {
+	if doc := sg.typeIndex.specDocs[ts]; doc != nil {
+		for _, line := range strings.Split(doc.Text(), "\n") {
+			switch strings.TrimSpace(line) {
+			case "@closed":
+				return true
+			case "@open":
+				return false
+			}
+		}
+	}
+	return sg.closedObjects
+}
+
+// closeObject forbids members a struct schema does not declare. Composed
+// schemas use unevaluatedProperties, which sees the properties of every
+// allOf member; additionalProperties only sees its own object's.
+func closeObject(schema *Schema) {
+	switch {
+	case schema == nil:
+	case len(schema.AllOf) > 0:
+		schema.UnevaluatedProperties = false
+	case schema.AdditionalProperties == nil:
+		schema.AdditionalProperties = false
+	}
+}
+
+// isClosed reports whether schema forbids undeclared members at its top level.
+func isClosed(schema *Schema) bool {
+	return schema != nil && (schema.AdditionalProperties == false || schema.UnevaluatedProperties == false)
+}
+
+// openSchema returns schema with references to closed components replaced
+// by references to their open variants, looking through nullable anyOf
+// wrappers.
+func (sg *SchemaGenerator) openSchema(schema *Schema) *Schema {
+	if schema == nil {
+		return nil
+	}
+	if schema.Ref != "" {
+		if id := sg.openVariantID(strings.TrimPrefix(schema.Ref, schemaRefPrefix)); id != "" {
+			out := *schema
+			out.Ref = schemaRefPrefix + id
+			return &out
+		}
+		return schema
+	}
+	if len(schema.AnyOf) == 0 {
+		return schema
+	}
+	out := *schema
+	out.AnyOf = make([]*Schema, len(schema.AnyOf))
+	for i, sub := range schema.AnyOf {
+		out.AnyOf[i] = sg.openSchema(sub)
+	}
+	return &out
+}
+
+// openVariantID returns the ID of component id's open variant, deriving it
+// on first use, or "" when the component is not closed. The variant keeps
+// everything else, including its own (opened) allOf members.
+func (sg *SchemaGenerator) openVariantID(id string) string {
+	variant := id + variantIDSeparator + openVariant.suffix
+
+	sg.mutex.Lock()
+	_, exists := sg.schemas[variant]
+	base := sg.schemas[id]
+	sg.mutex.Unlock()
+	if exists {
+		return variant
+	}
+	if !isClosed(base) {
+		return ""
+	}
+
+	open := *base
+	open.AdditionalProperties, open.UnevaluatedProperties = nil, nil
+	if base.AllOf != nil {
+		open.AllOf = make([]*Schema, len(base.AllOf))
+		for i, member := range base.AllOf {
+			open.AllOf[i] = sg.openSchema(member)
+		}
+	}
+
+	sg.mutex.Lock()
+	sg.schemas[variant] = &open
+	if sg.variants == nil {
+		sg.variants = make(map[string]string)
+	}
+	sg.variants[variant] = id
+	sg.mutex.Unlock()
+
+	slog.Debug("[annot8] openVariantID: derived open schema variant", "base", id, "variant", variant)
+	return variant
+}
+
+// applyFieldClosure applies a field's openapi:"closed=true|false" tag to the
+// object the field holds, looking through arrays and nullable wrappers.
+// closed=true forbids undeclared members (unevaluatedProperties beside a
+// $ref); closed=false references the open variant of a closed component.
+func (sg *SchemaGenerator) applyFieldClosure(schema *Schema, tag *ast.BasicLit) *Schema {
+	if tag == nil {
+		return schema
+	}
+	openapiTag := extractTag(strings.Trim(tag.Value, "`"), "openapi")
+	for _, part := range splitTagOptions(openapiTag) {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok || strings.TrimSpace(key) != "closed" {
+			continue
+		}
+		closed, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			slog.Warn("[annot8] applyFieldClosure: closed expects true or false", "value", value)
+			return schema
+		}
+		return sg.closeField(schema, closed)
+	}
+	return schema
+}
+
+func (sg *SchemaGenerator) closeField(schema *Schema, closed bool) *Schema {
+	switch {
+	case schema == nil:
+		return nil
+	case schema.Ref != "":
+		if !closed {
+			return sg.openSchema(schema)
+		}
+		out := *schema
+		out.UnevaluatedProperties = false
+		return &out
+	case len(schema.AnyOf) > 0:
+		out := *schema
+		out.AnyOf = make([]*Schema, len(schema.AnyOf))
+		for i, sub := range schema.AnyOf {
+			out.AnyOf[i] = sg.closeField(sub, closed)
+		}
+		return &out
+	case schema.Items != nil:
+		out := *schema
+		out.Items = sg.closeField(schema.Items, closed)
+		return &out
+	case closed && schema.Properties != nil && schema.AdditionalProperties == nil:
+		out := *schema
+		out.AdditionalProperties = false
+		return &out
+	}
+	return schema
+}
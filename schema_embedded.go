@@ -0,0 +1,302 @@
+package annot8
+
+import (
+	"go/ast"
+	"log/slog"
+	"reflect"
+	"strings"
+)
+
+// EmbeddedStructMode selects how anonymous (embedded) struct fields are documented.
+type EmbeddedStructMode int
+
+const (
+	// EmbedFlatten promotes embedded fields into the parent object using the
+	// encoding/json rules: depth-based shadowing, conflict cancellation,
+	// tagged embeds as named members and optional members behind pointer
+	// embeds. This matches the wire format and is the default.
+	EmbedFlatten EmbeddedStructMode = iota
+	// EmbedAllOf documents each untagged embedded type as an allOf member
+	// next to the struct's own properties, for inheritance-style docs.
+	EmbedAllOf
+)
+
+// jsonField is a JSON object member found while walking a struct and the
+// structs it embeds, together with the source context needed to convert it.
+type jsonField struct {
+	name     string
+	goName   string // Go field name, as cross-field validate rules refer to it
+	field    *ast.Field
+	depth    int
+	tagged   bool
+	optional bool   // reached through an embedded pointer; omitted when nil
+	pkg      string // import path of the declaring struct
+	file     string // file declaring the struct
+}
+
+// embeddedLevel is one struct visited during the breadth-first walk.
+type embeddedLevel struct {
+	st        *ast.StructType
+	pkg, file string
+	optional  bool
+}
+
+// collectJSONFields returns the members encoding/json would marshal for
+// structType, in declaration order, plus allOf members for embedded types
+// whose fields cannot be promoted. In EmbedAllOf mode only the top-level
+// struct is walked and untagged embeds become allOf members.
+func (sg *SchemaGenerator) collectJSONFields(structType *ast.StructType) ([]jsonField, []*Schema) {
+	var fields []jsonField
+	var allOf []*Schema
+
+	current := []embeddedLevel{{st: structType, pkg: sg.currentPackage, file: sg.currentFile}}
+	count := map[*ast.StructType]int{structType: 1}
+	visited := make(map[*ast.StructType]bool)
+
+	for depth := 0; len(current) > 0; depth++ {
+		var next []embeddedLevel
+		nextCount := make(map[*ast.StructType]int)
+
+		for _, level := range current {
+			if visited[level.st] {
+				continue
+			}
+			visited[level.st] = true
+
+			restore := sg.enterContext(level.pkg, level.file)
+			for _, field := range level.st.Fields.List {
+				name, tagged, skip := sg.fieldName(field)
+				if skip {
+					continue
+				}
+
+				if len(field.Names) > 0 {
+					for _, ident := range field.Names {
+						if !ast.IsExported(ident.Name) {
+							continue
+						}
+						member := jsonField{
+							name:     ident.Name,
+							goName:   ident.Name,
+							field:    field,
+							depth:    depth,
+							tagged:   tagged,
+							optional: level.optional,
+							pkg:      level.pkg,
+							file:     level.file,
+						}
+						if tagged {
+							member.name = name
+						}
+						fields = appendJSONField(fields, member, count[level.st])
+					}
+					continue
+				}
+
+				// Embedded field.
+				typeName, isPointer := embeddedTypeName(field.Type)
+				if sg.embedMode == EmbedAllOf && !tagged {
+					allOf = append(allOf, sg.convertFieldType(field.Type))
+					continue
+				}
+
+				embedded, resolved := sg.resolveEmbeddedStruct(field.Type)
+				if !ast.IsExported(typeName) && embedded == nil {
+					continue
+				}
+				if tagged || (embedded == nil && resolved) {
+					member := jsonField{
+						name:     typeName,
+						goName:   typeName,
+						field:    field,
+						depth:    depth,
+						tagged:   tagged,
+						optional: level.optional,
+						pkg:      level.pkg,
+						file:     level.file,
+					}
+					if tagged {
+						member.name = name
+					}
+					fields = appendJSONField(fields, member, count[level.st])
+					continue
+				}
+				if embedded == nil {
+					// Not a struct we can see into (external or custom-marshaled):
+					// document it as an allOf member rather than guessing fields.
+					allOf = append(allOf, sg.convertFieldType(derefExpr(field.Type)))
+					continue
+				}
+
+				nextCount[embedded.st]++
+				if nextCount[embedded.st] == 1 {
+					embedded.optional = level.optional || isPointer
+					next = append(next, *embedded)
+				}
+			}
+			restore()
+		}
+
+		current, count = next, nextCount
+	}
+
+	return dominantJSONFields(fields), allOf
+}
+
+// appendJSONField records member, twice when its struct is embedded more
+// than once at the same depth so that the copies cancel each other out.
+func appendJSONField(fields []jsonField, member jsonField, times int) []jsonField {
+	fields = append(fields, member)
+	if times > 1 {
+		fields = append(fields, member)
+	}
+	return fields
+}
+
+// dominantJSONFields applies the encoding/json visibility rules: for every
+// name the shallowest member wins; at equal depth a single tagged member wins
+// and any other tie removes the name altogether.
+func dominantJSONFields(fields []jsonField) []jsonField {
+	byName := make(map[string][]jsonField, len(fields))
+	var order []string
+	for _, f := range fields {
+		if _, seen := byName[f.name]; !seen {
+			order = append(order, f.name)
+		}
+		byName[f.name] = append(byName[f.name], f)
+	}
+
+	out := make([]jsonField, 0, len(order))
+	for _, name := range order {
+		candidates := byName[name]
+		minDepth := candidates[0].depth
+		for _, c := range candidates[1:] {
+			minDepth = min(minDepth, c.depth)
+		}
+
+		var shallow, tagged []jsonField
+		for _, c := range candidates {
+			if c.depth != minDepth {
+				continue
+			}
+			shallow = append(shallow, c)
+			if c.tagged {
+				tagged = append(tagged, c)
+			}
+		}
+
+		switch {
+		case len(shallow) == 1:
+			out = append(out, shallow[0])
+		case len(tagged) == 1:
+			out = append(out, tagged[0])
+		default:
+			slog.Debug("[annot8] dominantJSONFields: conflicting embedded fields cancel", "name", name)
+		}
+	}
+	return out
+}
+
+// resolveEmbeddedStruct finds the struct declaration behind an embedded
+// field type, following aliases and defined types. resolved reports whether
+// the type was found at all; a resolved type with a nil struct is a named
+// non-struct type (for example type Code string) and marshals as a member.
+// Types with an external mapping or their own JSON/text marshaling are
+// treated as unresolved: their methods are promoted, not their fields.
+func (sg *SchemaGenerator) resolveEmbeddedStruct(expr ast.Expr) (embedded *embeddedLevel, resolved bool) {
+	if sg.typeIndex == nil {
+		return nil, false
+	}
+
+	var name string
+	switch t := derefExpr(expr).(type) {
+	case *ast.Ident:
+		name = t.Name
+	case *ast.SelectorExpr:
+		pkg, ok := t.X.(*ast.Ident)
+		if !ok {
+			return nil, false
+		}
+		name = pkg.Name + "." + t.Sel.Name
+	default:
+		return nil, false
+	}
+
+	qualified := sg.getQualifiedTypeName(name)
+	for range maxTypeChain {
+		if _, ok := sg.typeIndex.externalKnownType(qualified); ok {
+			return nil, false
+		}
+		if sg.typeIndex.inferMarshalerSchema(qualified, nil) != nil {
+			return nil, false
+		}
+
+		ts := sg.typeIndex.LookupQualifiedType(qualified)
+		if ts == nil {
+			return nil, false
+		}
+		if st, ok := ts.Type.(*ast.StructType); ok {
+			importPath, _ := sg.typeIndex.resolveID(qualified)
+			return &embeddedLevel{st: st, pkg: importPath, file: sg.typeIndex.specFiles[ts]}, true
+		}
+
+		target := sg.typeIndex.resolveAlias(sg.typeIndex.namedTarget(qualified, ts))
+		if target == "" {
+			return nil, true
+		}
+		qualified = target
+	}
+	return nil, false
+}
+
+// enterContext makes pkg and file the context for resolving type names and
+// returns a func restoring the previous context.
+func (sg *SchemaGenerator) enterContext(pkg, file string) func() {
+	oldPkg, oldFile := sg.currentPackage, sg.currentFile
+	sg.currentPackage, sg.currentFile = pkg, file
+	return func() { sg.currentPackage, sg.currentFile = oldPkg, oldFile }
+}
+
+// jsonFieldName reads the json tag of field. skip is true for json:"-";
+// tagged is true when the tag supplies a name (json:"-," names a member "-").
+func jsonFieldName(field *ast.Field) (name string, tagged, skip bool) {
+	if field.Tag == nil {
+		return "", false, false
+	}
+	value, ok := reflect.StructTag(strings.Trim(field.Tag.Value, "`")).Lookup("json")
+	if !ok {
+		return "", false, false
+	}
+	if value == "-" {
+		return "", false, true
+	}
+	name, _, _ = strings.Cut(value, ",")
+	return name, name != "", false
+}
+
+// embeddedTypeName returns the field name Go gives an embedded type (the
+// unqualified type name) and whether it is embedded through a pointer.
+func embeddedTypeName(expr ast.Expr) (string, bool) {
+	_, isPointer := expr.(*ast.StarExpr)
+	switch t := derefExpr(expr).(type) {
+	case *ast.Ident:
+		return t.Name, isPointer
+	case *ast.SelectorExpr:
+		return t.Sel.Name, isPointer
+	case *ast.IndexExpr:
+		name, _ := embeddedTypeName(t.X)
+		return name, isPointer
+	case *ast.IndexListExpr:
+		name, _ := embeddedTypeName(t.X)
+		return name, isPointer
+	}
+	return "", isPointer
+}
+
+// derefExpr strips one level of pointer from a type expression.
+func derefExpr(expr ast.Expr) ast.Expr {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		return star.X
+	}
+	return expr
+}
@@ -16,20 +16,14 @@ func (sg *SchemaGenerator) handleEnumType(qualifiedName string) *Schema {
 		return nil
 	}
 
-	ts := sg.typeIndex.LookupQualifiedType(qualifiedName)
-	if ts == nil {
-		return nil
-	}
-
-	// String-based alias enums
-	if ident, ok := ts.Type.(*ast.Ident); ok && ident.Name == "string" {
-		parts := strings.Split(qualifiedName, ".")
-		if len(parts) != 2 {
+	// String-based defined types, directly or through another defined type
+	if sg.typeIndex.underlyingBasicType(qualifiedName) == "string" {
+		importPath, typ := sg.typeIndex.resolveID(qualifiedName)
+		if importPath == "" {
 			return nil
 		}
-		pkg, typ := parts[0], parts[1]
 
-		enumValues := sg.extractEnumValues(pkg, typ)
+		enumValues := sg.extractEnumValues(importPath, typ)
 		if len(enumValues) > 0 {
 			return &Schema{
 				Type:        "string",
@@ -41,16 +35,17 @@ func (sg *SchemaGenerator) handleEnumType(qualifiedName string) *Schema {
 	return nil
 }
 
-// extractEnumValues finds constant string values for a given type in AST files.
-func (sg *SchemaGenerator) extractEnumValues(packageName, typeName string) []interface{} {
-	slog.Debug("[annot8] extractEnumValues: extracting values", "pkg", packageName, "type", typeName)
+// extractEnumValues finds constant string values for a given type in the AST
+// files of the package at importPath.
+func (sg *SchemaGenerator) extractEnumValues(importPath, typeName string) []interface{} {
+	slog.Debug("[annot8] extractEnumValues: extracting values", "importPath", importPath, "type", typeName)
 	if sg.typeIndex == nil {
 		return nil
 	}
 
 	var values []interface{}
-	for _, file := range sg.typeIndex.files {
-		if file.Name.Name != packageName {
+	for filePath, file := range sg.typeIndex.files {
+		if sg.typeIndex.filePackages[filePath] != importPath {
 			continue
 		}
 		for _, decl := range file.Decls {
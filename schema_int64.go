@@ -0,0 +1,145 @@
+package annot8
+
+import (
+	"go/ast"
+	"log/slog"
+	"strings"
+)
+
+// Int64Representation selects how int64 and uint64 values are documented.
+type Int64Representation int
+
+const (
+	// Int64AsString documents 64-bit integers as strings with an int64 or
+	// uint64 format, for APIs that quote them to survive JavaScript's 2^53
+	// number precision. This is the default.
+	Int64AsString Int64Representation = iota
+	// Int64AsInteger documents 64-bit integers as integers with an int64 or
+	// uint64 format, which is what encoding/json emits.
+	Int64AsInteger
+	// Int64FromJSONTag is Int64AsInteger. It predates fields with the
+	// ",string" json option being documented as quoted decimals under every
+	// representation.
+	Int64FromJSONTag
+)
+
+// SetInt64Representation selects how int64 and uint64 values are documented
+// in schemas, parameters and expanded query objects. Types override it with
+// an "@int64 string|integer" doc directive and fields with an
+// openapi:"int64=string|integer" tag. Fields with the ",string" json option
+// are quoted on the wire and always documented as strings.
+func (sg *SchemaGenerator) SetInt64Representation(rep Int64Representation) {
+	sg.int64Rep = rep
+}
+
+// int64Representation returns the representation in effect: the innermost
+// field or type override, then the generator option.
+func (sg *SchemaGenerator) int64Representation() Int64Representation {
+	if sg.int64Override != nil {
+		return *sg.int64Override
+	}
+	return sg.int64Rep
+}
+
+// mapGoType maps a predeclared type like mapGoTypeToOpenAPI, documenting
+// int64 and uint64 with the representation in effect.
+func (sg *SchemaGenerator) mapGoType(typeName string) (string, string) {
+	openapiType, format := mapGoTypeToOpenAPI(typeName)
+	if is64BitInteger(typeName) && sg.int64Representation() != Int64AsString {
+		openapiType = "integer"
+	}
+	return openapiType, format
+}
+
+// marshalerSchema describes a type with marshaling methods. Its own
+// MarshalJSON decides its output, so the generator option does not apply: a
+// type defined over int64 or uint64 is documented as the number it
+// conventionally writes, unless its "@int64 string" directive says it quotes
+// it.
+func (sg *SchemaGenerator) marshalerSchema(qualifiedName string) *Schema {
+	rep := Int64AsInteger
+	if ts := sg.typeIndex.LookupQualifiedType(strings.TrimLeft(qualifiedName, "*")); ts != nil {
+		restore := sg.enterTypeInt64Override(ts)
+		if sg.int64Override != nil {
+			rep = *sg.int64Override
+		}
+		restore()
+	}
+	return sg.typeIndex.inferMarshalerSchema(qualifiedName, func(kind string) (string, string) {
+		openapiType, format := mapGoTypeToOpenAPI(kind)
+		if is64BitInteger(kind) && rep != Int64AsString {
+			openapiType = "integer"
+		}
+		return openapiType, format
+	})
+}
+
+// withInt64Override makes rep the representation in effect when ok is set,
+// or clears any enclosing override when ok is false, and returns a func
+// restoring the previous state.
+func (sg *SchemaGenerator) withInt64Override(rep Int64Representation, ok bool) func() {
+	old := sg.int64Override
+	if ok {
+		sg.int64Override = &rep
+	} else {
+		sg.int64Override = nil
+	}
+	return func() { sg.int64Override = old }
+}
+
+// enterFieldInt64Override applies a field's openapi:"int64=..." tag, if any,
+// and returns a func restoring the previous state.
+func (sg *SchemaGenerator) enterFieldInt64Override(tag *ast.BasicLit) func() {
+	if tag == nil {
+		return func() {}
+	}
+	openapiTag := extractTag(strings.Trim(tag.Value, "`"), "openapi")
+	for _, part := range strings.Split(openapiTag, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok || strings.TrimSpace(key) != "int64" {
+			continue
+		}
+		if rep, ok := parseInt64Representation(value); ok {
+			return sg.withInt64Override(rep, true)
+		}
+		slog.Warn("[annot8] enterFieldInt64Override: unknown int64 representation", "value", value)
+	}
+	return func() {}
+}
+
+// enterTypeInt64Override scopes the representation to a named type's own
+// "@int64" directive so that field overrides never leak into components.
+func (sg *SchemaGenerator) enterTypeInt64Override(ts *ast.TypeSpec) func() {
+	doc := sg.typeIndex.specDocs[ts]
+	if doc != nil {
+		for _, line := range strings.Split(doc.Text(), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) < 2 || fields[0] != "@int64" {
+				continue
+			}
+			if rep, ok := parseInt64Representation(fields[1]); ok {
+				return sg.withInt64Override(rep, true)
+			}
+			slog.Warn("[annot8] enterTypeInt64Override: unknown int64 representation", "value", fields[1])
+		}
+	}
+	return sg.withInt64Override(0, false)
+}
+
+// parseInt64Representation reads an override value. "number" and "integer"
+// both mean a JSON number; "json" defers to the field's ",string" option.
+func parseInt64Representation(value string) (Int64Representation, bool) {
+	switch strings.TrimSpace(value) {
+	case "string":
+		return Int64AsString, true
+	case "integer", "number":
+		return Int64AsInteger, true
+	case "json":
+		return Int64FromJSONTag, true
+	}
+	return 0, false
+}
+
+func is64BitInteger(kind string) bool {
+	return kind == "int64" || kind == "uint64"
+}
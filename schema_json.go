@@ -0,0 +1,210 @@
+package annot8
+
+import (
+	"go/ast"
+	"go/token"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// encodingJSONTypes maps standard library types whose encoding/json output
+// differs from what their declaration suggests. Keys are full import paths.
+var encodingJSONTypes = map[string]*Schema{
+	// RawMessage is emitted verbatim: any JSON value.
+	"encoding/json.RawMessage": {},
+	// Number is emitted as a number literal despite being a string type.
+	"encoding/json.Number": {Type: "number"},
+	// Duration has no marshaling methods and encodes as int64 nanoseconds.
+	"time.Duration": {Type: "integer", Format: "int64", Description: "Duration in nanoseconds"},
+}
+
+// builtinJSONSchema returns the encoding/json schema for a standard library
+// type identifier ("json.RawMessage" or "encoding/json.RawMessage").
+func (idx *TypeIndex) builtinJSONSchema(id string) (*Schema, bool) {
+	if schema, ok := encodingJSONTypes[id]; ok {
+		return cloneSchema(schema), true
+	}
+	if idx == nil {
+		return nil, false
+	}
+	pkg, importPath, typeName := idx.schemaIdentity(id)
+	if importPath == "" || importPath == pkg {
+		// Unindexed short form: the standard library packages are unambiguous.
+		switch pkg {
+		case "json":
+			importPath = "encoding/json"
+		case "time":
+			importPath = "time"
+		}
+	}
+	if schema, ok := encodingJSONTypes[importPath+"."+typeName]; ok {
+		return cloneSchema(schema), true
+	}
+	return nil, false
+}
+
+// isAnyType reports whether name denotes the empty interface.
+func isAnyType(name string) bool {
+	return name == "any" || name == "interface{}"
+}
+
+// byteSliceSchema describes []byte, which encoding/json emits as a base64 string.
+func byteSliceSchema() *Schema {
+	return &Schema{Type: "string", ContentEncoding: "base64"}
+}
+
+// isByteType reports whether name is byte or its synonym uint8.
+func isByteType(name string) bool {
+	return name == "byte" || name == "uint8"
+}
+
+// fixedArrayLength returns N for a [N]T type expression with a literal
+// length, or -1 for slices and lengths given by constants.
+func fixedArrayLength(arr *ast.ArrayType) int {
+	lit, ok := arr.Len.(*ast.BasicLit)
+	if !ok || lit.Kind != token.INT {
+		return -1
+	}
+	n, err := strconv.ParseInt(lit.Value, 0, 0)
+	if err != nil {
+		return -1
+	}
+	return int(n)
+}
+
+// mapKeySchema returns the propertyNames schema for a map key type, following
+// encoding/json: string kinds are used as-is (enums keep their ref),
+// TextMarshaler keys are strings and integer kinds are decimal strings.
+// It returns nil for plain string keys.
+func (sg *SchemaGenerator) mapKeySchema(keyType string) *Schema {
+	if keyType == "string" {
+		return nil
+	}
+	if isBasicType(keyType) {
+		return integerKeySchema(keyType)
+	}
+	if sg.typeIndex == nil {
+		return nil
+	}
+
+	qualified := sg.getQualifiedTypeName(keyType)
+	underlying := sg.typeIndex.underlyingBasicType(qualified)
+	if underlying == "string" {
+		return sg.GenerateSchema(keyType)
+	}
+	if hint, ok := sg.typeIndex.typeJSONHints[sg.hintKey(qualified)]; ok && hint.hasMarshalText {
+		return &Schema{Type: "string"}
+	}
+	return integerKeySchema(underlying)
+}
+
+// hintKey returns the typeJSONHints key for a schema identifier.
+func (sg *SchemaGenerator) hintKey(id string) string {
+	importPath, typeName := sg.typeIndex.resolveID(id)
+	return importPath + "." + typeName
+}
+
+// integerKeySchema describes an integer map key rendered as a decimal string.
+func integerKeySchema(kind string) *Schema {
+	switch kind {
+	case "int", "int8", "int16", "int32", "int64", "rune":
+		return &Schema{Type: "string", Pattern: "^-?[0-9]+$"}
+	case "uint", "uint8", "uint16", "uint32", "uint64", "byte", "uintptr":
+		return &Schema{Type: "string", Pattern: "^[0-9]+$"}
+	}
+	return nil
+}
+
+// exprTypeName renders a type expression as the name GenerateSchema accepts
+// (for example "string", "pkg.Type", "[]pkg.Type").
+func exprTypeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.SelectorExpr:
+		if pkg, ok := t.X.(*ast.Ident); ok {
+			return pkg.Name + "." + t.Sel.Name
+		}
+	case *ast.StarExpr:
+		return "*" + exprTypeName(t.X)
+	case *ast.ArrayType:
+		return "[]" + exprTypeName(t.Elt)
+	case *ast.MapType:
+		return "map[" + exprTypeName(t.Key) + "]" + exprTypeName(t.Value)
+	case *ast.InterfaceType:
+		return "interface{}"
+	}
+	return ""
+}
+
+// hasJSONStringOption reports whether the json tag carries the ",string"
+// option, which makes encoding/json quote numbers and booleans.
+func hasJSONStringOption(tag *ast.BasicLit) bool {
+	if tag == nil {
+		return false
+	}
+	value, ok := reflect.StructTag(strings.Trim(tag.Value, "`")).Lookup("json")
+	if !ok {
+		return false
+	}
+	options := strings.Split(value, ",")
+	for _, opt := range options[1:] {
+		if opt == "string" {
+			return true
+		}
+	}
+	return false
+}
+
+// stringOptionSchema returns the schema of a field marshaled with the
+// ",string" option, or nil when the option does not apply. encoding/json
+// honors it for numbers and booleans (and pointers to them) without their
+// own marshaling methods; strings gain an extra layer of quoting but remain
+// strings, so they are left alone.
+func (sg *SchemaGenerator) stringOptionSchema(expr ast.Expr) *Schema {
+	nullable := isPointerType(expr)
+	kind := sg.scalarKind(derefExpr(expr))
+
+	var schema *Schema
+	switch kind {
+	case "bool":
+		schema = &Schema{Type: "string", Enum: []any{"true", "false"}}
+	case "float32", "float64":
+		_, format := mapGoTypeToOpenAPI(kind)
+		schema = &Schema{Type: "string", Format: format}
+	default:
+		keySchema := integerKeySchema(kind)
+		if keySchema == nil {
+			return nil
+		}
+		_, format := mapGoTypeToOpenAPI(kind)
+		schema = &Schema{Type: "string", Format: format, Pattern: keySchema.Pattern}
+	}
+
+	if nullable {
+		return sg.nullable(schema)
+	}
+	return schema
+}
+
+// scalarKind returns the predeclared type a field type expression marshals
+// as, following defined types, or "" when it has its own marshaling methods
+// or is not a predeclared type.
+func (sg *SchemaGenerator) scalarKind(expr ast.Expr) string {
+	name := exprTypeName(expr)
+	if name == "" || strings.ContainsAny(name, "[]*") {
+		return ""
+	}
+	if isBasicType(name) {
+		return name
+	}
+	if sg.typeIndex == nil {
+		return ""
+	}
+	qualified := sg.getQualifiedTypeName(name)
+	if sg.typeIndex.inferMarshalerSchema(qualified, nil) != nil {
+		return ""
+	}
+	return sg.typeIndex.underlyingBasicType(qualified)
+}
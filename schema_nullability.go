@@ -0,0 +1,159 @@
+package annot8
+
+import (
+	"slices"
+	"strings"
+)
+
+// NullabilityStrategy selects how schemas that also accept null are written.
+type NullabilityStrategy int
+
+const (
+	// NullTypeArray adds "null" to the type of inline schemas
+	// (type: [string, "null"]) and wraps references and compositions in
+	// anyOf with {type: "null"}. This is the default.
+	NullTypeArray NullabilityStrategy = iota
+	// NullAnyOf writes every nullable schema as anyOf: [T, {type: "null"}],
+	// for tools that do not understand type arrays.
+	NullAnyOf
+)
+
+// SetNullabilityStrategy selects how pointers, nullable wrapper types and
+// other nullable values are documented. External type mappings written in
+// another form are rewritten to match.
+func (sg *SchemaGenerator) SetNullabilityStrategy(strategy NullabilityStrategy) {
+	sg.nullability = strategy
+}
+
+// nullable returns schema documented as also accepting null, in the form the
+// strategy selects. Schemas that already accept null, including the empty
+// schema, are returned as they are.
+func (sg *SchemaGenerator) nullable(schema *Schema) *Schema {
+	if schema == nil || isNullable(schema) || isEmptySchema(schema) {
+		return schema
+	}
+
+	if sg.nullability == NullTypeArray {
+		if types := typeNames(schema); types != nil && schema.Ref == "" && len(schema.AnyOf) == 0 && len(schema.OneOf) == 0 {
+			out := *schema
+			out.Type = append(types, "null")
+			if out.Enum != nil {
+				out.Enum = append(append([]any(nil), out.Enum...), nil)
+			}
+			return &out
+		}
+	}
+
+	// A union gains a null member rather than being nested in another anyOf.
+	if schema.Ref == "" && schema.Type == nil && len(schema.AnyOf) > 0 {
+		out := *schema
+		out.AnyOf = append(append([]*Schema(nil), schema.AnyOf...), &Schema{Type: "null"})
+		return &out
+	}
+	return &Schema{AnyOf: []*Schema{schema, {Type: "null"}}}
+}
+
+// normalizeNullability rewrites a nullable schema written in another form,
+// such as a preset's type array, to the strategy's form.
+func (sg *SchemaGenerator) normalizeNullability(schema *Schema) *Schema {
+	if schema == nil || sg.nullability == NullTypeArray || !hasType(schema, "null") {
+		return schema
+	}
+	stripped := cloneSchema(schema)
+	stripNull(stripped)
+	return sg.nullable(stripped)
+}
+
+// stripNull removes null from schema in every form the strategies write:
+// its type list, its enum and its anyOf members, collapsing an anyOf left
+// with a single member into that member. It reports whether schema accepted
+// null. Members are copied before they are changed.
+func stripNull(schema *Schema) bool {
+	if schema == nil {
+		return false
+	}
+	nullable := isNullable(schema)
+
+	switch schema.Type.(type) {
+	case []string, []any:
+		kept := slices.DeleteFunc(typeNames(schema), func(t string) bool { return t == "null" })
+		switch len(kept) {
+		case 0:
+			schema.Type = nil
+		case 1:
+			schema.Type = kept[0]
+		default:
+			schema.Type = kept
+		}
+	}
+	if schema.Enum != nil {
+		schema.Enum = slices.DeleteFunc(slices.Clone(schema.Enum), func(v any) bool { return v == nil })
+	}
+
+	if len(schema.AnyOf) == 0 {
+		return nullable
+	}
+	kept := make([]*Schema, 0, len(schema.AnyOf))
+	for _, member := range schema.AnyOf {
+		if member == nil || member.Type == "null" {
+			continue
+		}
+		member = cloneSchema(member)
+		stripNull(member)
+		kept = append(kept, member)
+	}
+	if len(kept) == 1 && schema.Ref == "" && schema.Type == nil && len(schema.Properties) == 0 && schema.Items == nil {
+		// anyOf [T, null] from a pointer field or NullAnyOf: T alone.
+		*schema = *kept[0]
+	} else {
+		schema.AnyOf = kept
+	}
+	return nullable
+}
+
+// trimNullableWording drops the "Nullable ..." wording of a nullable type
+// mapping's description once null has been stripped from it.
+func trimNullableWording(schema *Schema) {
+	if rest, ok := strings.CutPrefix(schema.Description, "Nullable "); ok {
+		schema.Description = capitalize(rest)
+	}
+}
+
+// typeNames returns a copy of schema's type names, or nil when it has none.
+func typeNames(schema *Schema) []string {
+	switch t := schema.Type.(type) {
+	case string:
+		return []string{t}
+	case []string:
+		return append([]string(nil), t...)
+	case []any:
+		names := make([]string, 0, len(t))
+		for _, v := range t {
+			if s, ok := v.(string); ok {
+				names = append(names, s)
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+// isNullable reports whether schema accepts null in any of the strategies'
+// forms.
+func isNullable(schema *Schema) bool {
+	if hasType(schema, "null") {
+		return true
+	}
+	for _, member := range schema.AnyOf {
+		if member != nil && member.Type == "null" {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmptySchema reports whether schema accepts any JSON value.
+func isEmptySchema(schema *Schema) bool {
+	return schema.Type == nil && schema.Ref == "" && len(schema.AnyOf) == 0 &&
+		len(schema.OneOf) == 0 && len(schema.AllOf) == 0 && schema.Not == nil
+}
@@ -0,0 +1,274 @@
+package annot8
+
+import (
+	"go/ast"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+// Implementations documents the concrete types that an interface-typed field
+// may hold. The interface is emitted as a oneOf over the implementation
+// components, with a discriminator mapping when Discriminator is set.
+type Implementations struct {
+	// Discriminator is the JSON property whose value names the concrete type.
+	Discriminator string
+	// Types lists implementation type names ("card.Card"). Each maps from the
+	// discriminator value equal to its unqualified type name ("Card").
+	Types []string
+	// Mapping maps explicit discriminator values to implementation type names.
+	Mapping map[string]string
+}
+
+// RegisterImplementations declares the implementations of an interface type
+// ("payment.Method") on the shared TypeIndex. Registered implementations take
+// precedence over @discriminator/@oneOf doc directives and the implementer scan.
+func RegisterImplementations(iface string, impls Implementations) {
+	ensureTypeIndex()
+	if typeIndex == nil {
+		slog.Error("[annot8] RegisterImplementations: typeIndex is nil")
+		return
+	}
+	typeIndex.RegisterImplementations(iface, impls)
+}
+
+// RegisterImplementations declares the implementations of an interface type
+// for this index.
+func (idx *TypeIndex) RegisterImplementations(iface string, impls Implementations) {
+	if idx == nil {
+		return
+	}
+	if idx.implementations == nil {
+		idx.implementations = make(map[string]Implementations)
+	}
+	idx.implementations[iface] = impls
+}
+
+// implementation is one oneOf member with its discriminator value.
+type implementation struct {
+	value    string
+	typeName string
+}
+
+// interfaceSchema documents a named interface type. Implementations come from
+// the registry, then from @discriminator/@oneOf directives on the interface,
+// then from scanning project types whose method sets cover the interface.
+// Without implementations the interface accepts any JSON value.
+func (sg *SchemaGenerator) interfaceSchema(qualifiedName string, ts *ast.TypeSpec, iface *ast.InterfaceType) *Schema {
+	discriminator, members := sg.registeredImplementations(qualifiedName)
+	if len(members) == 0 {
+		discriminator, members = parseImplementationDirectives(sg.typeIndex.specDocs[ts])
+	}
+	if len(members) == 0 {
+		members = sg.typeIndex.scanImplementations(qualifiedName, iface)
+	}
+	if len(members) == 0 {
+		return &Schema{}
+	}
+
+	schema := &Schema{}
+	mapping := make(map[string]string, len(members))
+	for _, member := range members {
+		ref := sg.GenerateSchema(member.typeName)
+		schema.OneOf = append(schema.OneOf, ref)
+		if ref.Ref != "" {
+			mapping[member.value] = ref.Ref
+		}
+	}
+	if discriminator != "" {
+		schema.Discriminator = &Discriminator{PropertyName: discriminator, Mapping: mapping}
+	}
+
+	slog.Debug(
+		"[annot8] interfaceSchema: documented implementations",
+		"interface", qualifiedName,
+		"count", len(members),
+		"discriminator", discriminator,
+	)
+	return schema
+}
+
+// registeredImplementations returns the registry entry whose interface name
+// resolves to qualifiedName.
+func (sg *SchemaGenerator) registeredImplementations(qualifiedName string) (string, []implementation) {
+	for iface, impls := range sg.typeIndex.implementations {
+		restore := sg.enterContext("", "")
+		resolved := sg.getQualifiedTypeName(iface)
+		restore()
+		if resolved != qualifiedName && iface != qualifiedName {
+			continue
+		}
+
+		members := make([]implementation, 0, len(impls.Types)+len(impls.Mapping))
+		for _, typeName := range impls.Types {
+			members = append(members, implementation{value: unqualifiedName(typeName), typeName: typeName})
+		}
+		values := make([]string, 0, len(impls.Mapping))
+		for value := range impls.Mapping {
+			values = append(values, value)
+		}
+		sort.Strings(values)
+		for _, value := range values {
+			members = append(members, implementation{value: value, typeName: impls.Mapping[value]})
+		}
+		return impls.Discriminator, members
+	}
+	return "", nil
+}
+
+// parseImplementationDirectives reads "@discriminator <property>" and
+// "@oneOf <Type> <value>=<Type> ..." lines from an interface's doc comment.
+func parseImplementationDirectives(doc *ast.CommentGroup) (string, []implementation) {
+	if doc == nil {
+		return "", nil
+	}
+
+	var discriminator string
+	var members []implementation
+	for _, line := range strings.Split(doc.Text(), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "@discriminator":
+			if len(fields) > 1 {
+				discriminator = fields[1]
+			}
+		case "@oneOf":
+			for _, entry := range fields[1:] {
+				value, typeName, explicit := strings.Cut(entry, "=")
+				if !explicit {
+					typeName, value = entry, unqualifiedName(entry)
+				}
+				members = append(members, implementation{value: value, typeName: typeName})
+			}
+		}
+	}
+	return discriminator, members
+}
+
+// scanImplementations finds project types whose declared methods (value or
+// pointer receivers) cover the interface's method names. Signatures are not
+// compared. Unexported methods can only be satisfied within the interface's
+// own package. It returns nil when the method set cannot be determined.
+func (idx *TypeIndex) scanImplementations(qualifiedName string, iface *ast.InterfaceType) []implementation {
+	ifacePath, _ := idx.resolveID(qualifiedName)
+	required, ok := idx.interfaceMethods(ifacePath, idx.specFiles[idx.LookupQualifiedType(qualifiedName)], iface, 0)
+	if !ok || len(required) == 0 {
+		return nil
+	}
+	sealed := false
+	for name := range required {
+		if !ast.IsExported(name) {
+			sealed = true
+		}
+	}
+
+	importPaths := make([]string, 0, len(idx.types))
+	for importPath := range idx.types {
+		if idx.externalPkgs[importPath] || (sealed && importPath != ifacePath) {
+			continue
+		}
+		importPaths = append(importPaths, importPath)
+	}
+	sort.Strings(importPaths)
+
+	var members []implementation
+	for _, importPath := range importPaths {
+		names := make([]string, 0, len(idx.types[importPath]))
+		for name, ts := range idx.types[importPath] {
+			if _, isIface := ts.Type.(*ast.InterfaceType); isIface || ts.Assign.IsValid() {
+				continue
+			}
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			methods := idx.methodSets[importPath+"."+name]
+			if coversMethods(methods, required) {
+				members = append(members, implementation{value: name, typeName: idx.typeID(importPath, name)})
+			}
+		}
+	}
+	return members
+}
+
+// interfaceMethods collects the method names of iface, including those of
+// embedded interfaces declared in the index and the predeclared error.
+func (idx *TypeIndex) interfaceMethods(
+	importPath, file string,
+	iface *ast.InterfaceType,
+	depth int,
+) (map[string]bool, bool) {
+	if iface.Methods == nil || depth > maxTypeChain {
+		return map[string]bool{}, iface.Methods == nil
+	}
+
+	methods := make(map[string]bool)
+	for _, field := range iface.Methods.List {
+		if len(field.Names) > 0 {
+			for _, name := range field.Names {
+				methods[name.Name] = true
+			}
+			continue
+		}
+
+		var embeddedPath, embeddedName string
+		switch t := field.Type.(type) {
+		case *ast.Ident:
+			if t.Name == "error" {
+				methods["Error"] = true
+				continue
+			}
+			embeddedPath, embeddedName = importPath, t.Name
+		case *ast.SelectorExpr:
+			pkg, ok := t.X.(*ast.Ident)
+			if !ok {
+				return nil, false
+			}
+			embeddedPath, embeddedName = idx.resolveID(idx.resolveQualified(pkg.Name, t.Sel.Name, file))
+		default:
+			// Type-set constraints (~int | ~string) are not value interfaces.
+			return nil, false
+		}
+
+		ts := idx.types[embeddedPath][embeddedName]
+		if ts == nil {
+			return nil, false
+		}
+		embedded, ok := ts.Type.(*ast.InterfaceType)
+		if !ok {
+			return nil, false
+		}
+		inner, ok := idx.interfaceMethods(embeddedPath, idx.specFiles[ts], embedded, depth+1)
+		if !ok {
+			return nil, false
+		}
+		for name := range inner {
+			methods[name] = true
+		}
+	}
+	return methods, true
+}
+
+func coversMethods(methods, required map[string]bool) bool {
+	if len(methods) < len(required) {
+		return false
+	}
+	for name := range required {
+		if !methods[name] {
+			return false
+		}
+	}
+	return true
+}
+
+// unqualifiedName strips the package qualifier from a type name.
+func unqualifiedName(typeName string) string {
+	if dot := strings.LastIndex(typeName, "."); dot != -1 {
+		return typeName[dot+1:]
+	}
+	return typeName
+}
@@ -4,19 +4,25 @@ import (
 	"go/ast"
 	"log/slog"
 	"reflect"
+	"slices"
 	"strings"
 )
 
 // convertStructToSchema converts a Go AST struct type into an OpenAPI object schema.
+// Embedded structs are flattened or kept as allOf members according to the
+// generator's EmbeddedStructMode.
 func (sg *SchemaGenerator) convertStructToSchema(structType *ast.StructType) *Schema {
 	slog.Debug("[annot8] convertStructToSchema: called")
 
-	var allOf []*Schema
 	properties := make(map[string]*Schema)
 	var required []string
 	sqlcNullValueFieldJSONName, isSQLCNullWrapper := sg.detectSQLCNullWrapper(structType)
+	model := sg.sqlcModel()
 
 	for _, field := range structType.Fields.List {
+		if _, _, skip := sg.fieldName(field); skip {
+			continue
+		}
 		// Ensure dependent schemas generated for the field type
 		switch t := field.Type.(type) {
 		case *ast.Ident:
@@ -35,78 +41,106 @@ func (sg *SchemaGenerator) convertStructToSchema(structType *ast.StructType) *Sc
 				_ = sg.GenerateSchema(qualified)
 			}
 		}
+	}
 
-		if len(field.Names) == 0 {
-			// Handle embedded field
-			embeddedSchema := sg.convertFieldType(field.Type)
-			allOf = append(allOf, embeddedSchema)
-			continue
-		}
-
-		for _, nameIdent := range field.Names {
-			fieldName := nameIdent.Name
-			if !ast.IsExported(fieldName) {
-				continue // skip unexported
+	fields, allOf := sg.collectJSONFields(structType)
+	for _, member := range fields {
+		field, jsonName := member.field, member.name
+		isSQLCNullValue := isSQLCNullWrapper && member.depth == 0 && jsonName == sqlcNullValueFieldJSONName
+
+		// Convert field type in the context of the struct declaring it
+		restore := sg.enterContext(member.pkg, member.file)
+		restoreInt64 := sg.enterFieldInt64Override(field.Tag)
+		fieldSchema := sg.convertFieldType(field.Type)
+		if !sg.xmlMode && hasJSONStringOption(field.Tag) {
+			if quoted := sg.stringOptionSchema(field.Type); quoted != nil {
+				fieldSchema = quoted
 			}
+		}
+		if isSQLCNullValue {
+			fieldSchema = sg.wrapSQLCNullWrapperValueSchema(fieldSchema)
+		}
 
-			// Determine JSON property name
-			jsonName := fieldName
-			if field.Tag != nil {
-				tag := strings.Trim(field.Tag.Value, "`")
-				jsonTag := extractJSONTag(tag)
-				if jsonTag == "-" {
-					continue
-				}
-				if jsonTag != "" {
-					jsonName = jsonTag
-				}
+		if model != nil {
+			if column := model.column(member); column != nil {
+				applyColumnConstraints(fieldSchema, column)
 			}
+		}
+		fieldSchema = sg.applyFieldClosure(fieldSchema, field.Tag)
 
-			// Convert field type
-			fieldSchema := sg.convertFieldType(field.Type)
-			if isSQLCNullWrapper && jsonName == sqlcNullValueFieldJSONName {
-				fieldSchema = wrapSQLCNullWrapperValueSchema(fieldSchema)
-			}
+		// Apply struct tag enhancements ONLY if not a reference schema
+		// References should not have sibling properties per OpenAPI 3.1 spec
+		if field.Tag != nil && fieldSchema.Ref == "" {
+			tag := strings.Trim(field.Tag.Value, "`")
+			sg.currentField = jsonName
+			sg.applyEnhancedTags(fieldSchema, tag)
+		}
+		restoreInt64()
+		restore()
 
-			// Apply struct tag enhancements ONLY if not a reference schema
-			// References should not have sibling properties per OpenAPI 3.1 spec
-			if field.Tag != nil && fieldSchema.Ref == "" {
-				tag := strings.Trim(field.Tag.Value, "`")
-				sg.applyEnhancedTags(fieldSchema, tag)
+		if sg.xmlMode {
+			jsonName = placeXMLProperty(properties, member, fieldSchema)
+			if slices.Contains(required, jsonName) {
+				continue
 			}
-
+		} else {
 			properties[jsonName] = fieldSchema
+		}
 
-			// Determine required fields
-			if isSQLCNullWrapper && jsonName == sqlcNullValueFieldJSONName {
-				continue
-			}
-			if !isPointerType(field.Type) && !hasOmitEmpty(field.Tag) {
+		// Determine required fields; members promoted through an embedded
+		// pointer are omitted whenever that pointer is nil.
+		if isSQLCNullValue || member.optional {
+			continue
+		}
+		if sg.requiredPolicy == RequiredFromValidate {
+			if hasValidateRequired(field.Tag) {
 				required = append(required, jsonName)
 			}
+		} else if !isPointerType(field.Type) && !sg.omitsEmpty(field) {
+			required = append(required, jsonName)
 		}
 	}
+	dependent, conditionals := validateConditionals(fields, properties)
 
 	if len(allOf) == 0 {
-		return &Schema{
+		object := &Schema{
 			Type:       "object",
 			Properties: properties,
 			Required:   required,
 		}
+		applyConditionals(object, dependent, conditionals)
+		if sg.xmlMode {
+			object.XML = sg.xmlRootSchema(structType)
+		}
+		if model != nil {
+			object.Description = model.table.comment
+		}
+		return object
 	}
 
 	// if we have local properties, add as anonymous object to allOf
 	if len(properties) > 0 {
-		allOf = append(allOf, &Schema{
+		object := &Schema{
 			Type:       "object",
 			Properties: properties,
 			Required:   required,
-		})
+		}
+		applyConditionals(object, dependent, conditionals)
+		allOf = append(allOf, object)
 	}
 
-	return &Schema{
+	// Members reference open variants of closed components, which would
+	// otherwise reject the properties their siblings contribute.
+	for i, member := range allOf {
+		allOf[i] = sg.openSchema(member)
+	}
+	composed := &Schema{
 		AllOf: allOf,
 	}
+	if sg.xmlMode {
+		composed.XML = sg.xmlRootSchema(structType)
+	}
+	return composed
 }
 
 // convertFieldType inspects a Go AST expression and returns its OpenAPI schema representation.
@@ -116,8 +150,11 @@ func (sg *SchemaGenerator) convertFieldType(expr ast.Expr) *Schema {
 
 	switch t := expr.(type) {
 	case *ast.Ident:
+		if isAnyType(t.Name) {
+			return &Schema{}
+		}
 		// Basic Go types
-		basicType, basicFormat := mapGoTypeToOpenAPI(t.Name)
+		basicType, basicFormat := sg.mapGoType(t.Name)
 		if basicType != "object" {
 			schema := &Schema{Type: basicType}
 			if basicFormat != "" {
@@ -135,43 +172,34 @@ func (sg *SchemaGenerator) convertFieldType(expr ast.Expr) *Schema {
 		// which must not mutate the shared canonical entry.
 		if ident, ok := t.X.(*ast.Ident); ok {
 			qualified := "*" + sg.getQualifiedTypeName(ident.Name)
-			if sg.typeIndex != nil {
-				if schema, ok := sg.typeIndex.externalKnownTypes[qualified]; ok {
-					return cloneSchema(schema)
-				}
+			if schema, ok := sg.typeIndex.externalKnownType(qualified); ok {
+				return sg.normalizeNullability(cloneSchema(schema))
 			}
 		} else if sel, ok := t.X.(*ast.SelectorExpr); ok {
 			if ident, ok := sel.X.(*ast.Ident); ok {
-				qualified := "*" + ident.Name + "." + sel.Sel.Name
-				if sg.typeIndex != nil {
-					if schema, ok := sg.typeIndex.externalKnownTypes[qualified]; ok {
-						return cloneSchema(schema)
-					}
+				qualified := "*" + sg.getQualifiedTypeName(ident.Name+"."+sel.Sel.Name)
+				if schema, ok := sg.typeIndex.externalKnownType(qualified); ok {
+					return sg.normalizeNullability(cloneSchema(schema))
 				}
 			}
 		}
 
-		// Fallback: Pointer types: wrap with nullability to support OAS 3.1
-		underlying := sg.convertFieldType(t.X)
-		if underlying.Ref != "" {
-			// For references, we use anyOf to avoid type conflicts (e.g. if the ref is a string enum)
-			return &Schema{
-				AnyOf: []*Schema{
-					underlying,
-					{Type: "null"},
-				},
-			}
-		}
-
-		if tStr, ok := underlying.Type.(string); ok {
-			underlying.Type = []string{tStr, "null"}
-		}
-		return underlying
+		// Fallback: Pointer types accept null in the strategy's form
+		return sg.nullable(sg.convertFieldType(t.X))
 
 	case *ast.ArrayType:
+		// Byte slices are base64 strings; byte arrays stay arrays of numbers.
+		if ident, ok := t.Elt.(*ast.Ident); ok && t.Len == nil && isByteType(ident.Name) {
+			return sg.byteSliceSchema()
+		}
 		// Arrays and slices
 		elem := sg.convertFieldType(t.Elt)
-		return &Schema{Type: "array", Items: elem}
+		schema := &Schema{Type: "array", Items: elem}
+		if n := fixedArrayLength(t); n >= 0 {
+			schema.MinItems = &n
+			schema.MaxItems = &n
+		}
+		return schema
 
 	case *ast.SelectorExpr:
 		// Qualified types (e.g., time.Time)
@@ -182,11 +210,26 @@ func (sg *SchemaGenerator) convertFieldType(expr ast.Expr) *Schema {
 
 	case *ast.MapType:
 		// Maps as object with additionalProperties
-		return &Schema{Type: "object", AdditionalProperties: sg.convertFieldType(t.Value)}
+		return &Schema{
+			Type:                 "object",
+			AdditionalProperties: sg.convertFieldType(t.Value),
+			PropertyNames:        sg.mapKeySchema(exprTypeName(t.Key)),
+		}
 
 	case *ast.InterfaceType:
-		// Empty interface as object
-		return &Schema{Type: "object"}
+		// Interfaces marshal their dynamic value: any JSON value
+		return &Schema{}
+
+	case *ast.IndexExpr:
+		// Generic instantiations (pgtype.Range[pgtype.Int4]) use the generic type's schema
+		if name := exprTypeName(t.X); name != "" {
+			return sg.GenerateSchema(name)
+		}
+
+	case *ast.IndexListExpr:
+		if name := exprTypeName(t.X); name != "" {
+			return sg.GenerateSchema(name)
+		}
 	}
 
 	slog.Debug("[annot8] convertFieldType: unknown type, defaulting to object")
@@ -223,7 +266,7 @@ func hasOmitEmpty(tag *ast.BasicLit) bool {
 }
 
 func (sg *SchemaGenerator) detectSQLCNullWrapper(structType *ast.StructType) (string, bool) {
-	if sg.currentPackage != "sqlc" || structType == nil {
+	if sg.typeIndex == nil || sg.typeIndex.packageName(sg.currentPackage) != "sqlc" || structType == nil {
 		return "", false
 	}
 
@@ -272,24 +315,21 @@ func isBoolType(expr ast.Expr) bool {
 	return ok && ident.Name == "bool"
 }
 
-func wrapSQLCNullWrapperValueSchema(fieldSchema *Schema) *Schema {
+func (sg *SchemaGenerator) wrapSQLCNullWrapperValueSchema(fieldSchema *Schema) *Schema {
 	if fieldSchema == nil {
 		return nil
 	}
 
-	anyOf := []*Schema{
-		cloneSchema(fieldSchema),
-		{Type: "null"},
+	nullable := sg.nullable(cloneSchema(fieldSchema))
+	if !schemaSupportsString(fieldSchema) {
+		return nullable
 	}
-
-	if schemaSupportsString(fieldSchema) {
-		anyOf = append(anyOf, &Schema{
-			Type: "string",
-			Enum: []any{""},
-		})
+	empty := &Schema{Type: "string", Enum: []any{""}}
+	if nullable.Ref == "" && nullable.Type == nil && len(nullable.AnyOf) > 0 {
+		nullable.AnyOf = append(nullable.AnyOf, empty)
+		return nullable
 	}
-
-	return &Schema{AnyOf: anyOf}
+	return &Schema{AnyOf: []*Schema{nullable, empty}}
 }
 
 func schemaSupportsString(s *Schema) bool {
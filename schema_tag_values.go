@@ -0,0 +1,292 @@
+package annot8
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// splitTagOptions splits an openapi tag on top-level commas, keeping commas
+// inside [...], {...} and double-quoted strings so that values such as
+// example=[a,b] and example={"a":1,"b":2} stay intact.
+func splitTagOptions(tag string) []string {
+	var parts []string
+	depth, start := 0, 0
+	inString, escaped := false, false
+	for i := 0; i < len(tag); i++ {
+		c := tag[i]
+		switch {
+		case escaped:
+			escaped = false
+		case inString && c == '\\':
+			escaped = true
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '[' || c == '{':
+			depth++
+		case (c == ']' || c == '}') && depth > 0:
+			depth--
+		case c == ',' && depth == 0:
+			parts = append(parts, tag[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, tag[start:])
+}
+
+// coerceTagValue converts a raw tag value to the JSON type of schema:
+// integers, numbers and booleans are parsed, arrays are written as [a,b]
+// (or JSON) with elements coerced to the items schema, and objects as JSON
+// literals. Strings are kept as written unless quoted as a JSON string.
+func coerceTagValue(schema *Schema, raw string) (any, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "null" && isNullable(schema) {
+		return nil, nil
+	}
+
+	switch primaryType(schema) {
+	case "integer":
+		if v, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			return v, nil
+		}
+		if v, err := strconv.ParseUint(raw, 10, 64); err == nil {
+			return v, nil
+		}
+		return nil, fmt.Errorf("%q is not an integer", raw)
+	case "number":
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", raw)
+		}
+		return v, nil
+	case "boolean":
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a boolean", raw)
+		}
+		return v, nil
+	case "array":
+		return coerceTagArray(schema, raw)
+	case "object":
+		var v map[string]any
+		if err := json.Unmarshal([]byte(raw), &v); err != nil {
+			return nil, fmt.Errorf("%q is not a JSON object", raw)
+		}
+		return v, nil
+	case "string":
+		var s string
+		if strings.HasPrefix(raw, `"`) && json.Unmarshal([]byte(raw), &s) == nil {
+			return s, nil
+		}
+		return raw, nil
+	}
+
+	// Untyped schemas (any JSON value) accept JSON literals, else strings.
+	var v any
+	if err := json.Unmarshal([]byte(raw), &v); err == nil {
+		return v, nil
+	}
+	return raw, nil
+}
+
+// coerceTagArray parses a JSON array or the bare [a,b] form, coercing each
+// element to the items schema.
+func coerceTagArray(schema *Schema, raw string) (any, error) {
+	if !strings.HasPrefix(raw, "[") || !strings.HasSuffix(raw, "]") {
+		return nil, fmt.Errorf("%q is not an array; write [a,b]", raw)
+	}
+
+	inner := strings.TrimSpace(raw[1 : len(raw)-1])
+	out := []any{}
+	if inner == "" {
+		return out, nil
+	}
+	items := schema.Items
+	if items == nil || items.Ref != "" {
+		items = &Schema{}
+	}
+	for _, elem := range splitTagOptions(inner) {
+		v, err := coerceTagValue(items, elem)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+// primaryType returns the first non-null type of schema, or "".
+func primaryType(schema *Schema) string {
+	switch t := schema.Type.(type) {
+	case string:
+		return t
+	case []string:
+		for _, v := range t {
+			if v != "null" {
+				return v
+			}
+		}
+	case []any:
+		for _, v := range t {
+			if s, ok := v.(string); ok && s != "null" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// setTagValue coerces raw and stores it through set, recording a diagnostic
+// instead when the value does not fit the schema.
+func (sg *SchemaGenerator) setTagValue(schema *Schema, key, raw string, set func(any)) {
+	v, err := coerceTagValue(schema, raw)
+	if err != nil {
+		sg.tagDiagnostic(key, err)
+		return
+	}
+	set(v)
+}
+
+// applySubschemaTag applies the openapi tag keywords whose values are
+// schemas. A schema is written as a JSON object or as a bare type name
+// (contains=integer); the keywords taking several schemas use [a,b] for
+// prefixItems and a JSON object keyed by pattern or property name otherwise.
+// $refs inside these values are emitted as written.
+func (sg *SchemaGenerator) applySubschemaTag(schema *Schema, key, value string) {
+	var err error
+	switch key {
+	case "contains":
+		schema.Contains, err = parseTagSchema(value)
+	case "propertyNames":
+		schema.PropertyNames, err = parseTagSchema(value)
+	case "if":
+		schema.If, err = parseTagSchema(value)
+	case "then":
+		schema.Then, err = parseTagSchema(value)
+	case "else":
+		schema.Else, err = parseTagSchema(value)
+	case "unevaluatedProperties":
+		if b, parseErr := strconv.ParseBool(value); parseErr == nil {
+			schema.UnevaluatedProperties = b
+			return
+		}
+		var s *Schema
+		if s, err = parseTagSchema(value); err == nil {
+			schema.UnevaluatedProperties = s
+		}
+	case "prefixItems":
+		schema.PrefixItems, err = parseTagSchemaList(value)
+	case "patternProperties":
+		schema.PatternProperties, err = parseTagSchemaMap(value)
+	case "dependentSchemas":
+		schema.DependentSchemas, err = parseTagSchemaMap(value)
+	case "$defs":
+		schema.Defs, err = parseTagSchemaMap(value)
+	case "dependentRequired":
+		var deps map[string][]string
+		if json.Unmarshal([]byte(value), &deps) != nil {
+			err = fmt.Errorf("%q is not a JSON object of property lists", value)
+		} else {
+			schema.DependentRequired = deps
+		}
+	default:
+		return
+	}
+	if err != nil {
+		sg.tagDiagnostic(key, err)
+	}
+}
+
+// jsonSchemaTypes are the type names accepted in place of a schema.
+var jsonSchemaTypes = map[string]bool{
+	"string": true, "integer": true, "number": true, "boolean": true,
+	"object": true, "array": true, "null": true,
+}
+
+// parseTagSchema reads a JSON schema object or a (possibly quoted) type name.
+func parseTagSchema(raw string) (*Schema, error) {
+	raw = strings.TrimSpace(raw)
+	var name string
+	if json.Unmarshal([]byte(raw), &name) == nil {
+		raw = name
+	}
+	if jsonSchemaTypes[raw] {
+		return &Schema{Type: raw}, nil
+	}
+	if !strings.HasPrefix(raw, "{") {
+		return nil, fmt.Errorf("%q is not a JSON schema or type name", raw)
+	}
+	var s Schema
+	if err := json.Unmarshal([]byte(raw), &s); err != nil {
+		return nil, fmt.Errorf("%q is not a JSON schema", raw)
+	}
+	return &s, nil
+}
+
+// parseTagSchemaList reads [a,b] where each element is a schema.
+func parseTagSchemaList(raw string) ([]*Schema, error) {
+	if !strings.HasPrefix(raw, "[") || !strings.HasSuffix(raw, "]") {
+		return nil, fmt.Errorf("%q is not a list of schemas; write [a,b]", raw)
+	}
+	var out []*Schema
+	for _, elem := range splitTagOptions(raw[1 : len(raw)-1]) {
+		s, err := parseTagSchema(elem)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// parseTagSchemaMap reads a JSON object whose values are schemas.
+func parseTagSchemaMap(raw string) (map[string]*Schema, error) {
+	var entries map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(raw), &entries); err != nil {
+		return nil, fmt.Errorf("%q is not a JSON object of schemas", raw)
+	}
+	out := make(map[string]*Schema, len(entries))
+	for name, entry := range entries {
+		s, err := parseTagSchema(string(entry))
+		if err != nil {
+			return nil, err
+		}
+		out[name] = s
+	}
+	return out, nil
+}
+
+func (sg *SchemaGenerator) tagDiagnostic(key string, err error) {
+	sg.addDiagnostic(fmt.Sprintf("%s: %s %v", sg.tagOwner(), key, err))
+}
+
+// tagOwner labels the struct field whose tags are being applied.
+func (sg *SchemaGenerator) tagOwner() string {
+	if sg.currentType == "" {
+		return sg.currentField
+	}
+	return sg.currentType + "." + sg.currentField
+}
+
+// addDiagnostic records a problem found while generating schemas.
+func (sg *SchemaGenerator) addDiagnostic(msg string) {
+	slog.Warn("[annot8] schema diagnostic: " + msg)
+	sg.mutex.Lock()
+	sg.diagnostics = append(sg.diagnostics, msg)
+	sg.mutex.Unlock()
+}
+
+// Diagnostics returns the problems found while generating schemas, such as
+// struct tag values that could not be coerced to the field's type.
+func (sg *SchemaGenerator) Diagnostics() []string {
+	sg.mutex.Lock()
+	defer sg.mutex.Unlock()
+	out := append([]string(nil), sg.diagnostics...)
+	sort.Strings(out)
+	return slices.Compact(out)
+}
@@ -37,7 +37,7 @@ func extractTag(tag, key string) string {
 func (sg *SchemaGenerator) applyEnhancedTags(schema *Schema, tag string) {
 	// Parse openapi tag for enhanced features
 	if openapiTag := extractTag(tag, "openapi"); openapiTag != "" {
-		parts := strings.Split(openapiTag, ",")
+		parts := splitTagOptions(openapiTag)
 		for _, part := range parts {
 			part = strings.TrimSpace(part)
 			if strings.Contains(part, "=") {
@@ -49,7 +49,7 @@ func (sg *SchemaGenerator) applyEnhancedTags(schema *Schema, tag string) {
 				case "pattern":
 					schema.Pattern = value
 				case "example":
-					schema.Example = value
+					sg.setTagValue(schema, key, value, func(v any) { schema.Example = v })
 				case "title":
 					schema.Title = value
 				case "deprecated":
@@ -83,6 +83,10 @@ func (sg *SchemaGenerator) applyEnhancedTags(schema *Schema, tag string) {
 					if max, err := strconv.ParseFloat(value, 64); err == nil {
 						schema.ExclusiveMaximum = &max
 					}
+				case "multipleOf":
+					if m, err := strconv.ParseFloat(value, 64); err == nil && m > 0 {
+						schema.MultipleOf = &m
+					}
 				case "minLength":
 					if m, err := strconv.Atoi(value); err == nil {
 						schema.MinLength = &m
@@ -104,14 +108,45 @@ func (sg *SchemaGenerator) applyEnhancedTags(schema *Schema, tag string) {
 						ui := true
 						schema.UniqueItems = &ui
 					}
+				case "minContains":
+					if m, err := strconv.Atoi(value); err == nil {
+						schema.MinContains = &m
+					}
+				case "maxContains":
+					if m, err := strconv.Atoi(value); err == nil {
+						schema.MaxContains = &m
+					}
+				case "minProperties":
+					if m, err := strconv.Atoi(value); err == nil {
+						schema.MinProperties = &m
+					}
+				case "maxProperties":
+					if m, err := strconv.Atoi(value); err == nil {
+						schema.MaxProperties = &m
+					}
+				case "contentMediaType":
+					schema.ContentMediaType = value
+				case "contentEncoding":
+					schema.ContentEncoding = value
+				case "$id":
+					schema.ID = value
+				case "$comment":
+					schema.Comment = value
+				case "const":
+					sg.setTagValue(schema, key, value, func(v any) { schema.Const = v })
 				case "enum":
 					vals := strings.Split(value, "|")
-					schema.Enum = make([]interface{}, len(vals))
-					for i, v := range vals {
-						schema.Enum[i] = strings.TrimSpace(v)
+					enum := make([]any, 0, len(vals))
+					for _, raw := range vals {
+						sg.setTagValue(schema, key, raw, func(v any) { enum = append(enum, v) })
+					}
+					if len(enum) == len(vals) {
+						schema.Enum = enum
 					}
 				case "default":
-					schema.Default = value
+					sg.setTagValue(schema, key, value, func(v any) { schema.Default = v })
+				default:
+					sg.applySubschemaTag(schema, key, value)
 				}
 			}
 		}
@@ -119,82 +154,7 @@ func (sg *SchemaGenerator) applyEnhancedTags(schema *Schema, tag string) {
 
 	// Parse validate tag for additional constraints
 	if validateTag := extractTag(tag, "validate"); validateTag != "" {
-		parts := strings.Split(validateTag, ",")
-		for _, part := range parts {
-			part = strings.TrimSpace(part)
-			switch {
-			case part == "email":
-				schema.Format = "email"
-			case part == "uuid":
-				schema.Format = "uuid"
-			case part == "uri", part == "url":
-				schema.Format = "uri"
-			case strings.HasPrefix(part, "min="):
-				val := strings.TrimPrefix(part, "min=")
-				if hasType(schema, "integer") || hasType(schema, "number") {
-					if min, err := strconv.ParseFloat(val, 64); err == nil {
-						schema.Minimum = &min
-					}
-				} else if hasType(schema, "string") {
-					if m, err := strconv.Atoi(val); err == nil {
-						schema.MinLength = &m
-					}
-				} else if hasType(schema, "array") {
-					if m, err := strconv.Atoi(val); err == nil {
-						schema.MinItems = &m
-					}
-				}
-			case strings.HasPrefix(part, "max="):
-				val := strings.TrimPrefix(part, "max=")
-				if hasType(schema, "integer") || hasType(schema, "number") {
-					if max, err := strconv.ParseFloat(val, 64); err == nil {
-						schema.Maximum = &max
-					}
-				} else if hasType(schema, "string") {
-					if m, err := strconv.Atoi(val); err == nil {
-						schema.MaxLength = &m
-					}
-				} else if hasType(schema, "array") {
-					if m, err := strconv.Atoi(val); err == nil {
-						schema.MaxItems = &m
-					}
-				}
-			case strings.HasPrefix(part, "exclusiveMin="):
-				val := strings.TrimPrefix(part, "exclusiveMin=")
-				if hasType(schema, "integer") || hasType(schema, "number") {
-					if min, err := strconv.ParseFloat(val, 64); err == nil {
-						schema.ExclusiveMinimum = &min
-					}
-				}
-			case strings.HasPrefix(part, "exclusiveMax="):
-				val := strings.TrimPrefix(part, "exclusiveMax=")
-				if hasType(schema, "integer") || hasType(schema, "number") {
-					if max, err := strconv.ParseFloat(val, 64); err == nil {
-						schema.ExclusiveMaximum = &max
-					}
-				}
-			case strings.HasPrefix(part, "len="):
-				val := strings.TrimPrefix(part, "len=")
-				if hasType(schema, "string") {
-					if m, err := strconv.Atoi(val); err == nil {
-						schema.MinLength = &m
-						schema.MaxLength = &m
-					}
-				} else if hasType(schema, "array") {
-					if m, err := strconv.Atoi(val); err == nil {
-						schema.MinItems = &m
-						schema.MaxItems = &m
-					}
-				}
-			case strings.HasPrefix(part, "oneof="):
-				val := strings.TrimPrefix(part, "oneof=")
-				vals := strings.Split(val, " ")
-				schema.Enum = make([]interface{}, len(vals))
-				for i, v := range vals {
-					schema.Enum[i] = v
-				}
-			}
-		}
+		sg.applyValidateRules(schema, parseValidateTag(validateTag))
 	}
 
 	// Parse binding tag for additional format hints
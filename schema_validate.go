@@ -0,0 +1,559 @@
+package annot8
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"log/slog"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RequiredPolicy selects what puts a struct field in an object's required list.
+type RequiredPolicy int
+
+const (
+	// RequiredFromJSON marks non-pointer fields without omitempty/omitzero as
+	// required: what is always present in the marshaled output. This is the default.
+	RequiredFromJSON RequiredPolicy = iota
+	// RequiredFromValidate marks exactly the fields carrying validate:"required",
+	// documenting what the request binder enforces.
+	RequiredFromValidate
+)
+
+// SetRequiredPolicy selects what drives the required list of object schemas.
+func (sg *SchemaGenerator) SetRequiredPolicy(policy RequiredPolicy) {
+	sg.requiredPolicy = policy
+}
+
+// Patterns mirror the regular expressions go-playground/validator uses.
+const (
+	alphaPattern    = `^[a-zA-Z]+$`
+	alphanumPattern = `^[a-zA-Z0-9]+$`
+	numericPattern  = `^[-+]?[0-9]+(?:\.[0-9]+)?$`
+	numberPattern   = `^[0-9]+$`
+	e164Pattern     = `^\+[1-9]?[0-9]{7,14}$`
+	cidrv4Body      = `(?:[0-9]{1,3}\.){3}[0-9]{1,3}/(?:[0-9]|[12][0-9]|3[0-2])`
+	cidrv6Body      = `[0-9a-fA-F:]*:[0-9a-fA-F:.]*/(?:[0-9]|[1-9][0-9]|1[01][0-9]|12[0-8])`
+)
+
+// oneofValueRegexp splits a oneof parameter as the validator does: values
+// are separated by spaces unless single-quoted.
+var oneofValueRegexp = regexp.MustCompile(`'[^']*'|\S+`)
+
+// validateRule is one go-playground/validator rule, such as "min=3".
+type validateRule struct {
+	name, param string
+}
+
+func (r validateRule) String() string {
+	if r.param == "" {
+		return r.name
+	}
+	return r.name + "=" + r.param
+}
+
+// constrains reports whether rules include one that changes a schema;
+// required and omitempty on elements document nothing.
+func constrains(rules []validateRule) bool {
+	for _, rule := range rules {
+		switch rule.name {
+		case "required", "omitempty", "omitnil":
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// parseValidateTag splits a validate tag into rules. Parameters may contain
+// the validator escapes 0x2C (comma) and 0x7C (pipe). OR groups ("a|b")
+// cannot be expressed per rule and are skipped.
+func parseValidateTag(tag string) []validateRule {
+	var rules []validateRule
+	for _, part := range strings.Split(tag, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if strings.Contains(part, "|") {
+			slog.Debug("[annot8] parseValidateTag: skipping OR rule", "rule", part)
+			continue
+		}
+		name, param, _ := strings.Cut(part, "=")
+		param = strings.NewReplacer("0x2C", ",", "0x7C", "|").Replace(param)
+		rules = append(rules, validateRule{name: name, param: param})
+	}
+	return rules
+}
+
+// fieldValidateRules returns the rules of a field's validate tag.
+func fieldValidateRules(tag *ast.BasicLit) []validateRule {
+	if tag == nil {
+		return nil
+	}
+	value, ok := reflect.StructTag(strings.Trim(tag.Value, "`")).Lookup("validate")
+	if !ok {
+		return nil
+	}
+	return parseValidateTag(value)
+}
+
+// hasValidateRequired reports whether the field itself (not its elements)
+// carries the required rule.
+func hasValidateRequired(tag *ast.BasicLit) bool {
+	for _, rule := range fieldValidateRules(tag) {
+		if rule.name == "dive" {
+			return false
+		}
+		if rule.name == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+// applyValidateRules translates validator rules into keywords on schema.
+// Rules after "dive" constrain slice elements or map values; map keys are
+// constrained by a "keys ... endkeys" group right after it. Rules that
+// cannot be applied are reported as diagnostics.
+func (sg *SchemaGenerator) applyValidateRules(schema *Schema, rules []validateRule) {
+	for i, rule := range rules {
+		if rule.name == "dive" {
+			sg.applyDiveRules(schema, rules[i+1:])
+			return
+		}
+		if err := applyValidateRule(schema, rule); err != nil {
+			sg.validateDiagnostic(rule, err)
+		}
+	}
+}
+
+func (sg *SchemaGenerator) validateDiagnostic(rule validateRule, err error) {
+	sg.addDiagnostic(fmt.Sprintf("%s: validate %s %v", sg.tagOwner(), rule, err))
+}
+
+// applyDiveRules applies element rules to a slice's items or a map's values
+// and keys. Referenced element schemas cannot carry sibling keywords, so
+// their rules are reported instead of applied.
+func (sg *SchemaGenerator) applyDiveRules(schema *Schema, rules []validateRule) {
+	dive := validateRule{name: "dive"}
+	if hasType(schema, "array") {
+		switch {
+		case schema.Items == nil:
+		case schema.Items.Ref != "":
+			if constrains(rules) {
+				sg.validateDiagnostic(dive, errors.New("cannot constrain referenced elements "+schema.Items.Ref))
+			}
+		default:
+			sg.applyValidateRules(schema.Items, rules)
+		}
+		return
+	}
+
+	values, ok := schema.AdditionalProperties.(*Schema)
+	if !ok || !hasType(schema, "object") {
+		if constrains(rules) && schema.Ref == "" {
+			sg.validateDiagnostic(dive, errors.New("applies to slices and maps only"))
+		}
+		return
+	}
+	if len(rules) > 0 && rules[0].name == "keys" {
+		end := len(rules)
+		for i, rule := range rules {
+			if rule.name == "endkeys" {
+				end = i
+				break
+			}
+		}
+		if schema.PropertyNames == nil {
+			schema.PropertyNames = &Schema{Type: "string"}
+		}
+		if schema.PropertyNames.Ref == "" {
+			sg.applyValidateRules(schema.PropertyNames, rules[1:end])
+		} else if constrains(rules[1:end]) {
+			sg.validateDiagnostic(dive, errors.New("cannot constrain referenced keys "+schema.PropertyNames.Ref))
+		}
+		rules = rules[min(end+1, len(rules)):]
+	}
+	switch {
+	case values == nil:
+	case values.Ref != "":
+		if constrains(rules) {
+			sg.validateDiagnostic(dive, errors.New("cannot constrain referenced values "+values.Ref))
+		}
+	default:
+		sg.applyValidateRules(values, rules)
+	}
+}
+
+// applyValidateRule applies a single rule that constrains the value itself.
+// It returns an error for a rule whose parameter does not fit the schema.
+func applyValidateRule(schema *Schema, rule validateRule) error {
+	switch rule.name {
+	case "email":
+		schema.Format = "email"
+	case "uuid", "uuid3", "uuid4", "uuid5":
+		schema.Format = "uuid"
+	case "uri", "url":
+		schema.Format = "uri"
+	case "hostname", "hostname_rfc1123", "fqdn":
+		schema.Format = "hostname"
+	case "ipv4", "ip4_addr":
+		schema.Format = "ipv4"
+	case "ipv6", "ip6_addr":
+		schema.Format = "ipv6"
+	case "ip", "ip_addr":
+		// Kept apart from anyOf, which nullability may use for the field.
+		schema.AllOf = append(schema.AllOf, &Schema{AnyOf: []*Schema{{Format: "ipv4"}, {Format: "ipv6"}}})
+	case "cidrv4":
+		addPattern(schema, "^"+cidrv4Body+"$")
+	case "cidrv6":
+		addPattern(schema, "^"+cidrv6Body+"$")
+	case "cidr":
+		addPattern(schema, "^(?:"+cidrv4Body+"|"+cidrv6Body+")$")
+	case "e164":
+		addPattern(schema, e164Pattern)
+	case "alpha":
+		addPattern(schema, alphaPattern)
+	case "alphanum":
+		addPattern(schema, alphanumPattern)
+	case "numeric":
+		addPattern(schema, numericPattern)
+	case "number":
+		addPattern(schema, numberPattern)
+	case "datetime":
+		applyDatetimeLayout(schema, rule.param)
+	case "startswith":
+		addPattern(schema, "^"+regexp.QuoteMeta(rule.param))
+	case "endswith":
+		addPattern(schema, regexp.QuoteMeta(rule.param)+"$")
+	case "contains":
+		addPattern(schema, regexp.QuoteMeta(rule.param))
+	case "min", "gte":
+		return applyBound(schema, "min", rule.param)
+	case "max", "lte":
+		return applyBound(schema, "max", rule.param)
+	case "gt":
+		return applyBound(schema, "gt", rule.param)
+	case "lt":
+		return applyBound(schema, "lt", rule.param)
+	case "exclusiveMin":
+		if isNumericSchema(schema) {
+			return applyBound(schema, "gt", rule.param)
+		}
+	case "exclusiveMax":
+		if isNumericSchema(schema) {
+			return applyBound(schema, "lt", rule.param)
+		}
+	case "len":
+		if err := applyBound(schema, "min", rule.param); err != nil {
+			return err
+		}
+		return applyBound(schema, "max", rule.param)
+	case "ne":
+		schema.Not = &Schema{Const: typedValidateValue(schema, rule.param)}
+	case "oneof":
+		vals := oneofValueRegexp.FindAllString(rule.param, -1)
+		enum := make([]any, len(vals))
+		for i, v := range vals {
+			v = strings.ReplaceAll(v, "'", "")
+			enum[i] = typedValidateValue(schema, v)
+			if _, isString := enum[i].(string); isString && isNumericSchema(schema) {
+				return fmt.Errorf("value %q is not a number", v)
+			}
+		}
+		schema.Enum = enum
+	}
+	return nil
+}
+
+// applyBound sets op ("min", "max", "gt", "lt") as a range on numbers or as
+// the equivalent length on strings, item count on arrays and entry count on
+// maps.
+func applyBound(schema *Schema, op, param string) error {
+	if isNumericSchema(schema) {
+		v, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return fmt.Errorf("bound %q is not a number", param)
+		}
+		switch op {
+		case "min":
+			schema.Minimum = &v
+		case "max":
+			schema.Maximum = &v
+		case "gt":
+			schema.ExclusiveMinimum = &v
+		case "lt":
+			schema.ExclusiveMaximum = &v
+		}
+		return nil
+	}
+
+	n, err := strconv.Atoi(param)
+	if err != nil {
+		return fmt.Errorf("length %q is not an integer", param)
+	}
+	switch op {
+	case "gt":
+		op, n = "min", n+1
+	case "lt":
+		op, n = "max", n-1
+	}
+	if n < 0 {
+		return nil
+	}
+	switch {
+	case hasType(schema, "string") && op == "min":
+		schema.MinLength = &n
+	case hasType(schema, "string") && op == "max":
+		schema.MaxLength = &n
+	case hasType(schema, "array") && op == "min":
+		schema.MinItems = &n
+	case hasType(schema, "array") && op == "max":
+		schema.MaxItems = &n
+	case hasType(schema, "object") && op == "min":
+		schema.MinProperties = &n
+	case hasType(schema, "object") && op == "max":
+		schema.MaxProperties = &n
+	}
+	return nil
+}
+
+func isNumericSchema(schema *Schema) bool {
+	return hasType(schema, "integer") || hasType(schema, "number")
+}
+
+// typedValidateValue converts a rule parameter to the JSON type of schema.
+func typedValidateValue(schema *Schema, param string) any {
+	switch {
+	case isNumericSchema(schema):
+		if v, err := strconv.ParseFloat(param, 64); err == nil {
+			return v
+		}
+	case hasType(schema, "boolean"):
+		if v, err := strconv.ParseBool(param); err == nil {
+			return v
+		}
+	}
+	return param
+}
+
+// addPattern sets schema's pattern, or adds an allOf member when a pattern
+// is already present so that both apply.
+func addPattern(schema *Schema, pattern string) {
+	if schema.Pattern == "" {
+		schema.Pattern = pattern
+		return
+	}
+	schema.AllOf = append(schema.AllOf, &Schema{Pattern: pattern})
+}
+
+// applyDatetimeLayout documents a datetime=<layout> rule: RFC 3339 layouts
+// map to formats, anything else to a pattern derived from the layout.
+func applyDatetimeLayout(schema *Schema, layout string) {
+	switch layout {
+	case time.RFC3339, time.RFC3339Nano:
+		schema.Format = "date-time"
+	case time.DateOnly:
+		schema.Format = "date"
+	default:
+		addPattern(schema, layoutPattern(layout))
+	}
+}
+
+// layoutTokens are the Go reference-time elements, longest first so that
+// "2006" wins over "2" and "15" over "1".
+var layoutTokens = []struct{ token, pattern string }{
+	{"January", `[A-Z][a-z]+`},
+	{"Monday", `[A-Z][a-z]+`},
+	{"Z07:00", `(?:Z|[+-][0-9]{2}:[0-9]{2})`},
+	{"-07:00", `[+-][0-9]{2}:[0-9]{2}`},
+	{"Z0700", `(?:Z|[+-][0-9]{4})`},
+	{"-0700", `[+-][0-9]{4}`},
+	{"2006", `[0-9]{4}`},
+	{"Jan", `[A-Z][a-z]{2}`},
+	{"Mon", `[A-Z][a-z]{2}`},
+	{"MST", `[A-Z]{3,5}`},
+	{"PM", `(?:AM|PM)`},
+	{"pm", `(?:am|pm)`},
+	{"_2", `[ 0-9][0-9]`},
+	{"01", `[0-9]{2}`},
+	{"02", `[0-9]{2}`},
+	{"03", `[0-9]{2}`},
+	{"04", `[0-9]{2}`},
+	{"05", `[0-9]{2}`},
+	{"06", `[0-9]{2}`},
+	{"15", `[0-9]{2}`},
+	{"1", `[0-9]{1,2}`},
+	{"2", `[0-9]{1,2}`},
+	{"3", `[0-9]{1,2}`},
+	{"4", `[0-9]{1,2}`},
+	{"5", `[0-9]{1,2}`},
+}
+
+// layoutPattern converts a time.Parse layout into an anchored pattern.
+// Fractional seconds written as .000 are fixed width; .999 are optional.
+func layoutPattern(layout string) string {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(layout); {
+		if (layout[i] == '.' || layout[i] == ',') && i+1 < len(layout) &&
+			(layout[i+1] == '0' || layout[i+1] == '9') {
+			digit, j := layout[i+1], i+1
+			for j < len(layout) && layout[j] == digit {
+				j++
+			}
+			if digit == '0' {
+				b.WriteString(`[.,][0-9]{` + strconv.Itoa(j-i-1) + `}`)
+			} else {
+				b.WriteString(`(?:[.,][0-9]+)?`)
+			}
+			i = j
+			continue
+		}
+
+		matched := false
+		for _, t := range layoutTokens {
+			if strings.HasPrefix(layout[i:], t.token) {
+				b.WriteString(t.pattern)
+				i += len(t.token)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			b.WriteString(regexp.QuoteMeta(layout[i : i+1]))
+			i++
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
+
+// validateConditionals translates the cross-field rules of an object's
+// members (required_if, required_unless, required_with, required_with_all,
+// required_without and excluded_with) into dependentRequired entries and
+// if/then clauses. Rules naming fields absent from the object are skipped.
+func validateConditionals(fields []jsonField, properties map[string]*Schema) (map[string][]string, []*Schema) {
+	jsonNames := make(map[string]string, len(fields))
+	for _, member := range fields {
+		jsonNames[member.goName] = member.name
+	}
+	lookup := func(goNames []string) ([]string, bool) {
+		out := make([]string, 0, len(goNames))
+		for _, goName := range goNames {
+			name, ok := jsonNames[goName]
+			if !ok {
+				slog.Debug("[annot8] validateConditionals: unknown field in rule", "field", goName)
+				return nil, false
+			}
+			out = append(out, name)
+		}
+		return out, true
+	}
+
+	dependent := make(map[string][]string)
+	var clauses []*Schema
+	for _, member := range fields {
+		this := member.name
+		for _, rule := range fieldValidateRules(member.field.Tag) {
+			if rule.name == "dive" {
+				break
+			}
+			params := strings.Fields(rule.param)
+			switch rule.name {
+			case "required_with", "required_without", "excluded_with":
+				others, ok := lookup(params)
+				if !ok {
+					continue
+				}
+				for _, other := range others {
+					switch rule.name {
+					case "required_with":
+						dependent[other] = append(dependent[other], this)
+					case "required_without":
+						clauses = append(clauses, &Schema{
+							If:   &Schema{Not: &Schema{Required: []string{other}}},
+							Then: &Schema{Required: []string{this}},
+						})
+					case "excluded_with":
+						clauses = append(clauses, &Schema{
+							If:   &Schema{Required: []string{other}},
+							Then: &Schema{Not: &Schema{Required: []string{this}}},
+						})
+					}
+				}
+			case "required_with_all":
+				others, ok := lookup(params)
+				if !ok || len(others) == 0 {
+					continue
+				}
+				clauses = append(clauses, &Schema{
+					If:   &Schema{Required: others},
+					Then: &Schema{Required: []string{this}},
+				})
+			case "required_if", "required_unless":
+				condition := fieldValuesCondition(params, lookup, properties)
+				if condition == nil {
+					continue
+				}
+				clause := &Schema{If: condition}
+				if rule.name == "required_if" {
+					clause.Then = &Schema{Required: []string{this}}
+				} else {
+					clause.Else = &Schema{Required: []string{this}}
+				}
+				clauses = append(clauses, clause)
+			}
+		}
+	}
+
+	if len(dependent) == 0 {
+		dependent = nil
+	}
+	return dependent, clauses
+}
+
+// fieldValuesCondition builds the "if" schema for "Field value [Field value...]"
+// rule parameters: every named property is present with the given value.
+func fieldValuesCondition(
+	params []string,
+	lookup func([]string) ([]string, bool),
+	properties map[string]*Schema,
+) *Schema {
+	if len(params) == 0 || len(params)%2 != 0 {
+		return nil
+	}
+	condition := &Schema{Properties: make(map[string]*Schema, len(params)/2)}
+	for i := 0; i < len(params); i += 2 {
+		names, ok := lookup(params[i : i+1])
+		if !ok {
+			return nil
+		}
+		value := params[i+1]
+		if prop := properties[names[0]]; prop != nil {
+			condition.Properties[names[0]] = &Schema{Const: typedValidateValue(prop, value)}
+		} else {
+			condition.Properties[names[0]] = &Schema{Const: value}
+		}
+		condition.Required = append(condition.Required, names[0])
+	}
+	return condition
+}
+
+// applyConditionals attaches dependentRequired entries and conditional
+// clauses to an object schema; several clauses are combined with allOf.
+func applyConditionals(object *Schema, dependent map[string][]string, clauses []*Schema) {
+	object.DependentRequired = dependent
+	switch len(clauses) {
+	case 0:
+	case 1:
+		object.If, object.Then, object.Else = clauses[0].If, clauses[0].Then, clauses[0].Else
+	default:
+		object.AllOf = append(object.AllOf, clauses...)
+	}
+}
@@ -0,0 +1,265 @@
+package annot8
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// SchemaVariants selects whether request and response bodies reference
+// derived components that leave out readOnly and writeOnly properties.
+type SchemaVariants int
+
+const (
+	// VariantsShared references the same component from requests and
+	// responses. This is the default.
+	VariantsShared SchemaVariants = iota
+	// VariantsInputOutput derives <Type>Input, without readOnly properties,
+	// for request bodies and <Type>Output, without writeOnly properties, for
+	// responses.
+	VariantsInputOutput
+	// VariantsCreateUpdate derives <Type>Create for POST bodies and
+	// <Type>Update, which also requires nothing, for PUT and PATCH bodies.
+	// Responses use <Type>Output as in VariantsInputOutput.
+	VariantsCreateUpdate
+)
+
+// schemaVariant describes one derived component kind.
+type schemaVariant struct {
+	suffix        string
+	dropReadOnly  bool
+	dropWriteOnly bool
+	optional      bool // clear required lists
+}
+
+var (
+	inputVariant  = schemaVariant{suffix: "Input", dropReadOnly: true}
+	outputVariant = schemaVariant{suffix: "Output", dropWriteOnly: true}
+	createVariant = schemaVariant{suffix: "Create", dropReadOnly: true}
+	updateVariant = schemaVariant{suffix: "Update", dropReadOnly: true, optional: true}
+)
+
+const schemaRefPrefix = "#/components/schemas/"
+
+// variantIDSeparator joins a component ID and a variant suffix. It cannot
+// appear in Go identifiers, so variant IDs never clash with declared types.
+const variantIDSeparator = "@"
+
+// SetSchemaVariants selects whether request and response bodies reference
+// derived components that omit readOnly and writeOnly properties.
+func (g *Generator) SetSchemaVariants(mode SchemaVariants) {
+	g.schemaVariants = mode
+}
+
+// requestVariant returns the variant request bodies of method use, if any.
+func (g *Generator) requestVariant(method string) (schemaVariant, bool) {
+	switch g.schemaVariants {
+	case VariantsInputOutput:
+		return inputVariant, true
+	case VariantsCreateUpdate:
+		switch strings.ToUpper(method) {
+		case "PUT", "PATCH":
+			return updateVariant, true
+		}
+		return createVariant, true
+	}
+	return schemaVariant{}, false
+}
+
+// responseVariant returns the variant response bodies use, if any.
+func (g *Generator) responseVariant() (schemaVariant, bool) {
+	if g.schemaVariants == VariantsShared {
+		return schemaVariant{}, false
+	}
+	return outputVariant, true
+}
+
+// drops reports whether v leaves property p out.
+func (v schemaVariant) drops(p *Schema) bool {
+	return (v.dropReadOnly && p.ReadOnly != nil && *p.ReadOnly) ||
+		(v.dropWriteOnly && p.WriteOnly != nil && *p.WriteOnly)
+}
+
+// schemaVariantOf rewrites schema for v: dropped properties are removed
+// from inline objects and references point at derived components wherever
+// the referenced schema (or anything it references) is affected. Components
+// that v does not change keep their original reference.
+func (sg *SchemaGenerator) schemaVariantOf(schema *Schema, v schemaVariant) *Schema {
+	if schema == nil {
+		return nil
+	}
+	if schema.Ref != "" {
+		if id := sg.variantID(strings.TrimPrefix(schema.Ref, schemaRefPrefix), v); id != "" {
+			out := *schema
+			out.Ref = schemaRefPrefix + id
+			return &out
+		}
+		return schema
+	}
+
+	out := *schema
+	if schema.Properties != nil {
+		out.Properties = make(map[string]*Schema, len(schema.Properties))
+		for name, prop := range schema.Properties {
+			if v.drops(prop) {
+				continue
+			}
+			out.Properties[name] = sg.schemaVariantOf(prop, v)
+		}
+		out.Required = nil
+		for _, name := range schema.Required {
+			if _, kept := out.Properties[name]; kept {
+				out.Required = append(out.Required, name)
+			}
+		}
+	}
+	if v.optional {
+		out.Required = nil
+		out.DependentRequired = nil
+	}
+	out.Items = sg.schemaVariantOf(schema.Items, v)
+	out.Not = sg.schemaVariantOf(schema.Not, v)
+	out.PropertyNames = sg.schemaVariantOf(schema.PropertyNames, v)
+	out.Contains = sg.schemaVariantOf(schema.Contains, v)
+	out.PrefixItems = sg.schemaVariantsOf(schema.PrefixItems, v)
+	out.Defs = sg.schemaVariantMap(schema.Defs, v)
+	out.PatternProperties = sg.schemaVariantMap(schema.PatternProperties, v)
+	out.DependentSchemas = sg.schemaVariantMap(schema.DependentSchemas, v)
+	out.If = sg.schemaVariantOf(schema.If, v)
+	out.Then = sg.schemaVariantOf(schema.Then, v)
+	out.Else = sg.schemaVariantOf(schema.Else, v)
+	out.AllOf = sg.schemaVariantsOf(schema.AllOf, v)
+	out.AnyOf = sg.schemaVariantsOf(schema.AnyOf, v)
+	out.OneOf = sg.schemaVariantsOf(schema.OneOf, v)
+	if ap, ok := schema.AdditionalProperties.(*Schema); ok {
+		out.AdditionalProperties = sg.schemaVariantOf(ap, v)
+	}
+	if up, ok := schema.UnevaluatedProperties.(*Schema); ok {
+		out.UnevaluatedProperties = sg.schemaVariantOf(up, v)
+	}
+	if schema.Discriminator != nil {
+		d := *schema.Discriminator
+		d.Mapping = make(map[string]string, len(schema.Discriminator.Mapping))
+		for value, ref := range schema.Discriminator.Mapping {
+			d.Mapping[value] = sg.schemaVariantOf(&Schema{Ref: ref}, v).Ref
+		}
+		out.Discriminator = &d
+	}
+	return &out
+}
+
+func (sg *SchemaGenerator) schemaVariantsOf(schemas []*Schema, v schemaVariant) []*Schema {
+	if schemas == nil {
+		return nil
+	}
+	out := make([]*Schema, len(schemas))
+	for i, s := range schemas {
+		out[i] = sg.schemaVariantOf(s, v)
+	}
+	return out
+}
+
+func (sg *SchemaGenerator) schemaVariantMap(schemas map[string]*Schema, v schemaVariant) map[string]*Schema {
+	if schemas == nil {
+		return nil
+	}
+	out := make(map[string]*Schema, len(schemas))
+	for name, s := range schemas {
+		out[name] = sg.schemaVariantOf(s, v)
+	}
+	return out
+}
+
+// variantID returns the ID of component id's v variant, deriving it on
+// first use, or "" when v would leave the component unchanged.
+func (sg *SchemaGenerator) variantID(id string, v schemaVariant) string {
+	variant := id + variantIDSeparator + v.suffix
+
+	sg.mutex.Lock()
+	_, exists := sg.schemas[variant]
+	base := sg.schemas[id]
+	sg.mutex.Unlock()
+	if exists {
+		return variant
+	}
+	if base == nil || !sg.variantAffects(base, v, map[string]bool{id: true}) {
+		return ""
+	}
+
+	// Reserve the ID first so that recursive references resolve to it.
+	sg.mutex.Lock()
+	sg.schemas[variant] = &Schema{}
+	if sg.variants == nil {
+		sg.variants = make(map[string]string)
+	}
+	sg.variants[variant] = id
+	sg.mutex.Unlock()
+
+	derived := sg.schemaVariantOf(base, v)
+
+	sg.mutex.Lock()
+	sg.schemas[variant] = derived
+	sg.mutex.Unlock()
+
+	slog.Debug("[annot8] variantID: derived schema variant", "base", id, "variant", variant)
+	return variant
+}
+
+// variantAffects reports whether v changes schema or any component it
+// references. visiting guards against reference cycles.
+func (sg *SchemaGenerator) variantAffects(schema *Schema, v schemaVariant, visiting map[string]bool) bool {
+	if schema == nil {
+		return false
+	}
+	if schema.Ref != "" {
+		id := strings.TrimPrefix(schema.Ref, schemaRefPrefix)
+		if visiting[id] {
+			return false
+		}
+		visiting[id] = true
+		sg.mutex.Lock()
+		target := sg.schemas[id]
+		sg.mutex.Unlock()
+		return sg.variantAffects(target, v, visiting)
+	}
+
+	if v.optional && (len(schema.Required) > 0 || len(schema.DependentRequired) > 0) {
+		return true
+	}
+	for _, prop := range schema.Properties {
+		if v.drops(prop) || sg.variantAffects(prop, v, visiting) {
+			return true
+		}
+	}
+	children := []*Schema{schema.Items, schema.Not, schema.PropertyNames, schema.Contains, schema.If, schema.Then, schema.Else}
+	if ap, ok := schema.AdditionalProperties.(*Schema); ok {
+		children = append(children, ap)
+	}
+	if up, ok := schema.UnevaluatedProperties.(*Schema); ok {
+		children = append(children, up)
+	}
+	children = append(children, schema.PrefixItems...)
+	for _, subs := range []map[string]*Schema{schema.Defs, schema.PatternProperties, schema.DependentSchemas} {
+		for _, sub := range subs {
+			children = append(children, sub)
+		}
+	}
+	children = append(children, schema.AllOf...)
+	children = append(children, schema.AnyOf...)
+	children = append(children, schema.OneOf...)
+	for _, child := range children {
+		if sg.variantAffects(child, v, visiting) {
+			return true
+		}
+	}
+	return false
+}
+
+// variantBase returns the component a variant was derived from and the
+// variant's suffix, or ok=false for ordinary components.
+func (sg *SchemaGenerator) variantBase(id string) (base, suffix string, ok bool) {
+	base, ok = sg.variants[id]
+	if !ok {
+		return "", "", false
+	}
+	return base, strings.TrimPrefix(id, base+variantIDSeparator), true
+}
@@ -0,0 +1,204 @@
+package annot8
+
+import (
+	"go/ast"
+	"reflect"
+	"strings"
+)
+
+// xmlComponentSuffix marks components generated with encoding/xml naming.
+// They are named after their JSON counterpart: OrderXML.
+const xmlComponentSuffix = "XML"
+
+// isXMLMediaType reports whether mediaType carries XML: application/xml,
+// text/xml or any +xml structured syntax suffix.
+func isXMLMediaType(mediaType string) bool {
+	base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(mediaType)), ";")
+	return base == "application/xml" || base == "text/xml" || strings.HasSuffix(base, "+xml")
+}
+
+// enterXMLMode makes schema generation follow encoding/xml when on, and
+// returns a func restoring the previous mode.
+func (sg *SchemaGenerator) enterXMLMode(on bool) func() {
+	old := sg.xmlMode
+	sg.xmlMode = on
+	return func() { sg.xmlMode = old }
+}
+
+// componentKey returns the schemas key for a type in the current mode. In
+// XML mode, types that marshal as elements (structs and anything built from
+// them) get their own component; predeclared-based types such as enums are
+// shared with JSON.
+func (sg *SchemaGenerator) componentKey(qualifiedName string) string {
+	if !sg.xmlMode || sg.typeIndex == nil {
+		return qualifiedName
+	}
+	if sg.typeIndex.LookupQualifiedType(qualifiedName) == nil || sg.typeIndex.underlyingBasicType(qualifiedName) != "" {
+		return qualifiedName
+	}
+
+	key := qualifiedName + variantIDSeparator + xmlComponentSuffix
+	sg.mutex.Lock()
+	if sg.variants == nil {
+		sg.variants = make(map[string]string)
+	}
+	sg.variants[key] = qualifiedName
+	sg.mutex.Unlock()
+	return key
+}
+
+// xmlTag is a parsed encoding/xml struct tag.
+type xmlTag struct {
+	namespace string
+	path      []string // element names; more than one for a>b
+	attr      bool
+	chardata  bool // ,chardata, ,cdata and ,innerxml
+	omitempty bool
+	skip      bool // "-", ,comment and ,any have no schema
+}
+
+// parseXMLTag reads the xml tag of field.
+func parseXMLTag(field *ast.Field) xmlTag {
+	if field.Tag == nil {
+		return xmlTag{}
+	}
+	value, ok := reflect.StructTag(strings.Trim(field.Tag.Value, "`")).Lookup("xml")
+	if !ok {
+		return xmlTag{}
+	}
+	if value == "-" {
+		return xmlTag{skip: true}
+	}
+
+	options := strings.Split(value, ",")
+	var tag xmlTag
+	name := options[0]
+	if ns, local, found := strings.Cut(name, " "); found {
+		tag.namespace, name = ns, local
+	}
+	if name != "" {
+		tag.path = strings.Split(name, ">")
+	}
+	for _, opt := range options[1:] {
+		switch opt {
+		case "attr":
+			tag.attr = true
+		case "chardata", "cdata", "innerxml":
+			tag.chardata = true
+		case "omitempty":
+			tag.omitempty = true
+		case "comment", "any":
+			tag.skip = true
+		}
+	}
+	return tag
+}
+
+// fieldName reads the member name of field for the current mode: the json
+// tag, or the xml tag in XML mode (a>b paths keep their full form).
+func (sg *SchemaGenerator) fieldName(field *ast.Field) (name string, tagged, skip bool) {
+	if !sg.xmlMode {
+		return jsonFieldName(field)
+	}
+	for _, ident := range field.Names {
+		if ident.Name == "XMLName" {
+			// The root element name, not a member.
+			return "", false, true
+		}
+	}
+	tag := parseXMLTag(field)
+	name = strings.Join(tag.path, ">")
+	return name, name != "", tag.skip
+}
+
+// omitsEmpty reports whether the field may be absent in the current mode.
+func (sg *SchemaGenerator) omitsEmpty(field *ast.Field) bool {
+	if sg.xmlMode {
+		return parseXMLTag(field).omitempty
+	}
+	return hasOmitEmpty(field.Tag)
+}
+
+// xmlRootSchema returns the XML object naming a struct's element: its
+// XMLName field tag, or the Go type name.
+func (sg *SchemaGenerator) xmlRootSchema(structType *ast.StructType) *XML {
+	root := &XML{Name: unqualifiedName(sg.currentType)}
+	for _, field := range structType.Fields.List {
+		if len(field.Names) != 1 || field.Names[0].Name != "XMLName" {
+			continue
+		}
+		tag := parseXMLTag(field)
+		if len(tag.path) > 0 {
+			root.Name = tag.path[len(tag.path)-1]
+		}
+		root.Namespace = tag.namespace
+	}
+	return root
+}
+
+// placeXMLProperty stores a member's schema under its XML name. Attributes
+// and namespaced elements carry an XML object; a>b paths become nested
+// objects, with a slice leaf documented as a wrapped array. It returns the
+// property name used at the top level.
+func placeXMLProperty(properties map[string]*Schema, member jsonField, schema *Schema) string {
+	tag := parseXMLTag(member.field)
+
+	switch {
+	case tag.chardata:
+		schema.Description = strings.TrimSpace(schema.Description + " Character data of the element")
+		properties[member.name] = schema
+		return member.name
+	case tag.attr:
+		properties[member.name] = withXML(schema, &XML{Name: member.name, Namespace: tag.namespace, Attribute: true})
+		return member.name
+	case len(tag.path) < 2:
+		if tag.namespace != "" {
+			schema = withXML(schema, &XML{Name: member.name, Namespace: tag.namespace})
+		}
+		properties[member.name] = schema
+		return member.name
+	}
+
+	// a>b>c: objects for the outer elements, unless the leaf is a slice, in
+	// which case its parent element wraps the repeated leaf elements.
+	path := tag.path
+	leaf := path[len(path)-1]
+	if hasType(schema, "array") && schema.Items != nil {
+		schema.Items = withXML(schema.Items, &XML{Name: leaf})
+		schema.XML = &XML{Name: path[len(path)-2], Namespace: tag.namespace, Wrapped: true}
+		path = path[:len(path)-1]
+	} else if tag.namespace != "" {
+		schema = withXML(schema, &XML{Name: leaf, Namespace: tag.namespace})
+	}
+
+	container := properties
+	for _, element := range path[:len(path)-1] {
+		parent, ok := container[element]
+		if !ok || parent.Ref != "" || !hasType(parent, "object") {
+			parent = &Schema{Type: "object", Properties: make(map[string]*Schema)}
+			container[element] = parent
+		}
+		container = parent.Properties
+	}
+	container[path[len(path)-1]] = schema
+	return path[0]
+}
+
+// withXML attaches xml to schema. References cannot carry sibling keywords,
+// so they are wrapped in a single-member allOf.
+func withXML(schema *Schema, xml *XML) *Schema {
+	if schema.Ref != "" {
+		return &Schema{AllOf: []*Schema{schema}, XML: xml}
+	}
+	schema.XML = xml
+	return schema
+}
+
+// byteSliceSchema describes []byte: a base64 string in JSON and plain
+// character data in XML.
+func (sg *SchemaGenerator) byteSliceSchema() *Schema {
+	if sg.xmlMode {
+		return &Schema{Type: "string"}
+	}
+	return byteSliceSchema()
+}
@@ -3,45 +3,64 @@ package annot8
 import (
 	"net/http"
 	"reflect"
+	"regexp"
 	"runtime"
+	"slices"
 	"strings"
 )
 
-// MiddlewareSecurityRule maps a middleware name pattern to one or more
-// OpenAPI security scheme keys. A rule matches when the middleware runtime
-// function name contains RuleContains.
+// MiddlewareSecurityRule maps a middleware to one or more OpenAPI security
+// scheme keys. Middleware identifies it: the middleware function itself
+// (IsTenant, auth.Handler), or the constructor returning it
+// (RequireRole for the closure RequireRole("admin") returns). Without
+// Middleware, a rule matches when the middleware runtime function name
+// contains RuleContains.
+//
+// Schemes are required together. Alternatives lists other scheme sets a
+// caller may satisfy instead (a JWT or an API key), and Optional also admits
+// anonymous callers, documented as an empty {} requirement.
 type MiddlewareSecurityRule struct {
+	Middleware   any
 	RuleContains string
 	Schemes      []string
+	Alternatives [][]string
+	Optional     bool
 }
 
 // RouteSecurityRule maps a method+route suffix pattern to one or more
-// OpenAPI security scheme keys.
+// OpenAPI security scheme keys. Schemes, Alternatives and Optional are as
+// for MiddlewareSecurityRule.
 type RouteSecurityRule struct {
-	Method     string
-	PathSuffix string
-	Schemes    []string
+	Method       string
+	PathSuffix   string
+	Schemes      []string
+	Alternatives [][]string
+	Optional     bool
 }
 
 // SecurityInferenceConfig controls how annot8 infers operation security.
 //
 // 1) MiddlewareRules are the primary source (1:1 explicit mapping).
 // 2) RouteRules are documented exceptions where auth is handler-driven.
-// 3) ACL fallback is optional and can be disabled.
+// 3) ACL fallback is optional and can be disabled. It requires ACLScheme,
+// which must be declared in Config.SecuritySchemes; ValidateSecuritySchemes
+// reports it otherwise.
 type SecurityInferenceConfig struct {
 	MiddlewareRules                []MiddlewareSecurityRule
 	RouteRules                     []RouteSecurityRule
 	EnableACLBearerFromPermissions bool
+	ACLScheme                      string
 }
 
 // DefaultSecurityInferenceConfig returns annot8's default security mapping.
 //
-// It includes the legacy JWT/auth name-contains behavior as explicit rules,
-// so behavior is auditable and overrideable.
+// It has no middleware rules: middleware is identified by function value, so
+// authentication middleware must be registered in a rule or wrapped with
+// SecurityMiddleware. Only the ACL fallback is enabled.
 func DefaultSecurityInferenceConfig() SecurityInferenceConfig {
 	return SecurityInferenceConfig{
 		MiddlewareRules: []MiddlewareSecurityRule{
-			// {RuleContains: "Authenticated", Schemes: []string{"BearerAuth"}},
+			// {Middleware: auth.Authenticated, Schemes: []string{"BearerAuth"}},
 		},
 		RouteRules: []RouteSecurityRule{
 			// {
@@ -51,33 +70,23 @@ func DefaultSecurityInferenceConfig() SecurityInferenceConfig {
 			// },
 		},
 		EnableACLBearerFromPermissions: true,
+		ACLScheme:                      "BearerAuth",
 	}
 }
 
+// inferOperationSecurity returns the security requirements of the rules
+// matching the route. Every matched rule must be satisfied, so the
+// requirements are the combinations of one alternative from each rule.
 func inferOperationSecurity(
 	route, method string,
 	middlewares []func(http.Handler) http.Handler,
 	cfg SecurityInferenceConfig,
 ) []SecurityRequirement {
-	required := make(SecurityRequirement)
+	var matched [][]SecurityRequirement
 
 	for _, mw := range middlewares {
-		name := middlewareRuntimeName(mw)
-		if name == "" {
-			continue
-		}
-
-		for _, rule := range cfg.MiddlewareRules {
-			if strings.TrimSpace(rule.RuleContains) == "" {
-				continue
-			}
-			if !strings.Contains(name, rule.RuleContains) {
-				continue
-			}
-			for _, scheme := range rule.Schemes {
-				required[scheme] = []string{}
-			}
-		}
+		alternatives, _ := cfg.middlewareRequirements(mw)
+		matched = append(matched, alternatives...)
 	}
 
 	for _, rule := range cfg.RouteRules {
@@ -87,16 +96,144 @@ func inferOperationSecurity(
 		if rule.PathSuffix != "" && !strings.HasSuffix(strings.TrimSpace(route), rule.PathSuffix) {
 			continue
 		}
-		for _, scheme := range rule.Schemes {
-			required[scheme] = []string{}
+		if alternatives := securityAlternatives(rule.Schemes, rule.Alternatives, rule.Optional); alternatives != nil {
+			matched = append(matched, alternatives)
 		}
 	}
 
-	if len(required) == 0 {
+	if len(matched) == 0 {
 		return nil
 	}
-	// One requirement object means all listed schemes are required together (AND semantics).
-	return []SecurityRequirement{required}
+	// Each requirement object lists schemes required together (AND); the
+	// list's entries are alternatives (OR).
+	requirements := []SecurityRequirement{{}}
+	for _, alternatives := range matched {
+		requirements = combineSecurityRequirements(requirements, alternatives)
+	}
+	return requirements
+}
+
+// securityAlternatives returns the requirements a rule accepts, or nil when
+// it names no scheme and is not optional.
+func securityAlternatives(schemes []string, alternatives [][]string, optional bool) []SecurityRequirement {
+	var out []SecurityRequirement
+	for _, set := range append([][]string{schemes}, alternatives...) {
+		if len(set) == 0 {
+			continue
+		}
+		req := make(SecurityRequirement, len(set))
+		for _, scheme := range set {
+			req[scheme] = []string{}
+		}
+		out = append(out, req)
+	}
+	if optional {
+		out = append(out, SecurityRequirement{})
+	}
+	return out
+}
+
+// combineSecurityRequirements returns every union of a requirement from
+// left with one from right, without duplicates.
+func combineSecurityRequirements(left, right []SecurityRequirement) []SecurityRequirement {
+	var out []SecurityRequirement
+	for _, l := range left {
+		for _, r := range right {
+			merged := make(SecurityRequirement, len(l)+len(r))
+			for _, req := range []SecurityRequirement{l, r} {
+				for scheme, scopes := range req {
+					merged[scheme] = mergeScopes(merged[scheme], scopes)
+				}
+			}
+			out = appendSecurityRequirement(out, merged)
+		}
+	}
+	return out
+}
+
+// annotatedSecurity applies @Security lines to the inferred requirements.
+// Each line is an alternative written as in swag: "OAuth[read] && ApiKey",
+// "BearerAuth || ApiKey". Declared alternatives replace the inferred ones;
+// "optional" admits anonymous callers, keeping the inferred requirements
+// when it is the only line, and "none" documents a public operation.
+func annotatedSecurity(inferred []SecurityRequirement, lines []string) []SecurityRequirement {
+	var declared []SecurityRequirement
+	optional := false
+	for _, line := range lines {
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "none":
+			return []SecurityRequirement{{}}
+		case "optional":
+			optional = true
+			continue
+		}
+		for _, alternative := range strings.Split(line, "||") {
+			req := make(SecurityRequirement)
+			for _, term := range strings.Split(alternative, "&&") {
+				if name, scopes := parseSecurityTerm(term); name != "" {
+					req[name] = mergeScopes(req[name], scopes)
+				}
+			}
+			if len(req) > 0 {
+				declared = appendSecurityRequirement(declared, req)
+			}
+		}
+	}
+	if len(declared) == 0 {
+		declared = inferred
+	}
+	if optional {
+		declared = appendSecurityRequirement(declared, SecurityRequirement{})
+	}
+	return declared
+}
+
+// parseSecurityTerm splits "OAuth[read, write]" into the scheme and scopes.
+func parseSecurityTerm(term string) (string, []string) {
+	term = strings.TrimSpace(term)
+	name, rest, ok := strings.Cut(term, "[")
+	if !ok {
+		return term, []string{}
+	}
+	scopes := []string{}
+	for _, scope := range strings.Split(strings.TrimSuffix(rest, "]"), ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return strings.TrimSpace(name), scopes
+}
+
+func appendSecurityRequirement(reqs []SecurityRequirement, req SecurityRequirement) []SecurityRequirement {
+	if slices.ContainsFunc(reqs, func(existing SecurityRequirement) bool {
+		return sameSecurityRequirement(existing, req)
+	}) {
+		return reqs
+	}
+	return append(reqs, req)
+}
+
+func mergeScopes(scopes, more []string) []string {
+	out := append([]string{}, scopes...)
+	for _, scope := range more {
+		if !slices.Contains(out, scope) {
+			out = append(out, scope)
+		}
+	}
+	return out
+}
+
+func sameSecurityRequirement(a, b SecurityRequirement) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for scheme, scopes := range a {
+		other, ok := b[scheme]
+		if !ok || !slices.Equal(slices.Sorted(slices.Values(scopes)), slices.Sorted(slices.Values(other))) {
+			return false
+		}
+	}
+	return true
 }
 
 func middlewareRuntimeName(mw func(http.Handler) http.Handler) string {
@@ -108,3 +245,124 @@ func middlewareRuntimeName(mw func(http.Handler) http.Handler) string {
 	}
 	return ""
 }
+
+// closureSuffixRegexp matches the suffix the runtime gives closures:
+// pkg.RequireRole.func1, pkg.RequireRole.func1.2.
+var closureSuffixRegexp = regexp.MustCompile(`\.func\d+(\.func\d+|\.\d+)*$`)
+
+// middlewareConstructorName attributes a closure to the function that
+// created it: pkg.RequireRole.func1 becomes pkg.RequireRole.
+func middlewareConstructorName(name string) string {
+	return closureSuffixRegexp.ReplaceAllString(name, "")
+}
+
+// funcRuntimeName returns the runtime name of a function value, or "".
+func funcRuntimeName(fn any) string {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return ""
+	}
+	if f := runtime.FuncForPC(v.Pointer()); f != nil {
+		return f.Name()
+	}
+	return ""
+}
+
+// matches reports whether the rule identifies the middleware named name.
+func (rule MiddlewareSecurityRule) matches(name string) bool {
+	if rule.Middleware != nil {
+		return middlewareIdentifiedBy(name, rule.Middleware)
+	}
+	return strings.TrimSpace(rule.RuleContains) != "" && strings.Contains(name, rule.RuleContains)
+}
+
+// middlewareIdentifiedBy reports whether the middleware named name is the
+// function target, or a closure target created.
+func middlewareIdentifiedBy(name string, target any) bool {
+	targetName := funcRuntimeName(target)
+	return targetName != "" && (name == targetName || middlewareConstructorName(name) == targetName)
+}
+
+// middlewareRequirements returns the alternatives of each rule or
+// SecurityMiddleware wrapper that identifies mw, and whether any did.
+func (cfg SecurityInferenceConfig) middlewareRequirements(mw func(http.Handler) http.Handler) ([][]SecurityRequirement, bool) {
+	if schemes, ok := securityMiddlewareSchemes(mw); ok {
+		if alternatives := securityAlternatives(schemes, nil, false); alternatives != nil {
+			return [][]SecurityRequirement{alternatives}, true
+		}
+		return nil, true
+	}
+	name := middlewareRuntimeName(mw)
+	if name == "" {
+		return nil, false
+	}
+
+	var requirements [][]SecurityRequirement
+	matched := false
+	for _, rule := range cfg.MiddlewareRules {
+		if !rule.matches(name) {
+			continue
+		}
+		matched = true
+		if alternatives := securityAlternatives(rule.Schemes, rule.Alternatives, rule.Optional); alternatives != nil {
+			requirements = append(requirements, alternatives)
+		}
+	}
+	return requirements, matched
+}
+
+// unmatchedMiddleware names the middlewares no rule identified, attributing
+// closures to their constructors. annot8's own markers are left out.
+func (cfg SecurityInferenceConfig) unmatchedMiddleware(middlewares []func(http.Handler) http.Handler) []string {
+	var names []string
+	for _, mw := range middlewares {
+		if pc := reflect.ValueOf(mw).Pointer(); mw == nil || pc == docMiddlewarePC || pc == rateLimitMiddlewarePC {
+			continue
+		}
+		if _, matched := cfg.middlewareRequirements(mw); matched {
+			continue
+		}
+		if name := middlewareConstructorName(middlewareRuntimeName(mw)); name != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// SecurityMiddleware returns mw marked as requiring schemes. The result
+// behaves exactly as mw; security inference recognizes it without a rule.
+//
+//	r.With(annot8.SecurityMiddleware(auth.Verify, "BearerAuth")).Get("/me", me)
+func SecurityMiddleware(mw func(http.Handler) http.Handler, schemes ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if probe, ok := next.(*securityProbe); ok {
+			probe.schemes = schemes
+			return probe
+		}
+		return mw(next)
+	}
+}
+
+// securityProbe reads the schemes of a SecurityMiddleware wrapper.
+type securityProbe struct {
+	http.Handler
+	schemes []string
+}
+
+// securityMiddlewarePC identifies the closures SecurityMiddleware returns.
+var securityMiddlewarePC = reflect.ValueOf(SecurityMiddleware(nil)).Pointer()
+
+func securityMiddlewareSchemes(mw func(http.Handler) http.Handler) ([]string, bool) {
+	if mw == nil || reflect.ValueOf(mw).Pointer() != securityMiddlewarePC {
+		return nil, false
+	}
+	probe := &securityProbe{}
+	mw(probe)
+	return probe.schemes, true
+}
+
+// requiresSecurity reports whether reqs demand any scheme; an empty
+// requirement ({}) admits anonymous callers.
+func requiresSecurity(reqs []SecurityRequirement) bool {
+	return slices.ContainsFunc(reqs, func(req SecurityRequirement) bool { return len(req) > 0 })
+}
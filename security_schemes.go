@@ -0,0 +1,121 @@
+package annot8
+
+import (
+	"fmt"
+	"maps"
+)
+
+// declareSecuritySchemes adds the schemes cfg declares. annot8 declares
+// none of its own.
+func declareSecuritySchemes(spec *Spec, cfg Config) {
+	maps.Copy(spec.Components.SecuritySchemes, cfg.SecuritySchemes)
+}
+
+// pruneSecuritySchemes removes the declared schemes no security requirement
+// references.
+func pruneSecuritySchemes(spec *Spec) {
+	used := make(map[string]bool)
+	forEachSecurityRequirement(spec, func(_ string, req SecurityRequirement) {
+		for name := range req {
+			used[name] = true
+		}
+	})
+	for name := range spec.Components.SecuritySchemes {
+		if !used[name] {
+			delete(spec.Components.SecuritySchemes, name)
+		}
+	}
+}
+
+// forEachSecurityRequirement calls fn with every security requirement in
+// spec, labelled by the operation declaring it ("" for the root).
+func forEachSecurityRequirement(spec *Spec, fn func(label string, req SecurityRequirement)) {
+	for _, req := range spec.Security {
+		fn("", req)
+	}
+	for _, item := range collectOperations(spec) {
+		label := operationLabel(item.path, item.method, item.op)
+		for _, req := range item.op.Security {
+			fn(label, req)
+		}
+	}
+}
+
+// securitySchemeProblems describes what scheme is missing for its type.
+func securitySchemeProblems(scheme SecurityScheme) []string {
+	switch scheme.Type {
+	case "apiKey":
+		if scheme.Name == "" || (scheme.In != "query" && scheme.In != "header" && scheme.In != "cookie") {
+			return []string{"apiKey needs a name and in (query, header or cookie)"}
+		}
+	case "http":
+		if scheme.Scheme == "" {
+			return []string{"http needs a scheme"}
+		}
+	case "oauth2":
+		if scheme.Flows == nil || len(oauthFlows(scheme.Flows)) == 0 {
+			return []string{"oauth2 needs at least one flow"}
+		}
+		var problems []string
+		for name, flow := range oauthFlows(scheme.Flows) {
+			if problem := oauthFlowProblem(name, flow); problem != "" {
+				problems = append(problems, problem)
+			}
+		}
+		return problems
+	case "openIdConnect":
+		if scheme.OpenIDConnectURL == "" {
+			return []string{"openIdConnect needs an openIdConnectUrl"}
+		}
+	case "mutualTLS":
+	default:
+		return []string{fmt.Sprintf("unknown type %q", scheme.Type)}
+	}
+	return nil
+}
+
+func oauthFlowProblem(name string, flow *OAuthFlow) string {
+	needsAuthorization := name == "implicit" || name == "authorizationCode"
+	needsToken := name != "implicit"
+	switch {
+	case needsAuthorization && flow.AuthorizationURL == "":
+		return name + " flow needs an authorizationUrl"
+	case name == "deviceAuthorization" && flow.DeviceAuthorizationURL == "":
+		return name + " flow needs a deviceAuthorizationUrl"
+	case name != "deviceAuthorization" && flow.DeviceAuthorizationURL != "":
+		return name + " flow cannot have a deviceAuthorizationUrl"
+	case needsToken && flow.TokenURL == "":
+		return name + " flow needs a tokenUrl"
+	}
+	return ""
+}
+
+// oauthFlows returns the flows set in flows by their field name.
+func oauthFlows(flows *OAuthFlows) map[string]*OAuthFlow {
+	out := make(map[string]*OAuthFlow)
+	for name, flow := range map[string]*OAuthFlow{
+		"implicit":            flows.Implicit,
+		"password":            flows.Password,
+		"clientCredentials":   flows.ClientCredentials,
+		"authorizationCode":   flows.AuthorizationCode,
+		"deviceAuthorization": flows.DeviceAuthorization,
+	} {
+		if flow != nil {
+			out[name] = flow
+		}
+	}
+	return out
+}
+
+// oauthScopeDefined reports whether any of scheme's flows defines scope.
+func oauthScopeDefined(scheme SecurityScheme, scope string) bool {
+	if scheme.Flows == nil {
+		return false
+	}
+	for _, flow := range oauthFlows(scheme.Flows) {
+		if _, ok := flow.Scopes[scope]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -11,15 +11,16 @@ import (
 // Config defines the configuration for OpenAPI specification generation.
 // All fields except Title and Version are optional.
 type Config struct {
-	Title             string                   // Required: API title
-	Summary           string                   // Optional: API summary
-	Description       string                   // Optional: API description
-	Version           string                   // Required: API version (e.g., "1.0.0")
-	TermsOfService    string                   // Optional: Terms of service URL
-	Servers           []string                 // Optional: List of base server URLs
-	Contact           *Contact                 // Optional: Contact information
-	License           *License                 // Optional: License information
-	SecurityInference *SecurityInferenceConfig // Optional: security inference override
+	Title             string                    // Required: API title
+	Summary           string                    // Optional: API summary
+	Description       string                    // Optional: API description
+	Version           string                    // Required: API version (e.g., "1.0.0")
+	TermsOfService    string                    // Optional: Terms of service URL
+	Servers           []string                  // Optional: List of base server URLs
+	Contact           *Contact                  // Optional: Contact information
+	License           *License                  // Optional: License information
+	SecurityInference *SecurityInferenceConfig  // Optional: security inference override
+	SecuritySchemes   map[string]SecurityScheme // Optional: security schemes; unreferenced ones are pruned
 }
 
 // Contact represents contact information for the API.
@@ -48,6 +49,10 @@ type Spec struct {
 	Tags              []Tag                  `json:"tags,omitempty"`
 	Security          []SecurityRequirement  `json:"security,omitempty"`
 	ExternalDocs      *ExternalDocumentation `json:"externalDocs,omitempty"`
+	TagGroups         []TagGroup             `json:"x-tagGroups,omitempty"`
+
+	// Internal validation metadata (not serialized in OpenAPI output).
+	schemaDiagnostics []string `json:"-"`
 }
 
 // Info captures high-level metadata about the API.
@@ -104,6 +109,8 @@ type Operation struct {
 	hasTagsAnnotation     bool     `json:"-"`
 	hasSuccessAnnotation  bool     `json:"-"`
 	annotationParseErrors []string `json:"-"`
+	pathParamConflicts    []string `json:"-"`
+	unmatchedMiddleware   []string `json:"-"`
 	routePattern          string   `json:"-"`
 	httpMethod            string   `json:"-"`
 }
@@ -182,11 +189,13 @@ func responseCodeSortParts(code string) (group int, numeric int, text string) {
 
 // Parameter describes a path/query/header parameter.
 type Parameter struct {
-	Name        string  `json:"name"`
-	In          string  `json:"in"`
-	Description string  `json:"description,omitempty"`
-	Required    bool    `json:"required,omitempty"`
-	Schema      *Schema `json:"schema,omitempty"`
+	Name          string  `json:"name"`
+	In            string  `json:"in"`
+	Description   string  `json:"description,omitempty"`
+	Required      bool    `json:"required,omitempty"`
+	AllowReserved bool    `json:"allowReserved,omitempty"`
+	Schema        *Schema `json:"schema,omitempty"`
+	Wildcard      bool    `json:"x-wildcard,omitempty"` // matches the rest of the path (chi's *)
 }
 
 // RequestBody describes an HTTP request payload.
@@ -206,12 +215,26 @@ type MediaTypeObject struct {
 
 // Response captures the structure of an HTTP response.
 type Response struct {
+	Ref         string                     `json:"$ref,omitempty"`
 	Description string                     `json:"description"`
 	Headers     map[string]Header          `json:"headers,omitempty"`
 	Content     map[string]MediaTypeObject `json:"content,omitempty"`
 	Links       map[string]Link            `json:"links,omitempty"`
 }
 
+// MarshalJSON writes a response with a Ref as a Reference Object, which
+// carries no required description.
+func (r Response) MarshalJSON() ([]byte, error) {
+	if r.Ref != "" {
+		return json.Marshal(struct {
+			Ref         string `json:"$ref"`
+			Description string `json:"description,omitempty"`
+		}{r.Ref, r.Description})
+	}
+	type response Response
+	return json.Marshal(response(r))
+}
+
 // Schema represents an OpenAPI schema definition.
 type Schema struct {
 	Type                 any                `json:"type,omitempty"`
@@ -222,28 +245,51 @@ type Schema struct {
 	Ref                  string             `json:"$ref,omitempty"`
 	Description          string             `json:"description,omitempty"`
 
+	ID      string             `json:"$id,omitempty"`
+	Comment string             `json:"$comment,omitempty"`
+	Defs    map[string]*Schema `json:"$defs,omitempty"`
+
 	Format           string   `json:"format,omitempty"`
 	Pattern          string   `json:"pattern,omitempty"`
 	Minimum          *float64 `json:"minimum,omitempty"`
 	Maximum          *float64 `json:"maximum,omitempty"`
 	ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty"`
 	ExclusiveMaximum *float64 `json:"exclusiveMaximum,omitempty"`
+	MultipleOf       *float64 `json:"multipleOf,omitempty"`
 	MinLength        *int     `json:"minLength,omitempty"`
 	MaxLength        *int     `json:"maxLength,omitempty"`
 	MinItems         *int     `json:"minItems,omitempty"`
 	MaxItems         *int     `json:"maxItems,omitempty"`
 	UniqueItems      *bool    `json:"uniqueItems,omitempty"`
+	MinContains      *int     `json:"minContains,omitempty"`
+	MaxContains      *int     `json:"maxContains,omitempty"`
+	MinProperties    *int     `json:"minProperties,omitempty"`
+	MaxProperties    *int     `json:"maxProperties,omitempty"`
 	Enum             []any    `json:"enum,omitempty"`
 	Const            any      `json:"const,omitempty"`
 	Default          any      `json:"default,omitempty"`
 	Example          any      `json:"example,omitempty"`
 	Examples         []any    `json:"examples,omitempty"`
 
+	PatternProperties     map[string]*Schema `json:"patternProperties,omitempty"`
+	PropertyNames         *Schema            `json:"propertyNames,omitempty"`
+	UnevaluatedProperties any                `json:"unevaluatedProperties,omitempty"`
+	PrefixItems           []*Schema          `json:"prefixItems,omitempty"`
+	Contains              *Schema            `json:"contains,omitempty"`
+	ContentEncoding       string             `json:"contentEncoding,omitempty"`
+	ContentMediaType      string             `json:"contentMediaType,omitempty"`
+
 	OneOf []*Schema `json:"oneOf,omitempty"`
 	AnyOf []*Schema `json:"anyOf,omitempty"`
 	AllOf []*Schema `json:"allOf,omitempty"`
 	Not   *Schema   `json:"not,omitempty"`
 
+	If                *Schema             `json:"if,omitempty"`
+	Then              *Schema             `json:"then,omitempty"`
+	Else              *Schema             `json:"else,omitempty"`
+	DependentRequired map[string][]string `json:"dependentRequired,omitempty"`
+	DependentSchemas  map[string]*Schema  `json:"dependentSchemas,omitempty"`
+
 	Title         string                 `json:"title,omitempty"`
 	Deprecated    *bool                  `json:"deprecated,omitempty"`
 	ReadOnly      *bool                  `json:"readOnly,omitempty"`
@@ -335,14 +381,51 @@ type Webhooks map[string]*PathItem
 // SecurityRequirement represents a security requirement.
 type SecurityRequirement map[string][]string
 
-// SecurityScheme represents a security scheme configuration.
+// SecurityScheme represents a security scheme configuration. Type is one of
+// apiKey, http, mutualTLS, oauth2 or openIdConnect.
 type SecurityScheme struct {
-	Type         string `json:"type"`
-	Name         string `json:"name,omitempty"`
-	In           string `json:"in,omitempty"`
-	Scheme       string `json:"scheme,omitempty"`
-	BearerFormat string `json:"bearerFormat,omitempty"`
-	Description  string `json:"description,omitempty"`
+	Type             string      `json:"type"`
+	Name             string      `json:"name,omitempty"`
+	In               string      `json:"in,omitempty"`
+	Scheme           string      `json:"scheme,omitempty"`
+	BearerFormat     string      `json:"bearerFormat,omitempty"`
+	Flows            *OAuthFlows `json:"flows,omitempty"`
+	OpenIDConnectURL string      `json:"openIdConnectUrl,omitempty"`
+	Description      string      `json:"description,omitempty"`
+}
+
+// OAuthFlows lists the OAuth2 flows an oauth2 scheme supports.
+type OAuthFlows struct {
+	Implicit          *OAuthFlow `json:"implicit,omitempty"`
+	Password          *OAuthFlow `json:"password,omitempty"`
+	ClientCredentials *OAuthFlow `json:"clientCredentials,omitempty"`
+	AuthorizationCode *OAuthFlow `json:"authorizationCode,omitempty"`
+	// DeviceAuthorization is the OAuth 2.0 device flow (RFC 8628). OpenAPI
+	// only defines it from 3.2, and 3.1 forbids unknown keys here, so it is
+	// written as the x-deviceAuthorization extension while specs are 3.1.
+	DeviceAuthorization *OAuthFlow `json:"x-deviceAuthorization,omitempty"`
+}
+
+// OAuthFlow describes one OAuth2 flow. Scopes maps scope names to their
+// descriptions and is always written, as the specification requires.
+type OAuthFlow struct {
+	AuthorizationURL string `json:"authorizationUrl,omitempty"`
+	// DeviceAuthorizationURL belongs to the DeviceAuthorization flow only,
+	// whose extension object may carry it.
+	DeviceAuthorizationURL string            `json:"deviceAuthorizationUrl,omitempty"`
+	TokenURL               string            `json:"tokenUrl,omitempty"`
+	RefreshURL             string            `json:"refreshUrl,omitempty"`
+	Scopes                 map[string]string `json:"scopes"`
+}
+
+// MarshalJSON writes a nil Scopes as an empty object.
+func (f OAuthFlow) MarshalJSON() ([]byte, error) {
+	type flow OAuthFlow
+	out := flow(f)
+	if out.Scopes == nil {
+		out.Scopes = map[string]string{}
+	}
+	return json.Marshal(out)
 }
 
 // Tag represents an OpenAPI tag entry.
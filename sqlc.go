@@ -0,0 +1,348 @@
+package annot8
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"math"
+	"strings"
+)
+
+// sqlcModel is a table together with the names sqlc gives its columns in
+// the generated struct.
+type sqlcModel struct {
+	table  *sqlTable
+	fields map[string]*sqlColumn // sqlKey(Go field name) -> column
+	json   map[string]*sqlColumn // JSON tag -> column, with emit_json_tags
+}
+
+// LoadSQLCConfig reads a sqlc.yaml (or sqlc.json) file and the schema files
+// and migrations it references. Model structs sqlc generated from a table
+// then document the table's constraints: varchar lengths, NOT NULL, CHECK
+// enums and bounds, numeric precision and COMMENT ON text. The types sqlc
+// substitutes (sql_package and go_type overrides) get external mappings
+// unless one is already configured.
+func (sg *SchemaGenerator) LoadSQLCConfig(path string) error {
+	packages, err := readSQLCConfig(path)
+	if err != nil {
+		return err
+	}
+
+	tables := make(map[string]map[string]*sqlcModel)
+	for _, pkg := range packages {
+		ddl := newSQLSchema()
+		for _, schemaPath := range pkg.schema {
+			if err := ddl.loadPath(schemaPath); err != nil {
+				return fmt.Errorf("sqlc package %s: %w", pkg.goName, err)
+			}
+		}
+
+		models := make(map[string]*sqlcModel, len(ddl.tables))
+		for _, table := range ddl.tables {
+			models[sqlKey(pkg.modelName(table))] = pkg.model(table)
+		}
+		tables[pkg.importPath()] = models
+		sg.typeIndex.addMissingExternalTypes(pkg.typeMappings(ddl))
+		slog.Debug("[annot8] LoadSQLCConfig: loaded schema", "package", pkg.goName, "tables", len(models))
+	}
+
+	sg.mutex.Lock()
+	sg.sqlcTables = tables
+	sg.mutex.Unlock()
+	return nil
+}
+
+// LoadSQLCConfig reads a sqlc.yaml (or sqlc.json) file so that sqlc model
+// schemas document the constraints of their tables.
+func (g *Generator) LoadSQLCConfig(path string) error {
+	return g.schemaGen.LoadSQLCConfig(path)
+}
+
+// modelName returns the name sqlc gives the model struct of table: the
+// singular table name, prefixed by its schema outside the default one,
+// unless renamed.
+func (p sqlcPackage) modelName(table *sqlTable) string {
+	name := table.name
+	if !p.exactTableNames {
+		name = singularize(name)
+	}
+	if table.schema != "" {
+		name = table.schema + "_" + name
+	}
+	if renamed, ok := p.rename[name]; ok {
+		return renamed
+	}
+	return name
+}
+
+// model indexes table's columns by the Go field names and JSON tags sqlc
+// generates for them.
+func (p sqlcPackage) model(table *sqlTable) *sqlcModel {
+	m := &sqlcModel{
+		table:  table,
+		fields: make(map[string]*sqlColumn, len(table.columns)),
+		json:   make(map[string]*sqlColumn, len(table.columns)),
+	}
+	for _, column := range table.columns {
+		field := column.name
+		if renamed, ok := p.rename[column.name]; ok {
+			field = renamed
+		}
+		m.fields[sqlKey(field)] = column
+		if p.emitJSONTags {
+			m.json[jsonTagName(column.name, p.jsonCaseStyle)] = column
+		}
+	}
+	return m
+}
+
+// column returns the column behind a struct member: by Go field name, then
+// by JSON tag.
+func (m *sqlcModel) column(member jsonField) *sqlColumn {
+	if column := m.fields[sqlKey(member.goName)]; column != nil {
+		return column
+	}
+	return m.json[member.name]
+}
+
+// jsonTagName spells a column name in a json_tags_case_style.
+func jsonTagName(column, style string) string {
+	if style == "" || style == "none" || style == "snake" {
+		return column
+	}
+	var b strings.Builder
+	for i, word := range strings.Split(column, "_") {
+		if word == "" {
+			continue
+		}
+		if i == 0 && style == "camel" {
+			b.WriteString(word)
+			continue
+		}
+		b.WriteString(capitalize(word))
+	}
+	return b.String()
+}
+
+// typeMappings returns external type mappings for the types sqlc generates:
+// the standard library types every driver uses, the nullable wrappers of
+// pgx/v5, and each go_type override, described by the preset catalog or
+// else by the overridden database type (the column's type for column
+// overrides). Nullable columns generated as pointers
+// (emit_pointers_for_null_types) need no mapping: pointers are nullable.
+func (p sqlcPackage) typeMappings(ddl *sqlSchema) map[string]*Schema {
+	mappings := maps.Clone(Presets.StdLib.Types)
+	if p.sqlPackage == "pgx/v5" {
+		maps.Copy(mappings, Presets.PgxV5.Types)
+	}
+
+	catalog := presetCatalog()
+	for _, o := range p.overrides {
+		if !strings.Contains(o.goType, ".") {
+			continue // predeclared types need no mapping
+		}
+		if _, seen := mappings[o.goType]; seen {
+			continue
+		}
+		dbType := o.dbType
+		if dbType == "" {
+			dbType = ddl.columnType(o.column)
+		}
+		if schema, ok := catalog[o.goType]; ok {
+			mappings[o.goType] = schema
+		} else if schema := dbTypeSchema(dbType); schema != nil {
+			mappings[o.goType] = schema
+		}
+	}
+	return mappings
+}
+
+// presetCatalog merges the types of every curated preset.
+func presetCatalog() map[string]*Schema {
+	catalog := make(map[string]*Schema)
+	for _, preset := range []Preset{Presets.StdLib, Presets.PgxV5, Presets.GoogleUUID, Presets.ShopspringDecimal} {
+		maps.Copy(catalog, preset.Types)
+	}
+	return catalog
+}
+
+// dbTypeSchema maps a database type to the JSON type and format its values
+// take, or nil for types without a fixed representation.
+func dbTypeSchema(dbType string) *Schema {
+	switch canonicalSQLType(strings.TrimPrefix(strings.ToLower(dbType), "pg_catalog.")) {
+	case "uuid":
+		return &Schema{Type: "string", Format: "uuid"}
+	case "timestamptz", "timestamp", "timestamp with time zone", "timestamp without time zone":
+		return &Schema{Type: "string", Format: "date-time"}
+	case "date":
+		return &Schema{Type: "string", Format: "date"}
+	case "time", "timetz", "time with time zone", "time without time zone":
+		return &Schema{Type: "string", Format: "time"}
+	case "interval":
+		return &Schema{Type: "string", Format: "duration"}
+	case "inet":
+		return &Schema{Type: "string", Description: "IPv4 or IPv6 address"}
+	case "cidr":
+		return &Schema{Type: "string", Description: "IP network prefix in CIDR notation"}
+	case "text", "varchar", "char", "citext", "name":
+		return &Schema{Type: "string"}
+	case "bool", "boolean":
+		return &Schema{Type: "boolean"}
+	case "int2", "smallint", "smallserial", "int4", "int", "integer", "serial":
+		return &Schema{Type: "integer", Format: "int32"}
+	case "int8", "bigint", "bigserial":
+		return &Schema{Type: "integer", Format: "int64"}
+	case "float4", "real":
+		return &Schema{Type: "number", Format: "float"}
+	case "float8", "double precision", "numeric":
+		return &Schema{Type: "number", Format: "double"}
+	case "bytea", "blob":
+		return &Schema{Type: "string", ContentEncoding: "base64"}
+	case "json", "jsonb":
+		return &Schema{Description: "Any JSON value"}
+	}
+	return nil
+}
+
+// singularize applies the inflection rules that matter for table names.
+func singularize(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, "ies") && len(name) > 3:
+		return name[:len(name)-3] + "y"
+	case strings.HasSuffix(lower, "sses"), strings.HasSuffix(lower, "shes"),
+		strings.HasSuffix(lower, "ches"), strings.HasSuffix(lower, "xes"):
+		return name[:len(name)-2]
+	case strings.HasSuffix(lower, "ss"), strings.HasSuffix(lower, "us"), strings.HasSuffix(lower, "is"):
+		return name
+	case strings.HasSuffix(lower, "s"):
+		return name[:len(name)-1]
+	}
+	return name
+}
+
+// sqlcModel returns the table behind the struct being converted, if it is a
+// model of a loaded sqlc package.
+func (sg *SchemaGenerator) sqlcModel() *sqlcModel {
+	if sg.sqlcTables == nil || sg.currentType == "" {
+		return nil
+	}
+	models := sg.sqlcTables[sg.currentPackage]
+	if models == nil {
+		return nil
+	}
+	return models[sqlKey(unqualifiedName(sg.currentType))]
+}
+
+// applyColumnConstraints documents a column's DDL constraints on the schema
+// of the field it is generated into. NOT NULL removes null from nullable
+// types such as pgtype.Text; the other constraints apply to inline schemas
+// of the matching JSON type, so that struct tags may still override them.
+func applyColumnConstraints(schema *Schema, column *sqlColumn) {
+	if column.notNull && stripNull(schema) {
+		trimNullableWording(schema)
+	}
+	if schema.Ref != "" {
+		return
+	}
+	if schema.Description == "" {
+		schema.Description = column.comment
+	}
+	// Strings stored in typed columns (uuid, date, ...) take the type's format.
+	if hasType(schema, "string") && schema.Format == "" {
+		if db := dbTypeSchema(column.dataType); db != nil && db.Type == "string" {
+			schema.Format = db.Format
+		}
+	}
+
+	for _, target := range constraintTargets(schema) {
+		switch primaryType(target) {
+		case "string":
+			applyStringColumn(target, column)
+		case "integer", "number":
+			applyNumericColumn(target, column)
+		}
+		if len(column.enum) > 0 && len(target.Enum) == 0 && primaryType(target) != "" &&
+			primaryType(target) != "array" && primaryType(target) != "object" {
+			for _, raw := range column.enum {
+				if v, err := coerceTagValue(&Schema{Type: primaryType(target)}, raw); err == nil {
+					target.Enum = append(target.Enum, v)
+				}
+			}
+			if isNullable(target) {
+				target.Enum = append(target.Enum, nil)
+			}
+		}
+	}
+}
+
+// constraintTargets returns the schema, or its typed anyOf members when the
+// JSON type is a union (pgtype.Numeric is a number or a special string).
+func constraintTargets(schema *Schema) []*Schema {
+	if primaryType(schema) != "" {
+		return []*Schema{schema}
+	}
+	var targets []*Schema
+	for _, member := range schema.AnyOf {
+		if member.Ref == "" && len(member.Enum) == 0 && primaryType(member) != "" && primaryType(member) != "null" {
+			targets = append(targets, member)
+		}
+	}
+	return targets
+}
+
+func applyStringColumn(schema *Schema, column *sqlColumn) {
+	if schema.MaxLength == nil {
+		switch {
+		case column.maxLength != nil:
+			schema.MaxLength = column.maxLength
+		case (column.dataType == "varchar" || column.dataType == "char") && len(column.modifiers) == 1:
+			n := column.modifiers[0]
+			schema.MaxLength = &n
+		}
+	}
+	// Decimals documented as strings (shopspring/decimal) get a pattern.
+	if column.dataType == "numeric" && len(column.modifiers) > 0 && schema.Pattern == "" && schema.Format == "" {
+		precision, scale := numericModifiers(column.modifiers)
+		if integer := precision - scale; integer > 0 {
+			pattern := fmt.Sprintf(`^-?\d{1,%d}`, integer)
+			if scale > 0 {
+				pattern += fmt.Sprintf(`(\.\d{1,%d})?`, scale)
+			}
+			schema.Pattern = pattern + "$"
+		}
+	}
+}
+
+func applyNumericColumn(schema *Schema, column *sqlColumn) {
+	if column.dataType == "numeric" && len(column.modifiers) > 0 &&
+		schema.ExclusiveMaximum == nil && schema.Maximum == nil {
+		precision, scale := numericModifiers(column.modifiers)
+		limit := math.Pow10(precision - scale)
+		lower := -limit
+		schema.ExclusiveMaximum = &limit
+		schema.ExclusiveMinimum = &lower
+	}
+	for _, bound := range column.bounds {
+		value := bound.value
+		switch bound.op {
+		case ">=":
+			schema.Minimum, schema.ExclusiveMinimum = &value, nil
+		case ">":
+			schema.ExclusiveMinimum, schema.Minimum = &value, nil
+		case "<=":
+			schema.Maximum, schema.ExclusiveMaximum = &value, nil
+		case "<":
+			schema.ExclusiveMaximum, schema.Maximum = &value, nil
+		}
+	}
+}
+
+// numericModifiers reads numeric(p) or numeric(p, s).
+func numericModifiers(modifiers []int) (precision, scale int) {
+	precision = modifiers[0]
+	if len(modifiers) > 1 {
+		scale = modifiers[1]
+	}
+	return precision, scale
+}
@@ -0,0 +1,227 @@
+package annot8
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// sqlcPackage is one Go package generated by sqlc: a "sql" entry of a
+// version 2 config or a "packages" entry of a version 1 config.
+type sqlcPackage struct {
+	schema []string // schema files or migration directories, absolute
+	goName string   // Go package name
+	outDir string   // directory of the generated code, absolute
+
+	sqlPackage      string // database/sql (the default), pgx/v4 or pgx/v5
+	exactTableNames bool
+	emitJSONTags    bool
+	jsonCaseStyle   string // none (the default), camel, pascal or snake
+	overrides       []sqlcOverride
+	rename          map[string]string // column or singular table name -> Go name
+}
+
+// sqlcOverride replaces the Go type sqlc generates for a database type or a
+// single column.
+type sqlcOverride struct {
+	dbType   string
+	column   string // table.column
+	goType   string // import/path.Type, or a predeclared type
+	pointer  bool
+	slice    bool
+	nullable bool
+}
+
+// readSQLCConfig reads a sqlc.yaml, sqlc.yml or sqlc.json file. Relative
+// paths in it are resolved against the file's directory, as sqlc does.
+func readSQLCConfig(path string) ([]sqlcPackage, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	// #nosec G304 -- build-time codegen tool; path is the caller's sqlc config.
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+
+	var doc any
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &doc)
+	} else {
+		err = yaml.Unmarshal(data, &doc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	root, ok := doc.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: expected a mapping at the top level", path)
+	}
+
+	dir := filepath.Dir(path)
+	var packages []sqlcPackage
+	switch version := configString(root, "version"); version {
+	case "2":
+		// Global settings live under overrides.go.
+		global := configMap(configMap(root, "overrides"), "go")
+		for _, entry := range configList(root, "sql") {
+			sql, _ := entry.(map[string]any)
+			gen, _ := configMap(sql, "gen")["go"].(map[string]any)
+			if gen == nil {
+				continue
+			}
+			out := resolveConfigPath(dir, configString(gen, "out"))
+			packages = append(packages, newSQLCPackage(sql, gen, global, out, configString(gen, "package"), dir))
+		}
+	case "1":
+		for _, entry := range configList(root, "packages") {
+			pkg, _ := entry.(map[string]any)
+			out := resolveConfigPath(dir, configString(pkg, "path"))
+			packages = append(packages, newSQLCPackage(pkg, pkg, root, out, configString(pkg, "name"), dir))
+		}
+	default:
+		return nil, fmt.Errorf("%s: unsupported sqlc config version %q", path, version)
+	}
+	return packages, nil
+}
+
+// newSQLCPackage builds a package from the entry naming its schema, the
+// mapping holding its Go options (the same mapping in version 1 configs) and
+// the mapping holding config-wide overrides and renames.
+func newSQLCPackage(entry, gen, global map[string]any, outDir, goName, dir string) sqlcPackage {
+	if goName == "" {
+		goName = filepath.Base(outDir)
+	}
+	pkg := sqlcPackage{
+		goName:          goName,
+		outDir:          outDir,
+		sqlPackage:      configString(gen, "sql_package"),
+		exactTableNames: configBool(gen, "emit_exact_table_names"),
+		emitJSONTags:    configBool(gen, "emit_json_tags"),
+		jsonCaseStyle:   configString(gen, "json_tags_case_style"),
+		rename:          make(map[string]string),
+	}
+	for _, schema := range configStrings(entry, "schema") {
+		pkg.schema = append(pkg.schema, resolveConfigPath(dir, schema))
+	}
+
+	// Package overrides are consulted before config-wide ones, as in sqlc.
+	for _, source := range []map[string]any{gen, global} {
+		for _, raw := range configList(source, "overrides") {
+			if o, ok := parseSQLCOverride(raw); ok {
+				pkg.overrides = append(pkg.overrides, o)
+			}
+		}
+	}
+	for _, source := range []map[string]any{global, gen} {
+		for from, to := range configMap(source, "rename") {
+			if name, ok := to.(string); ok {
+				pkg.rename[from] = name
+			}
+		}
+	}
+	return pkg
+}
+
+// parseSQLCOverride reads an overrides entry. go_type is either a string
+// ("github.com/google/uuid.UUID", "*time.Time") or a mapping with import,
+// package, type, pointer and slice keys.
+func parseSQLCOverride(raw any) (sqlcOverride, bool) {
+	entry, ok := raw.(map[string]any)
+	if !ok {
+		return sqlcOverride{}, false
+	}
+	o := sqlcOverride{
+		dbType:   strings.ToLower(configString(entry, "db_type")),
+		column:   configString(entry, "column"),
+		nullable: configBool(entry, "nullable"),
+	}
+
+	switch goType := entry["go_type"].(type) {
+	case string:
+		o.goType = goType
+		if rest, ok := strings.CutPrefix(o.goType, "[]"); ok {
+			o.goType, o.slice = rest, true
+		}
+		if rest, ok := strings.CutPrefix(o.goType, "*"); ok {
+			o.goType, o.pointer = rest, true
+		}
+	case map[string]any:
+		o.goType = configString(goType, "type")
+		if importPath := configString(goType, "import"); importPath != "" {
+			o.goType = importPath + "." + o.goType
+		}
+		o.pointer = configBool(goType, "pointer")
+		o.slice = configBool(goType, "slice")
+	}
+	if o.goType == "" || (o.dbType == "" && o.column == "") {
+		return sqlcOverride{}, false
+	}
+	return o, true
+}
+
+// importPath returns the import path of the generated package.
+func (p sqlcPackage) importPath() string {
+	root := findProjectRoot()
+	if root == "" {
+		return p.goName
+	}
+	return projectImportPath(root, p.outDir)
+}
+
+func resolveConfigPath(dir, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+func configMap(m map[string]any, key string) map[string]any {
+	v, _ := m[key].(map[string]any)
+	return v
+}
+
+func configList(m map[string]any, key string) []any {
+	v, _ := m[key].([]any)
+	return v
+}
+
+func configString(m map[string]any, key string) string {
+	switch v := m[key].(type) {
+	case string:
+		return v
+	case float64, int:
+		// Configs may write version: 2 unquoted.
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
+func configBool(m map[string]any, key string) bool {
+	switch v := m[key].(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	}
+	return false
+}
+
+// configStrings reads a value that may be a single string or a list.
+func configStrings(m map[string]any, key string) []string {
+	if s := configString(m, key); s != "" {
+		return []string{s}
+	}
+	var out []string
+	for _, v := range configList(m, key) {
+		if s, ok := v.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,922 @@
+package annot8
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// sqlTable is a table as declared by schema DDL and migrations.
+type sqlTable struct {
+	schema  string // "" for the default schema
+	name    string
+	comment string
+	columns map[string]*sqlColumn // keyed by sqlKey(column name)
+}
+
+// sqlColumn holds the constraints of a column that JSON Schema can express.
+type sqlColumn struct {
+	name      string
+	dataType  string // lower-case base type with aliases folded: varchar, numeric
+	modifiers []int  // length, or precision and scale
+	notNull   bool
+	comment   string
+	enum      []string // CHECK (col IN (...)) values as written
+	maxLength *int     // CHECK (length(col) <= n)
+	bounds    []sqlBound
+}
+
+// sqlBound is a CHECK comparison of a column against a constant.
+type sqlBound struct {
+	op    string // >=, >, <=, <
+	value float64
+}
+
+// sqlSchema accumulates the tables declared by a sequence of DDL files.
+type sqlSchema struct {
+	tables map[string]*sqlTable // keyed by tableKey
+}
+
+func newSQLSchema() *sqlSchema {
+	return &sqlSchema{tables: make(map[string]*sqlTable)}
+}
+
+// sqlKey folds an identifier for matching: lower case, underscores dropped,
+// so that "created_at" matches the Go field CreatedAt.
+func sqlKey(name string) string {
+	return strings.ReplaceAll(strings.ToLower(name), "_", "")
+}
+
+// loadPath reads a schema file, or every .sql file of a migrations directory
+// in lexical order as sqlc does. Down migrations are skipped.
+func (s *sqlSchema) loadPath(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	files := []string{path}
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return err
+		}
+		files = files[:0]
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.IsDir() || !strings.HasSuffix(name, ".sql") || strings.HasSuffix(name, ".down.sql") {
+				continue
+			}
+			files = append(files, filepath.Join(path, name))
+		}
+		sort.Strings(files)
+	}
+
+	for _, file := range files {
+		// #nosec G304 -- build-time codegen tool; files come from the sqlc config.
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		s.parse(upMigration(string(data)))
+	}
+	return nil
+}
+
+// upMigration drops the down section of goose and dbmate migrations.
+func upMigration(src string) string {
+	lines := strings.Split(src, "\n")
+	for i, line := range lines {
+		marker := strings.ToLower(strings.Join(strings.Fields(line), " "))
+		if marker == "-- +goose down" || marker == "-- migrate:down" {
+			return strings.Join(lines[:i], "\n")
+		}
+	}
+	return src
+}
+
+// parse applies the CREATE TABLE, ALTER TABLE, COMMENT ON and DROP TABLE
+// statements of src. Other statements are ignored.
+func (s *sqlSchema) parse(src string) {
+	for _, stmt := range splitSQLStatements(src) {
+		switch {
+		case stmt.keywords("CREATE"):
+			s.createTable(stmt)
+		case stmt.keywords("ALTER", "TABLE"):
+			s.alterTable(stmt[2:])
+		case stmt.keywords("COMMENT", "ON"):
+			s.comment(stmt[2:])
+		case stmt.keywords("DROP", "TABLE"):
+			s.dropTable(stmt[2:])
+		}
+	}
+}
+
+func (s *sqlSchema) lookup(schema, name string) *sqlTable {
+	return s.tables[tableKey(schema, name)]
+}
+
+// columnType returns the data type of a "table.column" or
+// "schema.table.column" reference, or "".
+func (s *sqlSchema) columnType(ref string) string {
+	parts := strings.Split(ref, ".")
+	if len(parts) < 2 {
+		return ""
+	}
+	schema := ""
+	if len(parts) > 2 {
+		schema = parts[len(parts)-3]
+	}
+	table := s.lookup(schema, parts[len(parts)-2])
+	if table == nil {
+		return ""
+	}
+	if column := table.columns[sqlKey(parts[len(parts)-1])]; column != nil {
+		return column.dataType
+	}
+	return ""
+}
+
+// tableKey keys a table by schema and name; the default schema is "".
+func tableKey(schema, name string) string {
+	if strings.EqualFold(schema, "public") || strings.EqualFold(schema, "dbo") {
+		schema = ""
+	}
+	return sqlKey(schema) + "." + sqlKey(name)
+}
+
+func (s *sqlSchema) createTable(stmt sqlTokens) {
+	i := 1
+	for i < len(stmt) && !stmt[i].is("TABLE") {
+		switch strings.ToUpper(stmt[i].text) {
+		case "TEMP", "TEMPORARY", "UNLOGGED", "GLOBAL", "LOCAL", "OR", "REPLACE":
+			i++
+		default:
+			return // CREATE INDEX, CREATE TYPE, ...
+		}
+	}
+	if i >= len(stmt) {
+		return // truncated: CREATE TEMP;
+	}
+	i++
+	if stmt[i:].keywords("IF", "NOT", "EXISTS") {
+		i += 3
+	}
+	schema, name, i := stmt.qualifiedName(i)
+	if name == "" || i >= len(stmt) || !stmt[i].is("(") {
+		return // CREATE TABLE ... AS, PARTITION OF
+	}
+	body, end := stmt.group(i)
+
+	table := &sqlTable{schema: schema, name: name, columns: make(map[string]*sqlColumn)}
+	for _, element := range body.splitTopLevel(",") {
+		table.addElement(element)
+	}
+	// MySQL table options: COMMENT = 'text'.
+	for j := end; j < len(stmt); j++ {
+		if stmt[j].is("COMMENT") {
+			if k := j + 1; k < len(stmt) && stmt[k].is("=") {
+				j = k
+			}
+			if j+1 < len(stmt) && stmt[j+1].kind == sqlString {
+				table.comment = stmt[j+1].text
+			}
+		}
+	}
+	s.tables[tableKey(schema, name)] = table
+}
+
+// addElement adds a column definition or applies a table constraint.
+func (t *sqlTable) addElement(element sqlTokens) {
+	if len(element) == 0 {
+		return
+	}
+	switch strings.ToUpper(element[0].text) {
+	case "CONSTRAINT", "CHECK", "PRIMARY", "UNIQUE", "FOREIGN", "EXCLUDE", "LIKE", "KEY", "INDEX", "FULLTEXT", "SPATIAL":
+		t.addConstraint(element)
+		return
+	}
+	column := &sqlColumn{name: element[0].text}
+	column.define(element[1:], t)
+	t.columns[sqlKey(column.name)] = column
+}
+
+// addConstraint applies a table constraint: CHECK expressions and the NOT
+// NULL implied by PRIMARY KEY.
+func (t *sqlTable) addConstraint(element sqlTokens) {
+	for i := 0; i < len(element); i++ {
+		switch {
+		case element[i].is("CHECK") && i+1 < len(element) && element[i+1].is("("):
+			expr, end := element.group(i + 1)
+			t.applyCheck(expr, nil)
+			i = end - 1
+		case element[i].is("PRIMARY") && i+2 < len(element) && element[i+2].is("("):
+			columns, end := element.group(i + 2)
+			for _, name := range columns.splitTopLevel(",") {
+				if len(name) > 0 {
+					if column := t.columns[sqlKey(name[0].text)]; column != nil {
+						column.notNull = true
+					}
+				}
+			}
+			i = end - 1
+		}
+	}
+}
+
+// columnConstraintKeywords end the data type of a column definition.
+var columnConstraintKeywords = map[string]bool{
+	"NOT": true, "NULL": true, "DEFAULT": true, "CHECK": true, "PRIMARY": true, "UNIQUE": true,
+	"REFERENCES": true, "CONSTRAINT": true, "GENERATED": true, "COLLATE": true, "COMMENT": true,
+	"AUTO_INCREMENT": true, "AUTOINCREMENT": true, "ON": true, "IDENTITY": true,
+}
+
+// define reads a column's data type and constraints from the tokens that
+// follow its name.
+func (c *sqlColumn) define(def sqlTokens, table *sqlTable) {
+	var typeWords []string
+	i := 0
+	for ; i < len(def); i++ {
+		tok := def[i]
+		if tok.kind == sqlWord && columnConstraintKeywords[strings.ToUpper(tok.text)] {
+			break
+		}
+		switch {
+		case tok.is("("):
+			args, end := def.group(i)
+			if c.modifiers == nil {
+				for _, arg := range args.splitTopLevel(",") {
+					if len(arg) == 1 {
+						if n, err := strconv.Atoi(arg[0].text); err == nil {
+							c.modifiers = append(c.modifiers, n)
+						}
+					}
+				}
+			}
+			i = end - 1
+		case tok.kind == sqlWord:
+			typeWords = append(typeWords, strings.ToLower(tok.text))
+		}
+	}
+	c.dataType = canonicalSQLType(strings.Join(typeWords, " "))
+
+	for ; i < len(def); i++ {
+		switch {
+		case def[i:].keywords("NOT", "NULL"):
+			c.notNull = true
+			i++
+		case def[i:].keywords("PRIMARY", "KEY"):
+			c.notNull = true
+			i++
+		case def[i].is("CHECK") && i+1 < len(def) && def[i+1].is("("):
+			expr, end := def.group(i + 1)
+			if table != nil {
+				table.applyCheck(expr, c)
+			}
+			i = end - 1
+		case def[i].is("COMMENT") && i+1 < len(def) && def[i+1].kind == sqlString:
+			c.comment = def[i+1].text
+			i++
+		case def[i].is("("):
+			// Skip DEFAULT (...) and REFERENCES t (...) arguments.
+			_, end := def.group(i)
+			i = end - 1
+		}
+	}
+}
+
+// canonicalSQLType folds type aliases: character varying is varchar,
+// decimal is numeric.
+func canonicalSQLType(name string) string {
+	name = strings.TrimSuffix(strings.TrimSpace(name), " array")
+	switch name {
+	case "character varying", "varchar2", "nvarchar", "nvarchar2", "national character varying":
+		return "varchar"
+	case "character", "bpchar", "nchar", "national character":
+		return "char"
+	case "decimal", "dec":
+		return "numeric"
+	}
+	return name
+}
+
+func (s *sqlSchema) alterTable(stmt sqlTokens) {
+	i := 0
+	if stmt.keywords("IF", "EXISTS") {
+		i += 2
+	}
+	if i < len(stmt) && stmt[i].is("ONLY") {
+		i++
+	}
+	schema, name, i := stmt.qualifiedName(i)
+	table := s.lookup(schema, name)
+	if table == nil {
+		return
+	}
+
+	for _, action := range stmt[i:].splitTopLevel(",") {
+		switch {
+		case action.keywords("ADD", "CONSTRAINT"), action.keywords("ADD", "CHECK"),
+			action.keywords("ADD", "PRIMARY"):
+			table.addConstraint(action[1:])
+		case action.keywords("ADD"):
+			def := action[1:]
+			if def.keywords("COLUMN") {
+				def = def[1:]
+			}
+			if def.keywords("IF", "NOT", "EXISTS") {
+				def = def[3:]
+			}
+			table.addElement(def)
+		case action.keywords("DROP", "CONSTRAINT"):
+		case action.keywords("DROP"):
+			def := action[1:]
+			if def.keywords("COLUMN") {
+				def = def[1:]
+			}
+			if def.keywords("IF", "EXISTS") {
+				def = def[2:]
+			}
+			if len(def) > 0 {
+				delete(table.columns, sqlKey(def[0].text))
+			}
+		case action.keywords("ALTER"):
+			table.alterColumn(action[1:])
+		case action.keywords("RENAME", "TO"):
+			s.renameTable(table, action[2:])
+		case action.keywords("RENAME"):
+			def := action[1:]
+			if def.keywords("COLUMN") {
+				def = def[1:]
+			}
+			if len(def) == 3 && def[1].is("TO") {
+				if column := table.columns[sqlKey(def[0].text)]; column != nil {
+					delete(table.columns, sqlKey(def[0].text))
+					column.name = def[2].text
+					table.columns[sqlKey(column.name)] = column
+				}
+			}
+		}
+	}
+}
+
+// renameTable handles ALTER TABLE t RENAME TO u; def is the new name.
+func (s *sqlSchema) renameTable(table *sqlTable, def sqlTokens) {
+	_, name, _ := def.qualifiedName(0)
+	if name == "" {
+		return
+	}
+	delete(s.tables, tableKey(table.schema, table.name))
+	table.name = name
+	s.tables[sqlKey(table.schema)+"."+sqlKey(name)] = table
+}
+
+// alterColumn handles ALTER [COLUMN] c SET/DROP NOT NULL and TYPE changes.
+func (t *sqlTable) alterColumn(def sqlTokens) {
+	if def.keywords("COLUMN") {
+		def = def[1:]
+	}
+	if len(def) < 2 {
+		return
+	}
+	column := t.columns[sqlKey(def[0].text)]
+	if column == nil {
+		return
+	}
+	rest := def[1:]
+	switch {
+	case rest.keywords("SET", "NOT", "NULL"):
+		column.notNull = true
+	case rest.keywords("DROP", "NOT", "NULL"):
+		column.notNull = false
+	case rest.keywords("SET", "DATA", "TYPE"):
+		column.retype(rest[3:])
+	case rest.keywords("TYPE"):
+		column.retype(rest[1:])
+	}
+}
+
+func (c *sqlColumn) retype(def sqlTokens) {
+	for i, tok := range def {
+		if tok.is("USING") || tok.is("COLLATE") {
+			def = def[:i]
+			break
+		}
+	}
+	c.modifiers = nil
+	c.define(def, nil)
+}
+
+func (s *sqlSchema) comment(stmt sqlTokens) {
+	if len(stmt) < 2 {
+		return
+	}
+	target := strings.ToUpper(stmt[0].text)
+	var parts []string
+	i := 1
+	for ; i < len(stmt) && !stmt[i].is("IS"); i++ {
+		if stmt[i].kind == sqlWord || stmt[i].kind == sqlQuotedIdent {
+			parts = append(parts, stmt[i].text)
+		}
+	}
+	if i+1 >= len(stmt) {
+		return
+	}
+	text := ""
+	if stmt[i+1].kind == sqlString {
+		text = stmt[i+1].text
+	}
+
+	switch {
+	case target == "TABLE" && len(parts) >= 1:
+		schema := ""
+		if len(parts) > 1 {
+			schema = parts[len(parts)-2]
+		}
+		if table := s.lookup(schema, parts[len(parts)-1]); table != nil {
+			table.comment = text
+		}
+	case target == "COLUMN" && len(parts) >= 2:
+		schema := ""
+		if len(parts) > 2 {
+			schema = parts[len(parts)-3]
+		}
+		table := s.lookup(schema, parts[len(parts)-2])
+		if table == nil {
+			return
+		}
+		if column := table.columns[sqlKey(parts[len(parts)-1])]; column != nil {
+			column.comment = text
+		}
+	}
+}
+
+func (s *sqlSchema) dropTable(stmt sqlTokens) {
+	i := 0
+	if stmt.keywords("IF", "EXISTS") {
+		i += 2
+	}
+	for _, name := range stmt[i:].splitTopLevel(",") {
+		schema, table, _ := name.qualifiedName(0)
+		delete(s.tables, tableKey(schema, table))
+	}
+}
+
+// applyCheck reads enum values, bounds and length limits from a CHECK
+// expression. Conditions combined with AND apply to their own column;
+// column is the one an inline CHECK is declared on, if any.
+func (t *sqlTable) applyCheck(expr sqlTokens, column *sqlColumn) {
+	expr = expr.withoutCasts()
+	for _, cond := range expr.splitConjuncts() {
+		t.applyCondition(cond, column)
+	}
+}
+
+func (t *sqlTable) applyCondition(cond sqlTokens, inline *sqlColumn) {
+	name, length := cond.subject()
+	column := t.columns[sqlKey(name)]
+	if inline != nil && (name == "" || sqlKey(name) == sqlKey(inline.name)) {
+		// The column an inline CHECK belongs to is not in the table yet.
+		column = inline
+	}
+	if column == nil {
+		return
+	}
+
+	switch {
+	case cond.hasKeyword("IN") || cond.hasKeyword("ANY"):
+		if cond.hasKeyword("NOT") {
+			return
+		}
+		column.enum = cond.literals()
+	case cond.hasKeyword("OR"):
+		// col = 'a' OR col = 'b'
+		for _, alt := range cond.splitTopLevel("OR") {
+			if alt.has("=") && !alt.has("<") && !alt.has(">") {
+				column.enum = append(column.enum, alt.literals()...)
+			}
+		}
+	case cond.hasKeyword("BETWEEN"):
+		values := cond.numbers()
+		if len(values) == 2 && !length {
+			column.bounds = append(column.bounds, sqlBound{">=", values[0]}, sqlBound{"<=", values[1]})
+		}
+	default:
+		op, value, ok := cond.comparison()
+		if !ok {
+			return
+		}
+		if length {
+			limit := int(value)
+			switch op {
+			case "<":
+				limit--
+			case "<=":
+			default:
+				return
+			}
+			column.maxLength = &limit
+			return
+		}
+		column.bounds = append(column.bounds, sqlBound{op, value})
+	}
+}
+
+// sqlTokenKind classifies DDL tokens.
+type sqlTokenKind int
+
+const (
+	sqlWord sqlTokenKind = iota
+	sqlQuotedIdent
+	sqlString
+	sqlNumber
+	sqlPunct
+)
+
+type sqlToken struct {
+	kind sqlTokenKind
+	text string // unquoted for identifiers and strings
+}
+
+// is reports whether tok is the keyword or punctuation text.
+func (tok sqlToken) is(text string) bool {
+	return (tok.kind == sqlWord || tok.kind == sqlPunct) && strings.EqualFold(tok.text, text)
+}
+
+type sqlTokens []sqlToken
+
+// splitSQLStatements tokenizes src and splits it on semicolons. Comments
+// are dropped; dollar-quoted bodies (functions, DO blocks) become strings.
+func splitSQLStatements(src string) []sqlTokens {
+	var stmts []sqlTokens
+	var current sqlTokens
+	flush := func() {
+		if len(current) > 0 {
+			stmts = append(stmts, current)
+		}
+		current = nil
+	}
+
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.HasPrefix(src[i:], "--") || c == '#':
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src) - i
+			}
+			i += end
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				i = len(src)
+			} else {
+				i += end + 4
+			}
+		case c == ';':
+			flush()
+			i++
+		case c == '\'':
+			text, n := readSQLQuoted(src[i:], '\'')
+			current = append(current, sqlToken{kind: sqlString, text: text})
+			i += n
+		case c == '"' || c == '`':
+			text, n := readSQLQuoted(src[i:], c)
+			current = append(current, sqlToken{kind: sqlQuotedIdent, text: text})
+			i += n
+		case c == '$' && dollarTag(src[i:]) != "":
+			tag := dollarTag(src[i:])
+			end := strings.Index(src[i+len(tag):], tag)
+			if end < 0 {
+				end = len(src) - i - len(tag)
+			}
+			current = append(current, sqlToken{kind: sqlString, text: src[i+len(tag) : i+len(tag)+end]})
+			i += len(tag) + end + len(tag)
+			if i > len(src) {
+				i = len(src)
+			}
+		case isSQLWordByte(c):
+			j := i
+			for j < len(src) && (isSQLWordByte(src[j]) || src[j] == '$') {
+				j++
+			}
+			kind := sqlWord
+			if c >= '0' && c <= '9' {
+				kind = sqlNumber
+				for j < len(src) && (src[j] == '.' || (src[j] >= '0' && src[j] <= '9')) {
+					j++
+				}
+			}
+			current = append(current, sqlToken{kind: kind, text: src[i:j]})
+			i = j
+		default:
+			// Operators keep two-character forms together.
+			n := 1
+			if i+1 < len(src) {
+				switch src[i : i+2] {
+				case "::", ">=", "<=", "<>", "!=":
+					n = 2
+				}
+			}
+			current = append(current, sqlToken{kind: sqlPunct, text: src[i : i+n]})
+			i += n
+		}
+	}
+	flush()
+	return stmts
+}
+
+// readSQLQuoted reads a quoted string or identifier whose quote is escaped
+// by doubling, returning its text and length in src.
+func readSQLQuoted(src string, quote byte) (string, int) {
+	var b strings.Builder
+	for i := 1; i < len(src); i++ {
+		if src[i] != quote {
+			b.WriteByte(src[i])
+			continue
+		}
+		if i+1 < len(src) && src[i+1] == quote {
+			b.WriteByte(quote)
+			i++
+			continue
+		}
+		return b.String(), i + 1
+	}
+	return b.String(), len(src)
+}
+
+// dollarTag returns the $tag$ opening a dollar-quoted string, or "".
+func dollarTag(src string) string {
+	end := strings.IndexByte(src[1:], '$')
+	if end < 0 {
+		return ""
+	}
+	tag := src[:end+2]
+	for i := 1; i < len(tag)-1; i++ {
+		if !isSQLWordByte(tag[i]) {
+			return ""
+		}
+	}
+	return tag
+}
+
+func isSQLWordByte(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c >= 0x80
+}
+
+// keywords reports whether ts starts with the given keywords.
+func (ts sqlTokens) keywords(words ...string) bool {
+	if len(ts) < len(words) {
+		return false
+	}
+	for i, w := range words {
+		if ts[i].kind != sqlWord || !strings.EqualFold(ts[i].text, w) {
+			return false
+		}
+	}
+	return true
+}
+
+// qualifiedName reads schema.name (or name) starting at i and returns the
+// index after it.
+func (ts sqlTokens) qualifiedName(i int) (schema, name string, next int) {
+	var parts []string
+	for i < len(ts) && (ts[i].kind == sqlWord || ts[i].kind == sqlQuotedIdent) {
+		parts = append(parts, ts[i].text)
+		i++
+		if i < len(ts) && ts[i].is(".") {
+			i++
+			continue
+		}
+		break
+	}
+	switch len(parts) {
+	case 0:
+		return "", "", i
+	case 1:
+		return "", parts[0], i
+	}
+	schema = parts[len(parts)-2]
+	if strings.EqualFold(schema, "public") || strings.EqualFold(schema, "dbo") {
+		// sqlc does not prefix models of the default schema.
+		schema = ""
+	}
+	return schema, parts[len(parts)-1], i
+}
+
+// group returns the tokens inside the parenthesis opening at i and the
+// index after its closing parenthesis.
+func (ts sqlTokens) group(i int) (sqlTokens, int) {
+	depth := 0
+	for j := i; j < len(ts); j++ {
+		switch {
+		case ts[j].is("("):
+			depth++
+		case ts[j].is(")"):
+			depth--
+			if depth == 0 {
+				return ts[i+1 : j], j + 1
+			}
+		}
+	}
+	return ts[i+1:], len(ts)
+}
+
+// splitTopLevel splits ts on sep outside parentheses and brackets.
+func (ts sqlTokens) splitTopLevel(sep string) []sqlTokens {
+	var parts []sqlTokens
+	depth, start := 0, 0
+	for i, tok := range ts {
+		switch {
+		case tok.is("(") || tok.is("["):
+			depth++
+		case tok.is(")") || tok.is("]"):
+			depth--
+		case depth == 0 && tok.is(sep):
+			parts = append(parts, ts[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, ts[start:])
+}
+
+// splitConjuncts splits a condition on top-level AND, keeping the AND of
+// BETWEEN x AND y, after removing parentheses that wrap the whole of it.
+func (ts sqlTokens) splitConjuncts() []sqlTokens {
+	ts = ts.unwrap()
+	var parts []sqlTokens
+	depth, start, between := 0, 0, false
+	for i, tok := range ts {
+		switch {
+		case tok.is("(") || tok.is("["):
+			depth++
+		case tok.is(")") || tok.is("]"):
+			depth--
+		case depth == 0 && tok.is("BETWEEN"):
+			between = true
+		case depth == 0 && tok.is("AND"):
+			if between {
+				between = false
+				continue
+			}
+			parts = append(parts, ts[start:i].unwrap())
+			start = i + 1
+		}
+	}
+	parts = append(parts, ts[start:].unwrap())
+
+	// Nested conjunctions such as ((a > 0) AND (b > 0)).
+	var out []sqlTokens
+	for _, part := range parts {
+		if len(part) < len(ts) && part.hasKeyword("AND") && !part.hasKeyword("BETWEEN") {
+			out = append(out, part.splitConjuncts()...)
+			continue
+		}
+		out = append(out, part)
+	}
+	return out
+}
+
+// unwrap removes parentheses enclosing all of ts.
+func (ts sqlTokens) unwrap() sqlTokens {
+	for len(ts) >= 2 && ts[0].is("(") {
+		inner, end := ts.group(0)
+		if end != len(ts) {
+			break
+		}
+		ts = inner
+	}
+	return ts
+}
+
+// withoutCasts drops ::type casts, including array and multi-word types.
+func (ts sqlTokens) withoutCasts() sqlTokens {
+	out := make(sqlTokens, 0, len(ts))
+	for i := 0; i < len(ts); i++ {
+		if !ts[i].is("::") {
+			out = append(out, ts[i])
+			continue
+		}
+		for i+1 < len(ts) && (ts[i+1].kind == sqlWord || ts[i+1].kind == sqlQuotedIdent) &&
+			!ts[i+1].is("AND") && !ts[i+1].is("OR") {
+			i++
+			if i+1 < len(ts) && ts[i+1].is("(") {
+				_, end := ts.group(i + 1)
+				i = end - 1
+			}
+		}
+		for i+2 < len(ts) && ts[i+1].is("[") && ts[i+2].is("]") {
+			i += 2
+		}
+	}
+	return out
+}
+
+func (ts sqlTokens) hasKeyword(word string) bool {
+	depth := 0
+	for _, tok := range ts {
+		switch {
+		case tok.is("("):
+			depth++
+		case tok.is(")"):
+			depth--
+		case depth == 0 && tok.kind == sqlWord && strings.EqualFold(tok.text, word):
+			return true
+		}
+	}
+	return false
+}
+
+func (ts sqlTokens) has(punct string) bool {
+	for _, tok := range ts {
+		if tok.kind == sqlPunct && tok.text == punct {
+			return true
+		}
+	}
+	return false
+}
+
+// sqlConditionKeywords are words of a CHECK condition that never name the
+// column it constrains.
+var sqlConditionKeywords = map[string]bool{
+	"IN": true, "NOT": true, "ANY": true, "ARRAY": true, "AND": true, "OR": true, "BETWEEN": true,
+	"IS": true, "NULL": true, "TRUE": true, "FALSE": true, "VALUE": true,
+}
+
+// sqlLengthFuncs measure a string column in characters.
+var sqlLengthFuncs = map[string]bool{"LENGTH": true, "CHAR_LENGTH": true, "CHARACTER_LENGTH": true}
+
+// subject returns the column a condition constrains and whether it is
+// measured through a length function.
+func (ts sqlTokens) subject() (name string, length bool) {
+	for i, tok := range ts {
+		switch tok.kind {
+		case sqlQuotedIdent:
+			return tok.text, length
+		case sqlWord:
+			upper := strings.ToUpper(tok.text)
+			if sqlLengthFuncs[upper] {
+				length = true
+				continue
+			}
+			if sqlConditionKeywords[upper] || (i+1 < len(ts) && ts[i+1].is("(")) {
+				continue
+			}
+			return tok.text, length
+		}
+	}
+	return "", length
+}
+
+// literals returns the string and number constants of ts.
+func (ts sqlTokens) literals() []string {
+	var out []string
+	for i, tok := range ts {
+		switch tok.kind {
+		case sqlString:
+			out = append(out, tok.text)
+		case sqlNumber:
+			text := tok.text
+			if i > 0 && ts[i-1].is("-") {
+				text = "-" + text
+			}
+			out = append(out, text)
+		}
+	}
+	return out
+}
+
+// numbers returns the numeric constants of ts.
+func (ts sqlTokens) numbers() []float64 {
+	var out []float64
+	for _, lit := range ts.literals() {
+		if v, err := strconv.ParseFloat(lit, 64); err == nil {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// comparison reads "subject op number" or "number op subject", returning
+// the operator as seen from the subject.
+func (ts sqlTokens) comparison() (op string, value float64, ok bool) {
+	flip := map[string]string{">=": "<=", ">": "<", "<=": ">=", "<": ">"}
+	for i, tok := range ts {
+		if tok.kind != sqlPunct || flip[tok.text] == "" {
+			continue
+		}
+		right := ts[i+1:].numbers()
+		left := ts[:i].numbers()
+		switch {
+		case len(right) == 1 && len(left) == 0:
+			return tok.text, right[0], true
+		case len(left) == 1 && len(right) == 0:
+			return flip[tok.text], left[0], true
+		}
+		return "", 0, false
+	}
+	return "", 0, false
+}
@@ -0,0 +1,246 @@
+package annot8
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+var versionSegmentRegexp = regexp.MustCompile(`^v\d+(\.\d+)*$`)
+
+// TagContext describes the operation a TagStrategy tags.
+type TagContext struct {
+	Method string
+	Route  string // chi route pattern
+	// Mount is the prefix of the innermost r.Route or r.Mount subrouter the
+	// route belongs to, empty for routes on the root router.
+	Mount string
+	// Handler is the handler's runtime function name, such as
+	// "github.com/acme/api/orders.(*OrderHandler).List-fm".
+	Handler string
+}
+
+// TagStrategy tags operations without @Tags. Returning no tags falls back
+// to TagByRoute.
+type TagStrategy func(ctx TagContext) []string
+
+// TagByRoute tags an operation with the first route segment that is not
+// "api", a version (v2, v1.1), a parameter or a wildcard. It is the default.
+func TagByRoute(ctx TagContext) []string {
+	return []string{extractResourceFromRoute(ctx.Route)}
+}
+
+// TagByMount tags an operation with the last meaningful segment of the
+// r.Route or r.Mount prefix it was registered under, so every route of a
+// subrouter shares one tag.
+func TagByMount(ctx TagContext) []string {
+	segments := resourceSegments(ctx.Mount)
+	if len(segments) == 0 {
+		return nil
+	}
+	return []string{segments[len(segments)-1]}
+}
+
+// TagByPackage tags an operation with the last element of its handler's
+// import path.
+func TagByPackage(ctx TagContext) []string {
+	name := strings.TrimSuffix(ctx.Handler, "-fm")
+	name = name[strings.LastIndex(name, "/")+1:]
+	pkg, _, ok := strings.Cut(name, ".")
+	if !ok || pkg == "" {
+		return nil
+	}
+	return []string{pkg}
+}
+
+// TagByReceiver tags an operation with its handler's receiver type, without
+// a Handler or Controller suffix and pluralized: OrderHandler.List is tagged
+// Orders. Handlers that are not methods fall back to TagByRoute.
+func TagByReceiver(ctx TagContext) []string {
+	recv, _, ok := extractReceiverAndMethod(ctx.Handler)
+	if !ok {
+		return nil
+	}
+	for _, suffix := range []string{"Handlers", "Handler", "Controller"} {
+		if trimmed := strings.TrimSuffix(recv, suffix); trimmed != "" {
+			recv = trimmed
+		}
+	}
+	return []string{pluralize(capitalize(recv))}
+}
+
+// SetTagStrategy selects how operations without @Tags are tagged. The
+// default is TagByRoute.
+func (g *Generator) SetTagStrategy(strategy TagStrategy) {
+	g.tagStrategy = strategy
+}
+
+// operationTags returns the tags the strategy gives an operation without @Tags.
+func (g *Generator) operationTags(ctx TagContext) []string {
+	if g.tagStrategy != nil {
+		if tags := slices.DeleteFunc(g.tagStrategy(ctx), func(tag string) bool { return tag == "" }); len(tags) > 0 {
+			return tags
+		}
+	}
+	return TagByRoute(ctx)
+}
+
+// TagGroup is an x-tagGroups entry, which Redoc and other viewers render as
+// a section nesting its tags.
+type TagGroup struct {
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+// TagGroups configures the x-tagGroups extension.
+type TagGroups struct {
+	// Groups lists groups and their tags in display order. Tags no operation
+	// uses are dropped, as are groups left empty.
+	Groups []TagGroup
+	// Assign returns the group of a tag Groups does not list, given an
+	// operation carrying it; empty leaves the tag ungrouped. Groups it
+	// introduces follow Groups, sorted by name.
+	Assign func(ctx TagContext, tag string) string
+	// Ungrouped names a last group collecting the remaining tags. Viewers
+	// hide tags outside every group, so leave it empty only when Groups and
+	// Assign cover all of them.
+	Ungrouped string
+}
+
+// SetTagGroups emits x-tagGroups grouping the spec's tags. A zero TagGroups
+// emits none, the default.
+func (g *Generator) SetTagGroups(groups TagGroups) {
+	g.tagGroups = groups
+}
+
+// tagGrouper collects the group membership of tags as operations are built.
+type tagGrouper struct {
+	cfg      TagGroups
+	listed   map[string]bool
+	assigned map[string]string
+}
+
+func newTagGrouper(cfg TagGroups) *tagGrouper {
+	t := &tagGrouper{cfg: cfg, listed: make(map[string]bool), assigned: make(map[string]string)}
+	for _, group := range cfg.Groups {
+		for _, tag := range group.Tags {
+			t.listed[tag] = true
+		}
+	}
+	return t
+}
+
+// observe records the groups Assign gives the tags of an operation. The
+// first operation to assign a tag decides its group.
+func (t *tagGrouper) observe(ctx TagContext, tags []string) {
+	if t.cfg.Assign == nil {
+		return
+	}
+	for _, tag := range tags {
+		if _, seen := t.assigned[tag]; seen || t.listed[tag] {
+			continue
+		}
+		t.assigned[tag] = t.cfg.Assign(ctx, tag)
+	}
+}
+
+// groups returns the x-tagGroups entries for the spec's tags, or nil when
+// grouping is not configured.
+func (t *tagGrouper) groups(tags []Tag) []TagGroup {
+	if len(t.cfg.Groups) == 0 && t.cfg.Assign == nil && t.cfg.Ungrouped == "" {
+		return nil
+	}
+
+	used := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		used[tag.Name] = true
+	}
+	grouped := make(map[string]bool)
+
+	var result []TagGroup
+	add := func(name string, members []string) {
+		var kept []string
+		for _, tag := range members {
+			if used[tag] && !slices.Contains(kept, tag) {
+				kept = append(kept, tag)
+				grouped[tag] = true
+			}
+		}
+		if len(kept) == 0 {
+			return
+		}
+		if i := slices.IndexFunc(result, func(g TagGroup) bool { return g.Name == name }); i >= 0 {
+			result[i].Tags = append(result[i].Tags, kept...)
+			return
+		}
+		result = append(result, TagGroup{Name: name, Tags: kept})
+	}
+
+	for _, group := range t.cfg.Groups {
+		add(group.Name, group.Tags)
+	}
+
+	byGroup := make(map[string][]string)
+	for tag, group := range t.assigned {
+		if group != "" {
+			byGroup[group] = append(byGroup[group], tag)
+		}
+	}
+	names := make([]string, 0, len(byGroup))
+	for name := range byGroup {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		members := byGroup[name]
+		sort.Strings(members)
+		add(name, members)
+	}
+
+	if t.cfg.Ungrouped != "" {
+		var rest []string
+		for _, tag := range tags {
+			if !grouped[tag.Name] {
+				rest = append(rest, tag.Name)
+			}
+		}
+		add(t.cfg.Ungrouped, rest)
+	}
+	return result
+}
+
+// resourceSegments returns the segments of route that name resources,
+// skipping "api", version segments, parameters and wildcards.
+func resourceSegments(route string) []string {
+	var segments []string
+	for _, part := range strings.Split(strings.Trim(route, "/"), "/") {
+		if part == "" || part == "api" || versionSegmentRegexp.MatchString(part) ||
+			strings.Contains(part, "{") || strings.Contains(part, "*") {
+			continue
+		}
+		segments = append(segments, part)
+	}
+	return segments
+}
+
+// pluralize appends an English plural suffix to s.
+func pluralize(s string) string {
+	lower := strings.ToLower(s)
+	switch {
+	case lower == "":
+		return s
+	case strings.HasSuffix(lower, "ss"), strings.HasSuffix(lower, "x"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		return s + "es"
+	case strings.HasSuffix(lower, "s"):
+		return s
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && !isVowel(lower[len(lower)-2]):
+		return s[:len(s)-1] + "ies"
+	}
+	return s + "s"
+}
+
+func isVowel(b byte) bool {
+	return strings.IndexByte("aeiou", b) >= 0
+}
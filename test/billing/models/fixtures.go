@@ -0,0 +1,14 @@
+package models
+
+// Item is an invoice line. It shares its package and type name with
+// catalog/models.Item so tests can exercise import-path disambiguation.
+type Item struct {
+	InvoiceID int64 `json:"invoice_id"`
+	Amount    int64 `json:"amount"`
+}
+
+// Invoice is only declared here, so it keeps a short component name.
+type Invoice struct {
+	Number string `json:"number"`
+	Lines  []Item `json:"lines"`
+}
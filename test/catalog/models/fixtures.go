@@ -0,0 +1,8 @@
+package models
+
+// Item is a catalog entry. It shares its package and type name with
+// billing/models.Item so tests can exercise import-path disambiguation.
+type Item struct {
+	SKU   string `json:"sku"`
+	Title string `json:"title"`
+}
@@ -0,0 +1,112 @@
+package annot8fixtures_test
+
+import (
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/AxelTahmid/annot8"
+)
+
+func closedSchemaGenerator(mode annot8.EmbeddedStructMode) *annot8.SchemaGenerator {
+	sg := NewTestSchemaGenerator()
+	sg.SetClosedObjects(true)
+	sg.SetEmbeddedStructMode(mode)
+	return sg
+}
+
+func TestClosedObjects_GeneratorOption(t *testing.T) {
+	t.Parallel()
+
+	sg := closedSchemaGenerator(annot8.EmbedFlatten)
+	_ = sg.GenerateSchema("annot8fixtures.ClosedAccount")
+	AssertEqual(t, false, sg.GetSchemas()["annot8fixtures.ClosedAccount"].AdditionalProperties)
+
+	open := NewTestSchemaGenerator()
+	_ = open.GenerateSchema("annot8fixtures.ClosedAccount")
+	AssertEqual(t, nil, open.GetSchemas()["annot8fixtures.ClosedAccount"].AdditionalProperties)
+}
+
+func TestClosedObjects_TypeDirectives(t *testing.T) {
+	t.Parallel()
+
+	sg := closedSchemaGenerator(annot8.EmbedFlatten)
+	_ = sg.GenerateSchema("annot8fixtures.ClosedSettings")
+	AssertEqual(t, nil, sg.GetSchemas()["annot8fixtures.ClosedSettings"].AdditionalProperties)
+
+	plain := NewTestSchemaGenerator()
+	_ = plain.GenerateSchema("annot8fixtures.ClosedStrict")
+	AssertEqual(t, false, plain.GetSchemas()["annot8fixtures.ClosedStrict"].AdditionalProperties)
+}
+
+func TestClosedObjects_FlattenedEmbed(t *testing.T) {
+	t.Parallel()
+
+	sg := closedSchemaGenerator(annot8.EmbedFlatten)
+	_ = sg.GenerateSchema("annot8fixtures.ClosedMember")
+	member := sg.GetSchemas()["annot8fixtures.ClosedMember"]
+
+	AssertDeepEqual(t, []string{"id", "name"}, schemaKeys(member.Properties))
+	AssertEqual(t, false, member.AdditionalProperties)
+}
+
+func TestClosedObjects_ComposedEmbed(t *testing.T) {
+	t.Parallel()
+
+	sg := closedSchemaGenerator(annot8.EmbedAllOf)
+	_ = sg.GenerateSchema("annot8fixtures.ClosedMember")
+	schemas := sg.GetSchemas()
+	member := schemas["annot8fixtures.ClosedMember"]
+
+	AssertEqual(t, false, member.UnevaluatedProperties)
+	AssertEqual(t, nil, member.AdditionalProperties)
+	if len(member.AllOf) != 2 {
+		t.Fatalf("expected base and local allOf members, got %+v", member.AllOf)
+	}
+	AssertEqual(t, "#/components/schemas/annot8fixtures.ClosedBase@Open", member.AllOf[0].Ref)
+	AssertEqual(t, nil, member.AllOf[1].AdditionalProperties)
+
+	// The base stays closed where it is used on its own.
+	AssertEqual(t, false, schemas["annot8fixtures.ClosedBase"].AdditionalProperties)
+	open := schemas["annot8fixtures.ClosedBase@Open"]
+	AssertEqual(t, nil, open.AdditionalProperties)
+	AssertDeepEqual(t, []string{"id"}, schemaKeys(open.Properties))
+}
+
+func TestClosedObjects_FieldOverrides(t *testing.T) {
+	t.Parallel()
+
+	sg := NewTestSchemaGenerator()
+	_ = sg.GenerateSchema("annot8fixtures.ClosedHolder")
+	_ = sg.GenerateSchema("annot8fixtures.ClosedStrict")
+	props := sg.GetSchemas()["annot8fixtures.ClosedHolder"].Properties
+
+	// Not closed, so closed=false has nothing to open.
+	AssertEqual(t, "#/components/schemas/annot8fixtures.ClosedAccount", props["account"].Ref)
+
+	settings := props["settings"].AnyOf[0]
+	AssertEqual(t, "#/components/schemas/annot8fixtures.ClosedSettings", settings.Ref)
+	AssertEqual(t, false, settings.UnevaluatedProperties)
+	AssertEqual(t, nil, props["settings"].AnyOf[1].UnevaluatedProperties)
+
+	AssertEqual(t, false, props["history"].Items.UnevaluatedProperties)
+
+	closed := closedSchemaGenerator(annot8.EmbedFlatten)
+	_ = closed.GenerateSchema("annot8fixtures.ClosedHolder")
+	account := closed.GetSchemas()["annot8fixtures.ClosedHolder"].Properties["account"]
+	AssertEqual(t, "#/components/schemas/annot8fixtures.ClosedAccount@Open", account.Ref)
+}
+
+func TestClosedObjects_OpenVariantName(t *testing.T) {
+	g := NewTestGenerator()
+	g.SetClosedObjects(true)
+	g.SetEmbeddedStructMode(annot8.EmbedAllOf)
+	g.GenerateSchema("annot8fixtures.ClosedMember")
+	spec := g.GenerateSpec(chi.NewRouter(), annot8.Config{Title: "Closed", Version: "1.0.0"})
+
+	member := spec.Components.Schemas["annot8fixtures.ClosedMember"]
+	AssertEqual(t, "#/components/schemas/annot8fixtures.ClosedBaseOpen", member.AllOf[0].Ref)
+	if _, ok := spec.Components.Schemas["annot8fixtures.ClosedBaseOpen"]; !ok {
+		t.Fatalf("expected the open variant component, got %v", schemaKeys(spec.Components.Schemas))
+	}
+}
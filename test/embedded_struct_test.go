@@ -0,0 +1,102 @@
+package annot8fixtures_test
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/AxelTahmid/annot8"
+	fixtures "github.com/AxelTahmid/annot8/test"
+)
+
+// marshaledKeys returns the sorted object keys encoding/json produces for v.
+func marshaledKeys(t *testing.T, v any) []string {
+	t.Helper()
+	raw, err := json.Marshal(v)
+	AssertNoError(t, err)
+	var obj map[string]json.RawMessage
+	AssertNoError(t, json.Unmarshal(raw, &obj))
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestEmbeddedStructs_FlattenMatchesEncodingJSON(t *testing.T) {
+	tests := []struct {
+		typeName string
+		value    any
+		required []string
+	}{
+		{"annot8fixtures.EmbedPromoted", fixtures.EmbedPromoted{}, []string{"id", "created_at", "title"}},
+		{"annot8fixtures.EmbedShadow", fixtures.EmbedShadow{}, []string{"id", "created_at"}},
+		{"annot8fixtures.EmbedDeep", fixtures.EmbedDeep{}, []string{"id", "created_at", "title"}},
+		{"annot8fixtures.EmbedConflict", fixtures.EmbedConflict{}, []string{"left", "right"}},
+		{"annot8fixtures.EmbedTaggedWins", fixtures.EmbedTaggedWins{}, []string{"Name"}},
+		{"annot8fixtures.EmbedNamed", fixtures.EmbedNamed{}, []string{"base", "title"}},
+		{"annot8fixtures.EmbedPointer", fixtures.EmbedPointer{EmbedBase: &fixtures.EmbedBase{}}, []string{"title"}},
+		{"annot8fixtures.EmbedUnexported", fixtures.EmbedUnexported{}, []string{"secret", "title"}},
+		{"annot8fixtures.EmbedWithCode", fixtures.EmbedWithCode{}, []string{"EmbedCode", "title"}},
+		{"annot8fixtures.EmbedForeign", fixtures.EmbedForeign{}, []string{"number", "lines", "paid"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typeName, func(t *testing.T) {
+			gen := NewTestSchemaGenerator()
+			gen.GenerateSchema(tt.typeName)
+			schema, ok := gen.GetSchemas()[tt.typeName]
+			if !ok {
+				t.Fatalf("expected schema %s", tt.typeName)
+			}
+			if len(schema.AllOf) != 0 {
+				t.Fatalf("expected flattened object, got allOf %#v", schema.AllOf)
+			}
+
+			AssertDeepEqual(t, marshaledKeys(t, tt.value), schemaKeys(schema.Properties))
+
+			required := append([]string(nil), schema.Required...)
+			sort.Strings(required)
+			expected := append([]string(nil), tt.required...)
+			sort.Strings(expected)
+			AssertDeepEqual(t, expected, required)
+		})
+	}
+}
+
+func TestEmbeddedStructs_FieldTypes(t *testing.T) {
+	gen := NewTestSchemaGenerator()
+	gen.GenerateSchema("annot8fixtures.EmbedShadow")
+	gen.GenerateSchema("annot8fixtures.EmbedTaggedWins")
+	gen.GenerateSchema("annot8fixtures.EmbedNamed")
+	schemas := gen.GetSchemas()
+
+	AssertEqual(t, "string", schemas["annot8fixtures.EmbedShadow"].Properties["id"].Type.(string))
+	AssertEqual(t, "integer", schemas["annot8fixtures.EmbedTaggedWins"].Properties["Name"].Type.(string))
+	AssertEqual(t, "#/components/schemas/annot8fixtures.EmbedBase", schemas["annot8fixtures.EmbedNamed"].Properties["base"].Ref)
+}
+
+func TestEmbeddedStructs_AllOfOptIn(t *testing.T) {
+	gen := NewTestSchemaGenerator()
+	gen.SetEmbeddedStructMode(annot8.EmbedAllOf)
+	gen.GenerateSchema("annot8fixtures.EmbedPromoted")
+	gen.GenerateSchema("annot8fixtures.EmbedNamed")
+	schemas := gen.GetSchemas()
+
+	promoted := schemas["annot8fixtures.EmbedPromoted"]
+	if len(promoted.AllOf) != 2 {
+		t.Fatalf("expected allOf with embedded ref and local object, got %#v", promoted)
+	}
+	AssertEqual(t, "#/components/schemas/annot8fixtures.EmbedBase", promoted.AllOf[0].Ref)
+	if _, ok := promoted.AllOf[1].Properties["title"]; !ok {
+		t.Errorf("expected local title property in allOf, got %#v", promoted.AllOf[1])
+	}
+
+	// A tagged embed is a named member in either mode.
+	named := schemas["annot8fixtures.EmbedNamed"]
+	if len(named.AllOf) != 0 {
+		t.Fatalf("tagged embed should not produce allOf, got %#v", named.AllOf)
+	}
+	AssertEqual(t, "#/components/schemas/annot8fixtures.EmbedBase", named.Properties["base"].Ref)
+}
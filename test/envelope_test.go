@@ -0,0 +1,105 @@
+package annot8fixtures_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/AxelTahmid/annot8"
+	fixtures "github.com/AxelTahmid/annot8/test"
+)
+
+func envelopeSpec(configure func(g *annot8.Generator)) annot8.Spec {
+	r := chi.NewRouter()
+	stub := http.NotFoundHandler()
+	r.Method(http.MethodGet, "/items", annot8.Describe(stub, annot8.OperationDoc{
+		Responses: []annot8.ResponseDoc{{Status: http.StatusOK, Body: []fixtures.TestSimple{}, Wrapped: true}},
+	}))
+	r.Method(http.MethodGet, "/items/{id}", annot8.Describe(stub, annot8.OperationDoc{
+		Responses: []annot8.ResponseDoc{{Status: http.StatusOK, Body: fixtures.TestSimple{}, Wrapped: true}},
+	}))
+
+	g := NewTestGenerator()
+	if configure != nil {
+		configure(g)
+	}
+	return g.GenerateSpec(r, annot8.Config{Title: "Envelopes", Version: "1.0.0"})
+}
+
+func envelopeOf(t *testing.T, spec annot8.Spec, path string) *annot8.Schema {
+	t.Helper()
+	op := spec.Paths[path].Get
+	if op == nil {
+		t.Fatalf("expected GET %s, got %v", path, schemaKeys(spec.Paths))
+	}
+	return op.Responses["200"].Content["application/json"].Schema
+}
+
+func TestEnvelope_Default(t *testing.T) {
+	spec := envelopeSpec(nil)
+
+	list := envelopeOf(t, spec, "/items")
+	AssertDeepEqual(t, []string{"data", "message", "meta"}, schemaKeys(list.Properties))
+	AssertDeepEqual(t, []string{"message"}, list.Required)
+	AssertEqual(t, "#/components/schemas/PaginationMeta", list.Properties["meta"].Ref)
+
+	item := envelopeOf(t, spec, "/items/{id}")
+	AssertDeepEqual(t, []string{"data", "message"}, schemaKeys(item.Properties))
+
+	for _, name := range []string{"PaginationMeta", "ProblemDetails"} {
+		if _, ok := spec.Components.Schemas[name]; !ok {
+			t.Errorf("expected standard schema %s", name)
+		}
+	}
+}
+
+func TestEnvelope_GoType(t *testing.T) {
+	spec := envelopeSpec(func(g *annot8.Generator) {
+		g.SetEnvelopeConfig(annot8.EnvelopeConfig{
+			Type:      "github.com/AxelTahmid/annot8/test/httpx.Response",
+			DataField: "result",
+			MetaField: "meta",
+			Paginate:  annot8.PaginateSlices,
+		})
+	})
+
+	list := envelopeOf(t, spec, "/items")
+	AssertDeepEqual(t, []string{"meta", "ok", "result"}, schemaKeys(list.Properties))
+	result := list.Properties["result"]
+	if result.Items == nil || !strings.HasSuffix(result.Items.Ref, "TestSimple") {
+		t.Fatalf("expected result to hold TestSimple items, got %+v", result)
+	}
+	AssertDeepEqual(t, []string{"ok", "result"}, list.Required)
+	if meta := list.Properties["meta"]; len(meta.AnyOf) != 2 || meta.AnyOf[0].Ref != "#/components/schemas/httpx.Meta" {
+		t.Fatalf("expected the envelope type's nullable meta, got %+v", meta)
+	}
+
+	item := envelopeOf(t, spec, "/items/{id}")
+	AssertDeepEqual(t, []string{"ok", "result"}, schemaKeys(item.Properties))
+	if !strings.HasSuffix(item.Properties["result"].Ref, "TestSimple") {
+		t.Fatalf("expected result to reference TestSimple, got %+v", item.Properties["result"])
+	}
+}
+
+func TestEnvelope_StandardSchemasReplaceable(t *testing.T) {
+	spec := envelopeSpec(func(g *annot8.Generator) {
+		g.SetStandardSchemas(map[string]annot8.Schema{
+			"Problem": {Type: "object", Properties: map[string]*annot8.Schema{"code": {Type: "string"}}},
+		})
+		cfg := annot8.DefaultEnvelopeConfig()
+		cfg.Paginate = nil
+		g.SetEnvelopeConfig(cfg)
+	})
+
+	for _, name := range []string{"PaginationMeta", "ProblemDetails"} {
+		if _, ok := spec.Components.Schemas[name]; ok {
+			t.Errorf("expected %s to be replaced", name)
+		}
+	}
+	if _, ok := spec.Components.Schemas["Problem"]; !ok {
+		t.Fatalf("expected the replacement schema, got %v", schemaKeys(spec.Components.Schemas))
+	}
+	AssertDeepEqual(t, []string{"data", "message"}, schemaKeys(envelopeOf(t, spec, "/items").Properties))
+}
@@ -0,0 +1,115 @@
+package annot8fixtures_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/AxelTahmid/annot8"
+	fixtures "github.com/AxelTahmid/annot8/test"
+)
+
+func errorPolicySpec(policy *annot8.ErrorResponsePolicy) annot8.Spec {
+	r := chi.NewRouter()
+	stub := http.NotFoundHandler()
+	r.Method(http.MethodGet, "/items", stub)
+	r.Method(http.MethodGet, "/items/{id}", stub)
+	r.Method(http.MethodPost, "/items", annot8.Describe(stub, annot8.OperationDoc{
+		Body:      fixtures.TestSimple{},
+		Security:  []string{"BearerAuth"},
+		Responses: []annot8.ResponseDoc{{Status: http.StatusCreated}},
+	}))
+	r.With(annot8.RateLimitMiddleware(middleware.Throttle(10))).Method(http.MethodGet, "/search", stub)
+	r.With(middleware.Throttle(10)).Method(http.MethodGet, "/export", stub)
+
+	g := NewTestGenerator()
+	if policy != nil {
+		g.SetErrorResponsePolicy(*policy)
+	}
+	return g.GenerateSpec(r, annot8.Config{Title: "Errors", Version: "1.0.0"})
+}
+
+func TestErrorResponsePolicy_Default(t *testing.T) {
+	spec := errorPolicySpec(nil)
+
+	tests := []struct {
+		path, method string
+		op           *annot8.Operation
+		want         []string
+	}{
+		{"/items", "GET", spec.Paths["/items"].Get, []string{"200", "500"}},
+		{"/items/{id}", "GET", spec.Paths["/items/{id}"].Get, []string{"200", "400", "404", "500"}},
+		{"/items", "POST", spec.Paths["/items"].Post, []string{"201", "400", "401", "403", "500"}},
+		{"/search", "GET", spec.Paths["/search"].Get, []string{"200", "429", "500"}},
+		// Rate limiters are identified by value, not by name.
+		{"/export", "GET", spec.Paths["/export"].Get, []string{"200", "500"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			if tt.op == nil {
+				t.Fatalf("expected %s %s", tt.method, tt.path)
+			}
+			AssertDeepEqual(t, tt.want, schemaKeys(tt.op.Responses))
+		})
+	}
+
+	notFound := spec.Paths["/items/{id}"].Get.Responses["404"]
+	AssertEqual(t, "Not Found", notFound.Description)
+	AssertEqual(t, "#/components/schemas/ProblemDetails", notFound.Content["application/problem+json"].Schema.Ref)
+}
+
+func TestErrorResponsePolicy_RateLimiters(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy func(p *annot8.ErrorResponsePolicy)
+	}{
+		{"constructor", func(p *annot8.ErrorResponsePolicy) { p.RateLimiters = []any{middleware.ThrottleWithOpts} }},
+		{"name substring", func(p *annot8.ErrorResponsePolicy) { p.RateLimitContains = []string{"Throttle"} }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := annot8.DefaultErrorResponsePolicy()
+			tt.policy(&policy)
+			spec := errorPolicySpec(&policy)
+			AssertDeepEqual(t, []string{"200", "429", "500"}, schemaKeys(spec.Paths["/export"].Get.Responses))
+			AssertDeepEqual(t, []string{"200", "500"}, schemaKeys(spec.Paths["/items"].Get.Responses))
+		})
+	}
+}
+
+func TestErrorResponsePolicy_ComponentRefs(t *testing.T) {
+	policy := annot8.DefaultErrorResponsePolicy()
+	policy.ComponentRefs = true
+	policy.Responses[http.StatusNotFound] = annot8.DefaultErrorResponse{
+		Description: "No such item",
+		MediaType:   "application/json",
+		Schema:      &annot8.Schema{Type: "object"},
+		Name:        "ItemNotFound",
+	}
+	spec := errorPolicySpec(&policy)
+
+	AssertEqual(t, "#/components/responses/ItemNotFound", spec.Paths["/items/{id}"].Get.Responses["404"].Ref)
+	AssertEqual(t, "#/components/responses/InternalServerError", spec.Paths["/items"].Get.Responses["500"].Ref)
+
+	notFound, ok := spec.Components.Responses["ItemNotFound"]
+	if !ok {
+		t.Fatalf("expected an ItemNotFound response component, got %v", schemaKeys(spec.Components.Responses))
+	}
+	AssertEqual(t, "No such item", notFound.Description)
+	if _, ok := notFound.Content["application/json"]; !ok {
+		t.Fatalf("expected application/json content, got %v", schemaKeys(notFound.Content))
+	}
+	if _, ok := spec.Components.Responses["BadRequest"]; !ok {
+		t.Fatalf("expected referenced BadRequest component, got %v", schemaKeys(spec.Components.Responses))
+	}
+	AssertDeepEqual(t, []string(nil), annot8.ValidateRefs(&spec))
+}
+
+func TestErrorResponsePolicy_Disabled(t *testing.T) {
+	spec := errorPolicySpec(&annot8.ErrorResponsePolicy{Disabled: true})
+
+	AssertDeepEqual(t, []string{"200"}, schemaKeys(spec.Paths["/items/{id}"].Get.Responses))
+	AssertDeepEqual(t, []string{"201"}, schemaKeys(spec.Paths["/items"].Post.Responses))
+}
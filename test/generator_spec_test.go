@@ -1,11 +1,18 @@
 package annot8fixtures_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
 
 	"github.com/AxelTahmid/annot8"
 )
@@ -267,11 +274,39 @@ func TestGenerateSpec_ModelRenaming(t *testing.T) {
 	}
 }
 
+// productSecuritySchemes declares the cookie and header schemes the security
+// tests infer.
+func productSecuritySchemes() map[string]annot8.SecurityScheme {
+	return map[string]annot8.SecurityScheme{
+		"RefreshTokenCookieAuth": {
+			Type:        "apiKey",
+			Name:        "refresh_token",
+			In:          "cookie",
+			Description: "Refresh token passed in HttpOnly cookie",
+		},
+		"TerminalTokenAuth": {
+			Type:        "apiKey",
+			Name:        "X-Terminal-Token",
+			In:          "header",
+			Description: "Terminal token passed in X-Terminal-Token header",
+		},
+	}
+}
+
 func TestGenerateSpec_RefreshCookieSecurity(t *testing.T) {
 	r := chi.NewRouter()
 	r.Post("/api/v1/auth/refresh", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
 
-	cfg := annot8.Config{Title: "Test API", Version: "1.0.0"}
+	inference := annot8.DefaultSecurityInferenceConfig()
+	inference.RouteRules = []annot8.RouteSecurityRule{
+		{Method: http.MethodPost, PathSuffix: "/refresh", Schemes: []string{"RefreshTokenCookieAuth"}},
+	}
+	cfg := annot8.Config{
+		Title:             "Test API",
+		Version:           "1.0.0",
+		SecurityInference: &inference,
+		SecuritySchemes:   productSecuritySchemes(),
+	}
 	g := annot8.NewGenerator()
 	spec := g.GenerateSpec(r, cfg)
 
@@ -300,7 +335,17 @@ func TestGenerateSpec_TerminalHeaderSecurity(t *testing.T) {
 	r.With(RequireTerminal()).
 		Post("/api/v1/user/terminals/{id}/sync", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
 
-	cfg := annot8.Config{Title: "Test API", Version: "1.0.0"}
+	inference := annot8.DefaultSecurityInferenceConfig()
+	inference.MiddlewareRules = []annot8.MiddlewareSecurityRule{
+		// The closure RequireTerminal returns is attributed to its constructor.
+		{Middleware: RequireTerminal, Schemes: []string{"TerminalTokenAuth"}},
+	}
+	cfg := annot8.Config{
+		Title:             "Test API",
+		Version:           "1.0.0",
+		SecurityInference: &inference,
+		SecuritySchemes:   productSecuritySchemes(),
+	}
 	g := annot8.NewGenerator()
 	spec := g.GenerateSpec(r, cfg)
 
@@ -324,11 +369,22 @@ func TestGenerateSpec_TerminalHeaderSecurity(t *testing.T) {
 	}
 }
 
-func TestGenerateSpec_IsTenantImpliesBearerByDefault(t *testing.T) {
+// productSecurityInference maps the test middlewares to schemes by identity.
+func productSecurityInference() *annot8.SecurityInferenceConfig {
+	inference := annot8.DefaultSecurityInferenceConfig()
+	inference.MiddlewareRules = []annot8.MiddlewareSecurityRule{
+		{Middleware: IsTenant, Schemes: []string{"BearerAuth"}},
+		{Middleware: RequireDualIdentity, Schemes: []string{"BearerAuth", "TerminalTokenAuth"}},
+		{Middleware: RequireTerminal, Schemes: []string{"TerminalTokenAuth"}},
+	}
+	return &inference
+}
+
+func TestGenerateSpec_IsTenantRuleImpliesBearer(t *testing.T) {
 	r := chi.NewRouter()
 	r.With(IsTenant).Post("/api/v1/auth/customer/register", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
 
-	cfg := annot8.Config{Title: "Test API", Version: "1.0.0"}
+	cfg := annot8.Config{Title: "Test API", Version: "1.0.0", SecurityInference: productSecurityInference()}
 	g := annot8.NewGenerator()
 	spec := g.GenerateSpec(r, cfg)
 
@@ -340,7 +396,7 @@ func TestGenerateSpec_IsTenantImpliesBearerByDefault(t *testing.T) {
 		t.Fatalf("expected one security requirement object, got: %+v", op.Security)
 	}
 	if _, ok := op.Security[0]["BearerAuth"]; !ok {
-		t.Fatalf("expected BearerAuth for IsTenant by default mapping: %+v", op.Security)
+		t.Fatalf("expected BearerAuth for the IsTenant rule: %+v", op.Security)
 	}
 }
 
@@ -348,7 +404,12 @@ func TestGenerateSpec_RequireDualIdentityAddsBothSchemes(t *testing.T) {
 	r := chi.NewRouter()
 	r.With(RequireDualIdentity).Post("/api/v1/user/terminals/{id}/dual", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
 
-	cfg := annot8.Config{Title: "Test API", Version: "1.0.0"}
+	cfg := annot8.Config{
+		Title:             "Test API",
+		Version:           "1.0.0",
+		SecurityInference: productSecurityInference(),
+		SecuritySchemes:   productSecuritySchemes(),
+	}
 	g := annot8.NewGenerator()
 	spec := g.GenerateSpec(r, cfg)
 
@@ -367,7 +428,7 @@ func TestGenerateSpec_RequireDualIdentityAddsBothSchemes(t *testing.T) {
 	}
 }
 
-func TestGenerateSpec_JWTNameFallbackAddsBearer(t *testing.T) {
+func TestGenerateSpec_SecurityMiddlewareWrapperAddsBearer(t *testing.T) {
 	jwtGuard := func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			next.ServeHTTP(w, r)
@@ -375,7 +436,8 @@ func TestGenerateSpec_JWTNameFallbackAddsBearer(t *testing.T) {
 	}
 
 	r := chi.NewRouter()
-	r.With(jwtGuard).Get("/api/v1/fallback/jwt", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	r.With(annot8.SecurityMiddleware(jwtGuard, "BearerAuth")).
+		Get("/api/v1/fallback/jwt", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
 
 	cfg := annot8.Config{Title: "Test API", Version: "1.0.0"}
 	g := annot8.NewGenerator()
@@ -389,7 +451,55 @@ func TestGenerateSpec_JWTNameFallbackAddsBearer(t *testing.T) {
 		t.Fatalf("expected one security requirement object, got %d", len(op.Security))
 	}
 	if _, ok := op.Security[0]["BearerAuth"]; !ok {
-		t.Fatalf("expected BearerAuth via jwt name fallback: %+v", op.Security)
+		t.Fatalf("expected BearerAuth via the SecurityMiddleware wrapper: %+v", op.Security)
+	}
+}
+
+func TestGenerateSpec_SecurityRulesMatchByIdentity(t *testing.T) {
+	// A substring rule for IsTenant would also match NotIsTenantRedirect.
+	r := chi.NewRouter()
+	r.With(NotIsTenantRedirect).Get("/api/v1/landing", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	r.With(middleware.RequestID, IsTenant, RequireTerminal()).
+		Get("/api/v1/tenant", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	cfg := annot8.Config{
+		Title:             "Test API",
+		Version:           "1.0.0",
+		SecurityInference: productSecurityInference(),
+		SecuritySchemes:   productSecuritySchemes(),
+	}
+	spec := annot8.NewGenerator().GenerateSpec(r, cfg)
+
+	if security := spec.Paths["/api/v1/landing"].Get.Security; len(security) != 0 {
+		t.Fatalf("expected no security for NotIsTenantRedirect, got %+v", security)
+	}
+	AssertDeepEqual(t, []string{
+		"GET /api/v1/tenant: middleware github.com/go-chi/chi/v5/middleware.RequestID matched no security rule",
+	}, annot8.UnmatchedSecurityMiddleware(&spec))
+}
+
+func TestGenerateSpec_WarnsUnmatchedSecurityMiddleware(t *testing.T) {
+	var logs bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelWarn})))
+
+	// RequireTerminal has a rule; IsTenant, formerly matched by name, has none.
+	r := chi.NewRouter()
+	r.With(IsTenant, RequireTerminal()).Get("/api/v1/tenant", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	inference := annot8.DefaultSecurityInferenceConfig()
+	inference.MiddlewareRules = []annot8.MiddlewareSecurityRule{
+		{Middleware: RequireTerminal, Schemes: []string{"TerminalTokenAuth"}},
+	}
+	annot8.NewGenerator().GenerateSpec(r, annot8.Config{
+		Title:             "Test API",
+		Version:           "1.0.0",
+		SecurityInference: &inference,
+		SecuritySchemes:   productSecuritySchemes(),
+	})
+
+	if !strings.Contains(logs.String(), "unmatched security middleware") ||
+		!strings.Contains(logs.String(), "IsTenant matched no security rule") {
+		t.Fatalf("expected a warning naming IsTenant, got %q", logs.String())
 	}
 }
 
@@ -493,3 +603,91 @@ func TestGenerateSpec_QueryObjectParamExpansion(t *testing.T) {
 		}
 	}
 }
+
+func TestGenerateOpenAPISpecFile_UsesConfiguredGenerator(t *testing.T) {
+	r := chi.NewRouter()
+	r.Get("/api/v2/orders", http.NotFoundHandler().ServeHTTP)
+
+	gen := NewTestGenerator()
+	gen.SetErrorResponsePolicy(annot8.ErrorResponsePolicy{Disabled: true})
+	gen.SetTagGroups(annot8.TagGroups{Ungrouped: "API"})
+
+	path := filepath.Join(t.TempDir(), "openapi.json")
+	err := annot8.GenerateOpenAPISpecFile(&annot8.GenerateParams{
+		Router:    r,
+		Config:    annot8.Config{Title: "File", Version: "1.0.0"},
+		FilePath:  path,
+		Generator: gen,
+	})
+	if err != nil {
+		t.Fatalf("GenerateOpenAPISpecFile: %v", err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var spec annot8.Spec
+	if err = json.Unmarshal(raw, &spec); err != nil {
+		t.Fatal(err)
+	}
+	AssertDeepEqual(t, []annot8.TagGroup{{Name: "API", Tags: []string{"orders"}}}, spec.TagGroups)
+	if _, ok := spec.Paths["/api/v2/orders"].Get.Responses["500"]; ok {
+		t.Fatal("expected the generator's error response policy to apply")
+	}
+}
+
+func TestGenerateOpenAPISpecFile_RenameLeavesGeneratorNaming(t *testing.T) {
+	r := chi.NewRouter()
+	h := &int64ParamHandler{}
+	r.Get("/accounts/{account}/ledger", http.HandlerFunc(h.list))
+
+	gen := NewTestGenerator()
+	err := annot8.GenerateOpenAPISpecFile(&annot8.GenerateParams{
+		Router:         r,
+		Config:         annot8.Config{Title: "File", Version: "1.0.0"},
+		FilePath:       filepath.Join(t.TempDir(), "openapi.json"),
+		Generator:      gen,
+		RenameFunction: func(pkg, name string) string { return "Renamed" + name },
+	})
+	if err != nil {
+		t.Fatalf("GenerateOpenAPISpecFile: %v", err)
+	}
+
+	spec := gen.GenerateSpec(r, annot8.Config{Title: "Spec", Version: "1.0.0"})
+	if _, ok := spec.Components.Schemas["annot8fixtures.Int64Wire"]; !ok {
+		t.Fatalf("expected the generator's own naming after writing a file, got %v", schemaKeys(spec.Components.Schemas))
+	}
+}
+
+type reportsHandler struct{}
+
+// @Summary List reports
+// @Tags reports
+// @Success 200 {object} annot8fixtures.Int64Wire "ok"
+func (h *reportsHandler) list(w http.ResponseWriter, r *http.Request) {}
+
+func TestGenerateOpenAPISpecFile_StrictValidate(t *testing.T) {
+	r := chi.NewRouter()
+	h := &reportsHandler{}
+	// The ACL fallback references BearerAuth, which is not declared.
+	r.With(IsSystemAdmin).Get("/reports", http.HandlerFunc(h.list))
+
+	params := &annot8.GenerateParams{
+		Router:   r,
+		Config:   annot8.Config{Title: "File", Version: "1.0.0"},
+		FilePath: filepath.Join(t.TempDir(), "openapi.json"),
+		Validate: true,
+	}
+	if err := annot8.GenerateOpenAPISpecFile(params); err != nil {
+		t.Fatalf("expected security scheme violations to be warnings, got %v", err)
+	}
+
+	params.StrictValidate = true
+	err := annot8.GenerateOpenAPISpecFile(params)
+	var validationErr *annot8.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected a ValidationError, got %v", err)
+	}
+	AssertDeepEqual(t, []string{`GET /reports: security scheme "BearerAuth" is not declared`}, validationErr.Violations)
+}
@@ -11,3 +11,10 @@ type ProblemDetails struct {
 type Meta struct {
 	Limit int `json:"limit"`
 }
+
+// Response is a fixture for a configurable {data} envelope.
+type Response struct {
+	OK     bool  `json:"ok"`
+	Result any   `json:"result"`
+	Meta   *Meta `json:"meta,omitempty"`
+}
@@ -0,0 +1,121 @@
+package annot8fixtures_test
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/AxelTahmid/annot8"
+)
+
+func TestImportPathIndex_SameNamedPackagesStayDistinct(t *testing.T) {
+	gen := NewTestSchemaGenerator()
+
+	ref := gen.GenerateSchema("annot8fixtures.CartLine")
+	if ref == nil || ref.Ref == "" {
+		t.Fatal("expected reference for CartLine")
+	}
+
+	schemas := gen.GetSchemas()
+	cart, ok := schemas["annot8fixtures.CartLine"]
+	if !ok {
+		t.Fatalf("expected CartLine schema, got keys %v", schemaKeys(schemas))
+	}
+
+	product := cart.Properties["product"]
+	charge := cart.Properties["charge"]
+	billed := cart.Properties["billed"]
+	if product == nil || charge == nil || billed == nil {
+		t.Fatalf("expected product, charge and billed properties, got %v", cart.Properties)
+	}
+
+	if product.Ref == charge.Ref {
+		t.Fatalf("catalog and billing Item must not share a ref, both got %q", product.Ref)
+	}
+	AssertEqual(t, charge.Ref, billed.Ref)
+
+	catalogItem, okCatalog := schemas[refID(product.Ref)]
+	billingItem, okBilling := schemas[refID(charge.Ref)]
+	if !okCatalog || !okBilling {
+		t.Fatalf("expected both Item schemas to be stored, got keys %v", schemaKeys(schemas))
+	}
+	if _, ok := catalogItem.Properties["sku"]; !ok {
+		t.Errorf("catalog Item should have sku, got %v", catalogItem.Properties)
+	}
+	if _, ok := billingItem.Properties["invoice_id"]; !ok {
+		t.Errorf("billing Item should have invoice_id, got %v", billingItem.Properties)
+	}
+}
+
+func TestImportPathIndex_ComponentNamesDisambiguateOnlyCollisions(t *testing.T) {
+	tests := []struct {
+		name      string
+		nameFunc  annot8.ModelNameFunc
+		expected  []string
+		forbidden []string
+	}{
+		{
+			name:      "default strategy",
+			expected:  []string{"catalog.models.Item", "billing.models.Item", "models.Invoice", "annot8fixtures.CartLine"},
+			forbidden: []string{"models.Item", "models.Item2"},
+		},
+		{
+			name:      "custom strategy receives the qualifier",
+			nameFunc:  func(pkg, name string) string { return pkg + "_" + name },
+			expected:  []string{"catalog.models_Item", "billing.models_Item", "models_Invoice"},
+			forbidden: []string{"models_Item"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewTestGenerator()
+			if tt.nameFunc != nil {
+				g.SetModelNameFunc(tt.nameFunc)
+			}
+			g.GenerateSchema("annot8fixtures.CartLine")
+
+			spec := g.GenerateSpec(chi.NewRouter(), annot8.Config{Title: "Import Paths", Version: "1.0.0"})
+
+			for _, name := range tt.expected {
+				if _, ok := spec.Components.Schemas[name]; !ok {
+					t.Errorf("expected component %q, got keys %v", name, schemaKeys(spec.Components.Schemas))
+				}
+			}
+			for _, name := range tt.forbidden {
+				if _, ok := spec.Components.Schemas[name]; ok {
+					t.Errorf("did not expect component %q", name)
+				}
+			}
+		})
+	}
+}
+
+func TestImportPathIndex_LookupByImportPath(t *testing.T) {
+	idx := annot8.BuildTypeIndex()
+
+	if ts := idx.LookupType("github.com/AxelTahmid/annot8/test/catalog/models", "Item"); ts == nil {
+		t.Error("expected catalog Item by import path")
+	}
+	if ts := idx.LookupType("github.com/AxelTahmid/annot8/test/billing/models", "Invoice"); ts == nil {
+		t.Error("expected billing Invoice by import path")
+	}
+	if ts := idx.LookupType("github.com/AxelTahmid/annot8/test/catalog/models", "Invoice"); ts != nil {
+		t.Error("catalog models does not declare Invoice")
+	}
+}
+
+func refID(ref string) string {
+	return strings.TrimPrefix(ref, "#/components/schemas/")
+}
+
+func schemaKeys[S any](schemas map[string]S) []string {
+	keys := make([]string, 0, len(schemas))
+	for k := range schemas {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,122 @@
+package annot8fixtures_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/AxelTahmid/annot8"
+	fixtures "github.com/AxelTahmid/annot8/test"
+)
+
+func TestInt64Representation_Modes(t *testing.T) {
+	tests := []struct {
+		name     string
+		rep      annot8.Int64Representation
+		balance  any
+		quoted   any
+		optional any
+		history  any
+	}{
+		{"string", annot8.Int64AsString, "string", "string", []string{"string", "null"}, "string"},
+		{"integer", annot8.Int64AsInteger, "integer", "string", []string{"integer", "null"}, "integer"},
+		{"json tag", annot8.Int64FromJSONTag, "integer", "string", []string{"integer", "null"}, "integer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := NewTestSchemaGenerator()
+			gen.SetInt64Representation(tt.rep)
+			gen.GenerateSchema("annot8fixtures.Int64Wire")
+			schemas := gen.GetSchemas()
+			props := schemas["annot8fixtures.Int64Wire"].Properties
+
+			AssertDeepEqual(t, tt.balance, props["balance"].Type)
+			AssertEqual(t, "int64", props["balance"].Format)
+			AssertDeepEqual(t, tt.quoted, props["quoted"].Type)
+			AssertDeepEqual(t, tt.optional, props["optional"].Type)
+			AssertDeepEqual(t, tt.history, props["history"].Items.Type)
+			AssertEqual(t, "uint64", props["history"].Items.Format)
+
+			// Field and type overrides win over the generator option.
+			AssertDeepEqual(t, any("integer"), props["serial"].Type)
+			AssertEqual(t, "#/components/schemas/annot8fixtures.OrderID", props["id"].Ref)
+			AssertDeepEqual(t, any("integer"), schemas["annot8fixtures.OrderID"].Type)
+		})
+	}
+}
+
+func TestInt64Representation_SchemaMatchesMarshaledOutput(t *testing.T) {
+	values := []fixtures.Int64Wire{
+		{History: []uint64{}},
+		{
+			ID:       9007199254740993,
+			Balance:  -9007199254740993,
+			Quoted:   1 << 62,
+			Serial:   1<<64 - 1,
+			Optional: new(int64),
+			History:  []uint64{0, 1 << 63},
+		},
+	}
+
+	gen := NewTestSchemaGenerator()
+	gen.SetInt64Representation(annot8.Int64AsInteger)
+	ref := gen.GenerateSchema("annot8fixtures.Int64Wire")
+	schemas := gen.GetSchemas()
+
+	for _, value := range values {
+		AssertMarshalMatchesSchema(t, schemas, ref, value)
+	}
+}
+
+type int64ParamHandler struct{}
+
+// @Summary List ledger entries
+// @Param account path int64 true "Account ID"
+// @Param filter query annot8fixtures.Int64Query false "Cursor filters"
+// @Success 200 {object} annot8fixtures.Int64Wire "ok"
+func (h *int64ParamHandler) list(w http.ResponseWriter, r *http.Request) {}
+
+func TestInt64Representation_Parameters(t *testing.T) {
+	r := chi.NewRouter()
+	h := &int64ParamHandler{}
+	r.Get("/accounts/{account}/ledger", http.HandlerFunc(h.list))
+
+	g := NewTestGenerator()
+	g.SetInt64Representation(annot8.Int64AsInteger)
+	spec := g.GenerateSpec(r, annot8.Config{Title: "Int64", Version: "1.0.0"})
+
+	op := spec.Paths["/accounts/{account}/ledger"].Get
+	if op == nil {
+		t.Fatal("expected GET operation")
+	}
+
+	types := map[string]any{}
+	for _, p := range op.Parameters {
+		types[p.Name] = p.Schema.Type
+	}
+	AssertDeepEqual(t, map[string]any{
+		"account":  "integer",
+		"after_id": "integer",
+		"cursor":   "string",
+	}, types)
+}
+
+func TestInt64Representation_Marshalers(t *testing.T) {
+	for _, rep := range []annot8.Int64Representation{annot8.Int64AsString, annot8.Int64AsInteger} {
+		gen := NewTestSchemaGenerator()
+		gen.SetInt64Representation(rep)
+		ref := gen.GenerateSchema("annot8fixtures.Int64Marshalers")
+		schemas := gen.GetSchemas()
+		props := schemas["annot8fixtures.Int64Marshalers"].Properties
+
+		// The marshaler writes a number whatever the generator option is.
+		AssertDeepEqual(t, any("integer"), props["id"].Type)
+		AssertEqual(t, "int64", props["id"].Format)
+		// The type's @int64 directive describes a marshaler that quotes.
+		AssertDeepEqual(t, any("string"), props["ledger"].Type)
+
+		AssertMarshalMatchesSchema(t, schemas, ref, fixtures.Int64Marshalers{ID: 1 << 62, Ledger: 1 << 62})
+	}
+}
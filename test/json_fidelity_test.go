@@ -0,0 +1,129 @@
+package annot8fixtures_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/AxelTahmid/annot8"
+	fixtures "github.com/AxelTahmid/annot8/test"
+)
+
+func TestJSONFidelity_SchemaMatchesMarshaledOutput(t *testing.T) {
+	limit := 5
+	values := []struct {
+		name  string
+		value fixtures.JSONFidelity
+	}{
+		// Nil slices and maps marshal as null; like the rest of the generator,
+		// collections are documented as non-nullable, so use empty ones here.
+		{"empty", fixtures.JSONFidelity{
+			Payload:  []byte{},
+			Meta:     map[string]any{},
+			Scores:   map[int]string{},
+			Counters: map[uint16]int{},
+			ByStatus: map[fixtures.StatusEnum]int{},
+		}},
+		{"populated", fixtures.JSONFidelity{
+			Count:    9007199254740993,
+			Ratio:    1.5,
+			Enabled:  true,
+			Limit:    &limit,
+			Total:    42,
+			Payload:  []byte("hello"),
+			Checksum: [4]byte{1, 2, 3, 4},
+			Point:    [2]float64{1.25, -3},
+			Raw:      json.RawMessage(`{"nested":[1,"two"]}`),
+			Extra:    []any{1, "x", nil},
+			Meta:     map[string]any{"k": true},
+			Amount:   json.Number("12.50"),
+			Timeout:  3 * time.Second,
+			Scores:   map[int]string{1: "a", -2: "b"},
+			Counters: map[uint16]int{7: 1},
+			ByStatus: map[fixtures.StatusEnum]int{fixtures.StatusActive: 1},
+		}},
+	}
+
+	gen := NewTestSchemaGenerator()
+	ref := gen.GenerateSchema("annot8fixtures.JSONFidelity")
+	schemas := gen.GetSchemas()
+
+	for _, tt := range values {
+		t.Run(tt.name, func(t *testing.T) {
+			AssertMarshalMatchesSchema(t, schemas, ref, tt.value)
+		})
+	}
+}
+
+func TestJSONFidelity_Keywords(t *testing.T) {
+	gen := NewTestSchemaGenerator()
+	gen.GenerateSchema("annot8fixtures.JSONFidelity")
+	props := gen.GetSchemas()["annot8fixtures.JSONFidelity"].Properties
+
+	tests := []struct {
+		field string
+		check func(t *testing.T, s *annot8.Schema)
+	}{
+		{"count", func(t *testing.T, s *annot8.Schema) {
+			AssertEqual(t, "string", s.Type.(string))
+			AssertEqual(t, "^-?[0-9]+$", s.Pattern)
+		}},
+		{"enabled", func(t *testing.T, s *annot8.Schema) {
+			AssertDeepEqual(t, []any{"true", "false"}, s.Enum)
+		}},
+		{"limit", func(t *testing.T, s *annot8.Schema) {
+			AssertDeepEqual(t, []string{"string", "null"}, s.Type)
+		}},
+		{"payload", func(t *testing.T, s *annot8.Schema) {
+			AssertEqual(t, "string", s.Type.(string))
+			AssertEqual(t, "base64", s.ContentEncoding)
+		}},
+		{"checksum", func(t *testing.T, s *annot8.Schema) {
+			AssertEqual(t, "array", s.Type.(string))
+			AssertEqual(t, 4, *s.MinItems)
+			AssertEqual(t, 4, *s.MaxItems)
+		}},
+		{"raw", func(t *testing.T, s *annot8.Schema) {
+			AssertDeepEqual(t, &annot8.Schema{}, s)
+		}},
+		{"extra", func(t *testing.T, s *annot8.Schema) {
+			AssertDeepEqual(t, &annot8.Schema{}, s)
+		}},
+		{"timeout", func(t *testing.T, s *annot8.Schema) {
+			AssertEqual(t, "integer", s.Type.(string))
+			AssertEqual(t, "int64", s.Format)
+		}},
+		{"scores", func(t *testing.T, s *annot8.Schema) {
+			AssertEqual(t, "^-?[0-9]+$", s.PropertyNames.Pattern)
+		}},
+		{"counters", func(t *testing.T, s *annot8.Schema) {
+			AssertEqual(t, "^[0-9]+$", s.PropertyNames.Pattern)
+		}},
+		{"by_status", func(t *testing.T, s *annot8.Schema) {
+			AssertEqual(t, "#/components/schemas/annot8fixtures.StatusEnum", s.PropertyNames.Ref)
+		}},
+		{"meta", func(t *testing.T, s *annot8.Schema) {
+			if s.PropertyNames != nil {
+				t.Errorf("string keys need no propertyNames, got %#v", s.PropertyNames)
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			s, ok := props[tt.field]
+			if !ok {
+				t.Fatalf("missing property %s", tt.field)
+			}
+			tt.check(t, s)
+		})
+	}
+}
+
+func TestJSONFidelity_BasicTypeNames(t *testing.T) {
+	gen := NewTestSchemaGenerator()
+
+	AssertEqual(t, "base64", gen.GenerateSchema("[]byte").ContentEncoding)
+	AssertDeepEqual(t, &annot8.Schema{}, gen.GenerateSchema("any"))
+	AssertEqual(t, "^-?[0-9]+$", gen.GenerateSchema("map[int64]string").PropertyNames.Pattern)
+}
@@ -0,0 +1,57 @@
+package annot8fixtures_test
+
+import (
+	"testing"
+
+	"github.com/AxelTahmid/annot8"
+)
+
+func nullableProps(t *testing.T, strategy annot8.NullabilityStrategy) map[string]*annot8.Schema {
+	t.Helper()
+	sg := NewTestSchemaGenerator()
+	sg.SetNullabilityStrategy(strategy)
+	_ = sg.GenerateSchema("annot8fixtures.NullableFields")
+	return sg.GetSchemas()["annot8fixtures.NullableFields"].Properties
+}
+
+func TestNullability_TypeArray(t *testing.T) {
+	t.Parallel()
+
+	props := nullableProps(t, annot8.NullTypeArray)
+	AssertDeepEqual(t, []string{"string", "null"}, props["nickname"].Type)
+	AssertDeepEqual(t, []any{"string", "null"}, props["seen"].Type)
+	AssertEqual(t, "#/components/schemas/annot8fixtures.VariantSettings", props["profile"].AnyOf[0].Ref)
+	AssertEqual(t, "null", props["profile"].AnyOf[1].Type)
+	AssertDeepEqual(t, []string{"array", "null"}, props["tags"].Type)
+}
+
+func TestNullability_AnyOf(t *testing.T) {
+	t.Parallel()
+
+	props := nullableProps(t, annot8.NullAnyOf)
+	for _, name := range []string{"nickname", "seen", "profile", "tags"} {
+		s := props[name]
+		if s.Type != nil || len(s.AnyOf) != 2 || s.AnyOf[1].Type != "null" {
+			t.Fatalf("%s: expected anyOf [T, null], got %+v", name, s)
+		}
+	}
+	AssertEqual(t, "string", props["nickname"].AnyOf[0].Type)
+	AssertEqual(t, "date-time", props["seen"].AnyOf[0].Format)
+	AssertEqual(t, "array", props["tags"].AnyOf[0].Type)
+}
+
+func TestNullability_SQLCNullWrapper(t *testing.T) {
+	t.Parallel()
+
+	sg := NewTestSchemaGenerator()
+	sg.SetNullabilityStrategy(annot8.NullAnyOf)
+	_ = sg.GenerateSchema("sqlc.NullOrderDeliveryStatus")
+	value := FindSchemaBySuffix(t, sg.GetSchemas(), "sqlc.NullOrderDeliveryStatus").Properties["order_delivery_status"]
+
+	if len(value.AnyOf) != 3 {
+		t.Fatalf("expected the enum, null and the empty string, got %+v", value.AnyOf)
+	}
+	AssertEqual(t, "#/components/schemas/sqlc.OrderDeliveryStatus", value.AnyOf[0].Ref)
+	AssertEqual(t, any("null"), value.AnyOf[1].Type)
+	AssertDeepEqual(t, []any{""}, value.AnyOf[2].Enum)
+}
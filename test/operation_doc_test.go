@@ -0,0 +1,156 @@
+package annot8fixtures_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/AxelTahmid/annot8"
+	fixtures "github.com/AxelTahmid/annot8/test"
+	billing "github.com/AxelTahmid/annot8/test/billing/models"
+	catalog "github.com/AxelTahmid/annot8/test/catalog/models"
+)
+
+func operationDocSpec(t *testing.T) annot8.Spec {
+	t.Helper()
+	r := chi.NewRouter()
+
+	files := http.StripPrefix("/static/", http.FileServer(http.Dir(".")))
+	r.Handle("/static/*", annot8.Describe(files, annot8.OperationDoc{
+		Summary: "Static assets",
+		Tags:    []string{"assets"},
+		Produce: []string{"application/octet-stream"},
+		Methods: []string{http.MethodGet, http.MethodHead},
+		Responses: []annot8.ResponseDoc{
+			{Status: http.StatusOK, Body: "string"},
+			{Status: http.StatusNotFound},
+		},
+	}))
+
+	r.With(annot8.Doc(annot8.OperationDoc{
+		Summary: "Create through the gateway",
+		Params: []annot8.ParamDoc{
+			{Name: "id", In: "path", Type: 0},
+			{Name: "X-Request-ID", In: "header", Description: "Correlation ID"},
+		},
+		Body: fixtures.TestSimple{},
+		Responses: []annot8.ResponseDoc{
+			{Status: http.StatusCreated, Body: []*fixtures.TestSimple(nil), Wrapped: true},
+		},
+		Methods: []string{"post"},
+	})).Mount("/gateway/{id}", http.NotFoundHandler())
+
+	g := NewTestGenerator()
+	return g.GenerateSpec(r, annot8.Config{Title: "Docs", Version: "1.0.0"})
+}
+
+func TestOperationDoc_Describe(t *testing.T) {
+	spec := operationDocSpec(t)
+
+	item := spec.Paths["/static/{path}"]
+	if item.Get == nil || item.Head == nil {
+		t.Fatalf("expected GET and HEAD on /static/{path}, got %+v", item)
+	}
+	if item.Post != nil || item.Delete != nil {
+		t.Fatalf("expected only the documented methods, got %+v", item)
+	}
+
+	op := item.Get
+	AssertEqual(t, "Static assets", op.Summary)
+	AssertDeepEqual(t, []string{"assets"}, op.Tags)
+	ok, exists := op.Responses["200"]
+	if !exists {
+		t.Fatalf("expected a 200 response, got %v", schemaKeys(op.Responses))
+	}
+	if _, exists := ok.Content["application/octet-stream"]; !exists {
+		t.Fatalf("expected octet-stream content, got %v", schemaKeys(ok.Content))
+	}
+	AssertEqual(t, "OK", ok.Description)
+	AssertEqual(t, "Not Found", op.Responses["404"].Description)
+	AssertEqual(t, "#/components/schemas/ProblemDetails",
+		op.Responses["404"].Content["application/problem+json"].Schema.Ref)
+}
+
+func TestOperationDoc_Middleware(t *testing.T) {
+	spec := operationDocSpec(t)
+
+	item := spec.Paths["/gateway/{id}/{path}"]
+	if item.Post == nil || item.Get != nil {
+		t.Fatalf("expected only POST on /gateway/{id}/{path}, got %+v", item)
+	}
+	op := item.Post
+	AssertEqual(t, "Create through the gateway", op.Summary)
+
+	var id, requestID *annot8.Parameter
+	for i := range op.Parameters {
+		switch op.Parameters[i].Name {
+		case "id":
+			id = &op.Parameters[i]
+		case "X-Request-ID":
+			requestID = &op.Parameters[i]
+		}
+	}
+	if id == nil || requestID == nil {
+		t.Fatalf("expected id and X-Request-ID parameters, got %+v", op.Parameters)
+	}
+	AssertEqual(t, any("integer"), id.Schema.Type)
+	AssertEqual(t, any("string"), requestID.Schema.Type)
+	AssertEqual(t, "Correlation ID", requestID.Description)
+
+	if op.RequestBody == nil || !op.RequestBody.Required {
+		t.Fatalf("expected a required request body, got %+v", op.RequestBody)
+	}
+	body := op.RequestBody.Content["application/json"].Schema
+	if !strings.HasSuffix(body.Ref, "TestSimple") {
+		t.Fatalf("expected the body to reference TestSimple, got %+v", body)
+	}
+
+	created := op.Responses["201"].Content["application/json"].Schema
+	data := created.Properties["data"]
+	if data == nil || data.Items == nil || !strings.HasSuffix(data.Items.Ref, "TestSimple") {
+		t.Fatalf("expected wrapped TestSimple items, got %+v", created)
+	}
+	if _, ok := created.Properties["meta"]; !ok {
+		t.Fatalf("expected pagination meta for a wrapped slice, got %v", schemaKeys(created.Properties))
+	}
+}
+
+func TestOperationDoc_CompositeTypeNames(t *testing.T) {
+	r := chi.NewRouter()
+	r.Handle("/items", annot8.Describe(http.NotFoundHandler(), annot8.OperationDoc{
+		Summary: "Items by key",
+		Methods: []string{http.MethodGet},
+		Responses: []annot8.ResponseDoc{
+			// Both packages are named models; only import paths tell them apart.
+			{Status: http.StatusOK, Body: map[string]billing.Item(nil)},
+			{Status: http.StatusCreated, Body: [2]catalog.Item{}},
+			{Status: http.StatusAccepted, Body: fixtures.Page[catalog.Item]{}},
+		},
+	}))
+	spec := NewTestGenerator().GenerateSpec(r, annot8.Config{Title: "Docs", Version: "1.0.0"})
+	responses := spec.Paths["/items"].Get.Responses
+	schemas := spec.Components.Schemas
+
+	byKey := responses["200"].Content["application/json"].Schema
+	values, ok := byKey.AdditionalProperties.(*annot8.Schema)
+	if !ok || values.Ref == "" {
+		t.Fatalf("expected map values to reference billing Item, got %+v", byKey)
+	}
+	if _, ok := schemas[refID(values.Ref)].Properties["invoice_id"]; !ok {
+		t.Errorf("expected %s to be the billing Item", values.Ref)
+	}
+
+	pair := responses["201"].Content["application/json"].Schema
+	AssertEqual(t, 2, *pair.MinItems)
+	AssertEqual(t, 2, *pair.MaxItems)
+	if _, ok := schemas[refID(pair.Items.Ref)].Properties["sku"]; !ok {
+		t.Errorf("expected %s to be the catalog Item", pair.Items.Ref)
+	}
+
+	page := responses["202"].Content["application/json"].Schema
+	if !strings.HasSuffix(page.Ref, "Page") {
+		t.Errorf("expected the generic type's schema, got %+v", page)
+	}
+}
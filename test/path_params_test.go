@@ -0,0 +1,99 @@
+package annot8fixtures_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/AxelTahmid/annot8"
+)
+
+// @Summary Get invoice
+// @Tags invoices
+// @Param id path string true "Invoice number"
+// @Success 200 {object} map[string]string "ok"
+func invoiceByNumberHandler(w http.ResponseWriter, r *http.Request) {}
+
+// @Summary Get archived invoice
+// @Tags invoices
+// @Param year path string true "Archive year"
+// @Param slug path string true "Invoice slug"
+// @Success 200 {object} map[string]string "ok"
+func archivedInvoiceHandler(w http.ResponseWriter, r *http.Request) {}
+
+func pathParamSpec() annot8.Spec {
+	r := chi.NewRouter()
+	stub := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	r.Get("/orders/{id:[0-9]+}", stub)
+	r.Get("/orders/{id:[0-9]+}/items/{sku:[A-Z]{3}-[0-9]{4}}", stub)
+	r.Get("/accounts/{account:[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}}", stub)
+	r.Get("/reports/{day:\\d{4}-\\d{2}-\\d{2}}", stub)
+	r.Get("/users/{id:\\d+}", stub)
+	r.Get("/pages/{n:[1-9][0-9]*}", stub)
+	r.Get("/zones/{zip:[0-9]{5}}", stub)
+	r.Get("/codes/{code:0[0-9]+}", stub)
+	r.Get("/invoices/{id:[0-9]+}", invoiceByNumberHandler)
+	r.Get("/archive/{year:[0-9]{4}}/{slug:[a-z-]+}", archivedInvoiceHandler)
+
+	return NewTestGenerator().GenerateSpec(r, annot8.Config{Title: "Path Params", Version: "1.0.0"})
+}
+
+func pathParam(t *testing.T, op *annot8.Operation, name string) *annot8.Schema {
+	t.Helper()
+	for _, p := range op.Parameters {
+		if p.In == "path" && p.Name == name {
+			return p.Schema
+		}
+	}
+	t.Fatalf("no path parameter %q in %+v", name, op.Parameters)
+	return nil
+}
+
+func TestPathParams_TypedFromRegex(t *testing.T) {
+	spec := pathParamSpec()
+
+	tests := []struct {
+		path, name, typ, format, pattern string
+	}{
+		{"/orders/{id}", "id", "integer", "", ""},
+		{"/orders/{id}/items/{sku}", "sku", "string", "", "^[A-Z]{3}-[0-9]{4}$"},
+		{"/accounts/{account}", "account", "string", "uuid", "^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$"},
+		{"/reports/{day}", "day", "string", "date", `^\d{4}-\d{2}-\d{2}$`},
+		{"/users/{id}", "id", "integer", "", ""},
+		{"/pages/{n}", "n", "integer", "", ""},
+		// Bounded or zero-padded digits are strings: "01234" is not a number.
+		{"/zones/{zip}", "zip", "string", "", "^[0-9]{5}$"},
+		{"/codes/{code}", "code", "string", "", "^0[0-9]+$"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			item, ok := spec.Paths[tt.path]
+			if !ok {
+				t.Fatalf("expected path %s, got %v", tt.path, schemaKeys(spec.Paths))
+			}
+			s := pathParam(t, item.Get, tt.name)
+			AssertEqual(t, any(tt.typ), s.Type)
+			AssertEqual(t, tt.format, s.Format)
+			AssertEqual(t, tt.pattern, s.Pattern)
+		})
+	}
+}
+
+func TestPathParams_AnnotationWins(t *testing.T) {
+	spec := pathParamSpec()
+
+	// Conflicting type: the declaration is documented and the conflict reported.
+	id := pathParam(t, spec.Paths["/invoices/{id}"].Get, "id")
+	AssertEqual(t, "string", id.Type)
+
+	// Agreeing type: the declared strings keep the route's patterns.
+	archive := spec.Paths["/archive/{year}/{slug}"].Get
+	AssertEqual(t, "^[0-9]{4}$", pathParam(t, archive, "year").Pattern)
+	AssertEqual(t, "^[a-z-]+$", pathParam(t, archive, "slug").Pattern)
+
+	AssertDeepEqual(t, []string{
+		`GET /invoices/{id:[0-9]+}: path parameter "id": @Param documents string but the route constraint implies integer`,
+	}, annot8.ValidatePathParameters(&spec))
+}
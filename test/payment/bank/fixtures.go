@@ -0,0 +1,10 @@
+package bank
+
+// Transfer is a bank transfer payment.
+type Transfer struct {
+	Kind string `json:"kind"`
+	IBAN string `json:"iban"`
+}
+
+// PaymentKind implements payment.Method.
+func (Transfer) PaymentKind() string { return "bank_transfer" }
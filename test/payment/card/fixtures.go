@@ -0,0 +1,13 @@
+package card
+
+// Card is a card payment.
+type Card struct {
+	Kind  string `json:"kind"`
+	Last4 string `json:"last4"`
+}
+
+// PaymentKind implements payment.Method.
+func (Card) PaymentKind() string { return "card" }
+
+// RefundID implements payment.Refundable.
+func (c Card) RefundID() string { return c.Last4 }
@@ -0,0 +1,40 @@
+package payment
+
+// Method is a payment instrument chosen at checkout.
+//
+// @discriminator kind
+// @oneOf card=card.Card bank_transfer=bank.Transfer
+type Method interface {
+	PaymentKind() string
+}
+
+// Instrument is sealed; its implementations are found by scanning.
+type Instrument interface {
+	isInstrument()
+}
+
+// Refundable is documented through the implementations registry.
+type Refundable interface {
+	RefundID() string
+}
+
+// Voucher implements Instrument with a value receiver.
+type Voucher struct {
+	Code string `json:"code"`
+}
+
+func (Voucher) isInstrument() {}
+
+// GiftCard implements Instrument with a pointer receiver.
+type GiftCard struct {
+	Balance int `json:"balance"`
+}
+
+func (*GiftCard) isInstrument() {}
+
+// Checkout holds interface-typed fields.
+type Checkout struct {
+	Method     Method     `json:"method"`
+	Instrument Instrument `json:"instrument"`
+	Refund     Refundable `json:"refund,omitempty"`
+}
@@ -0,0 +1,93 @@
+package annot8fixtures_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/AxelTahmid/annot8"
+	"github.com/AxelTahmid/annot8/test/payment"
+	"github.com/AxelTahmid/annot8/test/payment/bank"
+	"github.com/AxelTahmid/annot8/test/payment/card"
+)
+
+func TestPolymorphism_DocDirectives(t *testing.T) {
+	gen := NewTestSchemaGenerator()
+	gen.GenerateSchema("payment.Checkout")
+	schemas := gen.GetSchemas()
+
+	method, ok := schemas["payment.Method"]
+	if !ok {
+		t.Fatalf("expected payment.Method component, got keys %v", schemaKeys(schemas))
+	}
+	AssertDeepEqual(t, []*annot8.Schema{
+		{Ref: "#/components/schemas/card.Card"},
+		{Ref: "#/components/schemas/bank.Transfer"},
+	}, method.OneOf)
+	AssertDeepEqual(t, &annot8.Discriminator{
+		PropertyName: "kind",
+		Mapping: map[string]string{
+			"card":          "#/components/schemas/card.Card",
+			"bank_transfer": "#/components/schemas/bank.Transfer",
+		},
+	}, method.Discriminator)
+
+	checkout := schemas["payment.Checkout"]
+	AssertEqual(t, "#/components/schemas/payment.Method", checkout.Properties["method"].Ref)
+}
+
+func TestPolymorphism_ImplementerScan(t *testing.T) {
+	gen := NewTestSchemaGenerator()
+	gen.GenerateSchema("payment.Instrument")
+	instrument := gen.GetSchemas()["payment.Instrument"]
+
+	AssertDeepEqual(t, []*annot8.Schema{
+		{Ref: "#/components/schemas/payment.GiftCard"},
+		{Ref: "#/components/schemas/payment.Voucher"},
+	}, instrument.OneOf)
+	if instrument.Discriminator != nil {
+		t.Errorf("scan without a discriminator should not set one, got %#v", instrument.Discriminator)
+	}
+}
+
+func TestPolymorphism_Registry(t *testing.T) {
+	idx := annot8.BuildTypeIndex()
+	idx.RegisterImplementations("payment.Refundable", annot8.Implementations{
+		Discriminator: "kind",
+		Mapping:       map[string]string{"card": "card.Card"},
+	})
+	gen := annot8.NewSchemaGenerator(idx)
+	gen.GenerateSchema("payment.Refundable")
+	refundable := gen.GetSchemas()["payment.Refundable"]
+
+	AssertDeepEqual(t, []*annot8.Schema{{Ref: "#/components/schemas/card.Card"}}, refundable.OneOf)
+	AssertEqual(t, "#/components/schemas/card.Card", refundable.Discriminator.Mapping["card"])
+}
+
+func TestPolymorphism_MarshaledOutputMatches(t *testing.T) {
+	gen := NewTestSchemaGenerator()
+	ref := gen.GenerateSchema("payment.Checkout")
+	schemas := gen.GetSchemas()
+
+	for _, checkout := range []payment.Checkout{
+		{Method: card.Card{Kind: "card", Last4: "4242"}, Instrument: payment.Voucher{Code: "X"}},
+		{Method: bank.Transfer{Kind: "bank_transfer", IBAN: "DE89"}, Instrument: &payment.GiftCard{Balance: 5}},
+	} {
+		AssertMarshalMatchesSchema(t, schemas, ref, checkout)
+	}
+}
+
+func TestPolymorphism_MappingFollowsRenamedComponents(t *testing.T) {
+	g := NewTestGenerator()
+	g.SetModelNameFunc(func(pkg, name string) string { return strings.ToUpper(pkg[:1]) + pkg[1:] + name })
+	g.GenerateSchema("payment.Checkout")
+	spec := g.GenerateSpec(chi.NewRouter(), annot8.Config{Title: "Payments", Version: "1.0.0"})
+
+	method, ok := spec.Components.Schemas["PaymentMethod"]
+	if !ok {
+		t.Fatalf("expected PaymentMethod component, got keys %v", schemaKeys(spec.Components.Schemas))
+	}
+	AssertEqual(t, "#/components/schemas/CardCard", method.Discriminator.Mapping["card"])
+	AssertEqual(t, "#/components/schemas/BankTransfer", method.Discriminator.Mapping["bank_transfer"])
+}
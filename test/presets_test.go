@@ -0,0 +1,100 @@
+package annot8fixtures_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/netip"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/AxelTahmid/annot8"
+	fixtures "github.com/AxelTahmid/annot8/test"
+)
+
+func TestPresets_StdLibMatchesMarshaledOutput(t *testing.T) {
+	idx := annot8.BuildTypeIndex()
+	idx.ApplyPresets(annot8.Presets.StdLib)
+	gen := annot8.NewSchemaGenerator(idx)
+	ref := gen.GenerateSchema("annot8fixtures.StdLibTypes")
+	schemas := gen.GetSchemas()
+
+	now := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	homepage, err := url.Parse("https://user:pw@example.com/a?b=c#d")
+	AssertNoError(t, err)
+
+	values := []struct {
+		name  string
+		value fixtures.StdLibTypes
+	}{
+		{"zero value", fixtures.StdLibTypes{}},
+		{"populated", fixtures.StdLibTypes{
+			CreatedAt: now,
+			DeletedAt: &now,
+			Nickname:  sql.NullString{String: "ann", Valid: true},
+			Visits:    sql.NullInt64{Int64: 9007199254740993, Valid: true},
+			Score:     sql.NullFloat64{Float64: 0.5, Valid: true},
+			Active:    sql.NullBool{Bool: true, Valid: true},
+			SeenAt:    sql.NullTime{Time: now, Valid: true},
+			Addr:      netip.MustParseAddr("2001:db8::1"),
+			Network:   netip.MustParsePrefix("10.0.0.0/8"),
+			Endpoint:  netip.MustParseAddrPort("127.0.0.1:8080"),
+			Homepage:  *homepage,
+			Callback:  homepage,
+		}},
+	}
+
+	for _, tt := range values {
+		t.Run(tt.name, func(t *testing.T) {
+			AssertMarshalMatchesSchema(t, schemas, ref, tt.value)
+		})
+	}
+
+	props := schemas["annot8fixtures.StdLibTypes"].Properties
+	AssertEqual(t, "date-time", props["created_at"].Format)
+	AssertDeepEqual(t, []string{"String", "Valid"}, props["nickname"].Required)
+	AssertEqual(t, "net/url.URL marshaled field by field", props["homepage"].Description)
+}
+
+//go:generate go run -C testdata/presets . ../preset_samples.json
+
+// Third-party libraries are not dependencies of this module. Their presets
+// are checked against testdata/preset_samples.json, which the program in
+// testdata/presets records by calling json.Marshal on the real types.
+func TestPresets_ThirdPartyMatchRecordedOutput(t *testing.T) {
+	raw, err := os.ReadFile("testdata/preset_samples.json")
+	AssertNoError(t, err)
+	var recorded map[string][]json.RawMessage
+	AssertNoError(t, json.Unmarshal(raw, &recorded))
+
+	presets := []annot8.Preset{annot8.Presets.PgxV5, annot8.Presets.GoogleUUID, annot8.Presets.ShopspringDecimal}
+	for _, preset := range presets {
+		for typeKey, schema := range preset.Types {
+			t.Run(typeKey, func(t *testing.T) {
+				samples := recorded[typeKey]
+				if len(samples) == 0 {
+					t.Fatalf("no recorded samples for %s; add them to testdata/presets and run go generate", typeKey)
+				}
+				for _, sample := range samples {
+					AssertJSONMatchesSchema(t, nil, schema, sample)
+				}
+			})
+		}
+	}
+}
+
+func TestPresets_ApplyToGenerator(t *testing.T) {
+	g := annot8.NewGeneratorWithCache(annot8.BuildTypeIndex())
+	g.ApplyPresets(annot8.Presets.PgxV5, annot8.Presets.GoogleUUID)
+
+	text := g.GenerateSchema("github.com/jackc/pgx/v5/pgtype.Text")
+	AssertDeepEqual(t, []string{"string", "null"}, text.Type)
+
+	id := g.GenerateSchema("github.com/google/uuid.UUID")
+	AssertEqual(t, "uuid", id.Format)
+
+	// Applying copies the mappings: editing the result leaves the preset intact.
+	text.Description = "changed"
+	AssertEqual(t, "Nullable text", annot8.Presets.PgxV5.Types["github.com/jackc/pgx/v5/pgtype.Text"].Description)
+}
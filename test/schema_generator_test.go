@@ -293,3 +293,108 @@ func TestSchemaGenerator_MarshalerFallbacks(t *testing.T) {
 		AssertEqual(t, "date-time", schema.Format)
 	})
 }
+
+func TestSchemaGenerator_TypedTagValues(t *testing.T) {
+	t.Parallel()
+
+	sg := NewTestSchemaGenerator()
+	_ = sg.GenerateSchema("annot8fixtures.TagTypedValues")
+	props := FindSchemaBySuffix(t, sg.GetSchemas(), ".TagTypedValues").Properties
+
+	tests := []struct {
+		field   string
+		example any
+		def     any
+		enum    []any
+	}{
+		{"limit", int64(25), int64(10), []any{int64(10), int64(25), int64(50)}},
+		{"ratio", nil, 0.5, nil},
+		{"active", nil, true, nil},
+		{"tags", []any{"red", "green"}, nil, nil},
+		{"sizes", nil, []any{int64(1), int64(2), int64(3)}, nil},
+		{"meta", map[string]any{"a": float64(1), "b": float64(2)}, nil, nil},
+		{"name", "10", "anonymous", nil},
+		{"extra", map[string]any{"nested": []any{float64(1), true}}, nil, nil},
+		{"broken", nil, nil, nil},
+		{"mismatch", nil, nil, nil},
+		{"labels", nil, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			s := props[tt.field]
+			AssertDeepEqual(t, tt.example, s.Example)
+			AssertDeepEqual(t, tt.def, s.Default)
+			AssertDeepEqual(t, tt.enum, s.Enum)
+		})
+	}
+}
+
+func TestSchemaGenerator_TypedTagValueDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	sg := NewTestSchemaGenerator()
+	_ = sg.GenerateSchema("annot8fixtures.TagTypedValues")
+
+	AssertDeepEqual(t, []string{
+		`annot8fixtures.TagTypedValues.broken: default "ten" is not an integer`,
+		`annot8fixtures.TagTypedValues.broken: enum "two" is not an integer`,
+		`annot8fixtures.TagTypedValues.labels: default "none" is not a JSON object`,
+		`annot8fixtures.TagTypedValues.mismatch: example "x" is not an integer`,
+	}, sg.Diagnostics())
+}
+
+func TestSchemaGenerator_SchemaKeywordTags(t *testing.T) {
+	t.Parallel()
+
+	sg := NewTestSchemaGenerator()
+	_ = sg.GenerateSchema("annot8fixtures.TagSchemaKeywords")
+	props := FindSchemaBySuffix(t, sg.GetSchemas(), ".TagSchemaKeywords").Properties
+
+	amount := props["amount"]
+	AssertDeepEqual(t, floatPtr(0.01), amount.MultipleOf)
+	AssertDeepEqual(t, any(9.99), amount.Const)
+	AssertEqual(t, "fixed price", amount.Comment)
+
+	scores := props["scores"]
+	AssertDeepEqual(t, floatPtr(90), scores.Contains.Minimum)
+	AssertEqual(t, 1, *scores.MinContains)
+	AssertEqual(t, 3, *scores.MaxContains)
+
+	pair := props["pair"]
+	if len(pair.PrefixItems) != 2 {
+		t.Fatalf("expected two prefixItems, got %+v", pair.PrefixItems)
+	}
+	AssertEqual(t, "string", pair.PrefixItems[0].Type)
+	AssertEqual(t, "integer", pair.PrefixItems[1].Type)
+
+	headers := props["headers"]
+	AssertEqual(t, "string", headers.PatternProperties["^x-"].Type)
+	AssertEqual(t, "^[a-z-]+$", headers.PropertyNames.Pattern)
+	AssertEqual(t, 1, *headers.MinProperties)
+	AssertEqual(t, 8, *headers.MaxProperties)
+
+	settings := props["settings"]
+	AssertEqual(t, false, settings.UnevaluatedProperties)
+	AssertDeepEqual(t, map[string][]string{"card": {"cvv"}}, settings.DependentRequired)
+	AssertDeepEqual(t, []string{"expiry"}, settings.DependentSchemas["card"].Required)
+
+	mode := props["mode"]
+	AssertDeepEqual(t, []string{"fast"}, mode.If.Required)
+	AssertEqual(t, 1, *mode.Then.MaxProperties)
+	AssertEqual(t, "object", mode.Else.Type)
+
+	avatar := props["avatar"]
+	AssertEqual(t, "image/png", avatar.ContentMediaType)
+	AssertEqual(t, "base64", avatar.ContentEncoding)
+	AssertEqual(t, "urn:avatar", avatar.ID)
+
+	bad := props["bad"]
+	if bad.Contains != nil || bad.PrefixItems != nil {
+		t.Fatalf("expected invalid schema values to be dropped, got %+v", bad)
+	}
+	AssertDeepEqual(t, []string{
+		`annot8fixtures.TagSchemaKeywords.bad: contains "thing" is not a JSON schema or type name`,
+		`annot8fixtures.TagSchemaKeywords.bad: prefixItems "string" is not a list of schemas; write [a,b]`,
+	}, sg.Diagnostics())
+}
@@ -0,0 +1,100 @@
+package annot8fixtures_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/AxelTahmid/annot8"
+)
+
+type variantHandler struct{}
+
+// @Summary Create account
+// @Tags accounts
+// @Param account body annot8fixtures.VariantAccount true "Account"
+// @Success 201 {object} annot8fixtures.VariantAccount "Created"
+func (h *variantHandler) create(w http.ResponseWriter, r *http.Request) {}
+
+// @Summary Update account
+// @Tags accounts
+// @Param account body annot8fixtures.VariantAccount true "Account"
+// @Success 200 {object} annot8fixtures.VariantAccount "Updated"
+func (h *variantHandler) update(w http.ResponseWriter, r *http.Request) {}
+
+func variantSpec(mode annot8.SchemaVariants) annot8.Spec {
+	r := chi.NewRouter()
+	h := &variantHandler{}
+	r.Post("/accounts", http.HandlerFunc(h.create))
+	r.Patch("/accounts/{id}", http.HandlerFunc(h.update))
+
+	g := NewTestGenerator()
+	g.SetSchemaVariants(mode)
+	return g.GenerateSpec(r, annot8.Config{Title: "Variants", Version: "1.0.0"})
+}
+
+func bodyRef(t *testing.T, op *annot8.Operation) string {
+	t.Helper()
+	return op.RequestBody.Content["application/json"].Schema.Ref
+}
+
+func responseRef(t *testing.T, op *annot8.Operation, code string) string {
+	t.Helper()
+	for _, media := range op.Responses[code].Content {
+		return media.Schema.Ref
+	}
+	t.Fatalf("no content for response %s", code)
+	return ""
+}
+
+func TestSchemaVariants_InputOutput(t *testing.T) {
+	spec := variantSpec(annot8.VariantsInputOutput)
+	schemas := spec.Components.Schemas
+
+	post := spec.Paths["/accounts"].Post
+	AssertEqual(t, "#/components/schemas/annot8fixtures.VariantAccountInput", bodyRef(t, post))
+	AssertEqual(t, "#/components/schemas/annot8fixtures.VariantAccountOutput", responseRef(t, post, "201"))
+
+	input := schemas["annot8fixtures.VariantAccountInput"]
+	AssertDeepEqual(t, []string{"email", "password", "profile", "settings"}, schemaKeys(input.Properties))
+	AssertDeepEqual(t, []string{"email", "password", "profile", "settings"}, input.Required)
+	AssertEqual(t, "#/components/schemas/annot8fixtures.VariantProfileInput", input.Properties["profile"].Ref)
+	// Components a variant does not change are shared.
+	AssertEqual(t, "#/components/schemas/annot8fixtures.VariantSettings", input.Properties["settings"].Ref)
+	AssertDeepEqual(t, []string{"display_name"}, schemaKeys(schemas["annot8fixtures.VariantProfileInput"].Properties))
+
+	output := schemas["annot8fixtures.VariantAccountOutput"]
+	AssertDeepEqual(t, []string{"created_at", "email", "id", "profile", "settings"}, schemaKeys(output.Properties))
+	AssertEqual(t, "#/components/schemas/annot8fixtures.VariantProfile", output.Properties["profile"].Ref)
+	if HasSchemaWithSuffix(schemas, "VariantProfileOutput") {
+		t.Fatal("unchanged components should not get an Output variant")
+	}
+
+	// The base component stays as declared.
+	AssertEqual(t, 6, len(schemas["annot8fixtures.VariantAccount"].Properties))
+}
+
+func TestSchemaVariants_CreateUpdate(t *testing.T) {
+	spec := variantSpec(annot8.VariantsCreateUpdate)
+	schemas := spec.Components.Schemas
+
+	AssertEqual(t, "#/components/schemas/annot8fixtures.VariantAccountCreate", bodyRef(t, spec.Paths["/accounts"].Post))
+	AssertEqual(t, "#/components/schemas/annot8fixtures.VariantAccountUpdate", bodyRef(t, spec.Paths["/accounts/{id}"].Patch))
+
+	update := schemas["annot8fixtures.VariantAccountUpdate"]
+	if len(update.Required) != 0 {
+		t.Fatalf("expected update variant to require nothing, got %v", update.Required)
+	}
+	AssertEqual(t, "#/components/schemas/annot8fixtures.VariantSettingsUpdate", update.Properties["settings"].Ref)
+	AssertDeepEqual(t, []string{"email", "password", "profile", "settings"}, schemas["annot8fixtures.VariantAccountCreate"].Required)
+}
+
+func TestSchemaVariants_SharedByDefault(t *testing.T) {
+	spec := variantSpec(annot8.VariantsShared)
+
+	AssertEqual(t, "#/components/schemas/annot8fixtures.VariantAccount", bodyRef(t, spec.Paths["/accounts"].Post))
+	if HasSchemaWithSuffix(spec.Components.Schemas, "VariantAccountInput") {
+		t.Fatal("variants should only be derived when enabled")
+	}
+}
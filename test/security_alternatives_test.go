@@ -0,0 +1,75 @@
+package annot8fixtures_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/AxelTahmid/annot8"
+)
+
+func alternativesSpec() annot8.Spec {
+	r := chi.NewRouter()
+	stub := http.NotFoundHandler()
+	r.With(GatewayAuth).Method(http.MethodGet, "/gateway", stub)
+	r.With(OptionalAuth).Method(http.MethodGet, "/feed", stub)
+	r.With(GatewayAuth, RequireTerminal()).Method(http.MethodGet, "/terminal", stub)
+	r.Method(http.MethodGet, "/public/preview", stub)
+	r.With(GatewayAuth).Method(http.MethodGet, "/declared", annot8.Describe(stub, annot8.OperationDoc{
+		Security: []string{"BearerAuth[orders:read] && TerminalTokenAuth || ApiKeyAuth"},
+	}))
+	r.With(GatewayAuth).Method(http.MethodGet, "/optional", annot8.Describe(stub, annot8.OperationDoc{
+		Security: []string{"optional"},
+	}))
+	r.With(GatewayAuth).Method(http.MethodGet, "/none", annot8.Describe(stub, annot8.OperationDoc{
+		Security: []string{"none"},
+	}))
+
+	inference := annot8.DefaultSecurityInferenceConfig()
+	inference.MiddlewareRules = []annot8.MiddlewareSecurityRule{
+		{Middleware: GatewayAuth, Schemes: []string{"BearerAuth"}, Alternatives: [][]string{{"ApiKeyAuth"}}},
+		{Middleware: OptionalAuth, Schemes: []string{"BearerAuth"}, Optional: true},
+		{Middleware: RequireTerminal, Schemes: []string{"TerminalTokenAuth"}},
+	}
+	inference.RouteRules = []annot8.RouteSecurityRule{
+		{Method: http.MethodGet, PathSuffix: "/preview", Schemes: []string{"BearerAuth"}, Optional: true},
+	}
+	return NewTestGenerator().GenerateSpec(r, annot8.Config{
+		Title:             "Alternatives",
+		Version:           "1.0.0",
+		SecurityInference: &inference,
+	})
+}
+
+func TestSecurityAlternatives(t *testing.T) {
+	spec := alternativesSpec()
+
+	tests := []struct {
+		path string
+		want []annot8.SecurityRequirement
+	}{
+		{"/gateway", []annot8.SecurityRequirement{{"BearerAuth": {}}, {"ApiKeyAuth": {}}}},
+		{"/feed", []annot8.SecurityRequirement{{"BearerAuth": {}}, {}}},
+		{"/terminal", []annot8.SecurityRequirement{
+			{"BearerAuth": {}, "TerminalTokenAuth": {}},
+			{"ApiKeyAuth": {}, "TerminalTokenAuth": {}},
+		}},
+		{"/public/preview", []annot8.SecurityRequirement{{"BearerAuth": {}}, {}}},
+		{"/declared", []annot8.SecurityRequirement{
+			{"BearerAuth": {"orders:read"}, "TerminalTokenAuth": {}},
+			{"ApiKeyAuth": {}},
+		}},
+		{"/optional", []annot8.SecurityRequirement{{"BearerAuth": {}}, {"ApiKeyAuth": {}}, {}}},
+		{"/none", []annot8.SecurityRequirement{{}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			op := spec.Paths[tt.path].Get
+			if op == nil {
+				t.Fatalf("expected GET %s, got %v", tt.path, schemaKeys(spec.Paths))
+			}
+			AssertDeepEqual(t, tt.want, op.Security)
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package annot8fixtures_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/AxelTahmid/annot8"
+)
+
+func securitySchemesSpec() annot8.Spec {
+	r := chi.NewRouter()
+	stub := http.NotFoundHandler()
+	r.Method(http.MethodGet, "/public", stub)
+	r.Method(http.MethodGet, "/orders", annot8.Describe(stub, annot8.OperationDoc{Security: []string{"OAuth"}}))
+	r.Method(http.MethodGet, "/admin", annot8.Describe(stub, annot8.OperationDoc{Security: []string{"Undeclared"}}))
+
+	cfg := annot8.Config{
+		Title:   "Schemes",
+		Version: "1.0.0",
+		SecuritySchemes: map[string]annot8.SecurityScheme{
+			"OAuth": {
+				Type: "oauth2",
+				Flows: &annot8.OAuthFlows{
+					AuthorizationCode: &annot8.OAuthFlow{
+						AuthorizationURL: "https://auth.example.com/authorize",
+						TokenURL:         "https://auth.example.com/token",
+						Scopes:           map[string]string{"orders:read": "Read orders"},
+					},
+					ClientCredentials: &annot8.OAuthFlow{TokenURL: "https://auth.example.com/token"},
+					DeviceAuthorization: &annot8.OAuthFlow{
+						DeviceAuthorizationURL: "https://auth.example.com/device",
+						TokenURL:               "https://auth.example.com/token",
+					},
+				},
+			},
+			"OIDC":   {Type: "openIdConnect", OpenIDConnectURL: "https://auth.example.com/.well-known/openid-configuration"},
+			"Client": {Type: "mutualTLS"},
+		},
+	}
+	return NewTestGenerator().GenerateSpec(r, cfg)
+}
+
+func TestSecuritySchemes_PrunesUnreferenced(t *testing.T) {
+	spec := securitySchemesSpec()
+
+	AssertDeepEqual(t, []string{"OAuth"}, schemaKeys(spec.Components.SecuritySchemes))
+}
+
+func TestSecuritySchemes_OAuthFlows(t *testing.T) {
+	spec := securitySchemesSpec()
+
+	raw, err := json.Marshal(spec.Components.SecuritySchemes["OAuth"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Flows map[string]map[string]any `json:"flows"`
+	}
+	if err = json.Unmarshal(raw, &doc); err != nil {
+		t.Fatal(err)
+	}
+	// The device flow is OpenAPI 3.2; 3.1 specs carry it as an extension.
+	AssertDeepEqual(t, []string{"authorizationCode", "clientCredentials", "x-deviceAuthorization"}, schemaKeys(doc.Flows))
+	AssertEqual(t, any("https://auth.example.com/device"), doc.Flows["x-deviceAuthorization"]["deviceAuthorizationUrl"])
+	// Scopes are required even when a flow defines none.
+	AssertDeepEqual(t, any(map[string]any{}), doc.Flows["clientCredentials"]["scopes"])
+}
+
+func TestSecuritySchemes_Validation(t *testing.T) {
+	spec := securitySchemesSpec()
+	spec.Paths["/orders"].Get.Security = []annot8.SecurityRequirement{{"OAuth": {"orders:read", "orders:write"}}}
+	spec.Components.SecuritySchemes["Broken"] = annot8.SecurityScheme{
+		Type: "oauth2",
+		Flows: &annot8.OAuthFlows{
+			Password:          &annot8.OAuthFlow{},
+			ClientCredentials: &annot8.OAuthFlow{TokenURL: "https://auth.example.com/token", DeviceAuthorizationURL: "https://auth.example.com/device"},
+		},
+	}
+
+	AssertDeepEqual(t, []string{
+		`GET /admin: security scheme "Undeclared" is not declared`,
+		`GET /orders: scope "orders:write" is not defined by oauth2 scheme "OAuth"`,
+		`security scheme "Broken": clientCredentials flow cannot have a deviceAuthorizationUrl`,
+		`security scheme "Broken": password flow needs a tokenUrl`,
+	}, annot8.ValidateSecuritySchemes(&spec))
+}
+
+func IsSystemAdmin(next http.Handler) http.Handler { return next }
+
+func TestSecuritySchemes_ACLFallbackScheme(t *testing.T) {
+	r := chi.NewRouter()
+	r.With(IsSystemAdmin).Get("/reports", http.NotFoundHandler().ServeHTTP)
+
+	// An undeclared fallback scheme is still referenced, and reported.
+	spec := NewTestGenerator().GenerateSpec(r, annot8.Config{Title: "ACL", Version: "1.0.0"})
+	AssertDeepEqual(t, []annot8.SecurityRequirement{{"BearerAuth": {}}}, spec.Paths["/reports"].Get.Security)
+	if len(spec.Components.SecuritySchemes) != 0 {
+		t.Fatalf("expected no security schemes by default, got %v", schemaKeys(spec.Components.SecuritySchemes))
+	}
+	AssertDeepEqual(t, []string{`GET /reports: security scheme "BearerAuth" is not declared`}, annot8.ValidateSecuritySchemes(&spec))
+
+	inference := annot8.DefaultSecurityInferenceConfig()
+	inference.ACLScheme = "SessionAuth"
+	spec = NewTestGenerator().GenerateSpec(r, annot8.Config{
+		Title:             "ACL",
+		Version:           "1.0.0",
+		SecurityInference: &inference,
+		SecuritySchemes: map[string]annot8.SecurityScheme{
+			"SessionAuth": {Type: "apiKey", Name: "session", In: "cookie"},
+		},
+	})
+	AssertDeepEqual(t, []annot8.SecurityRequirement{{"SessionAuth": {}}}, spec.Paths["/reports"].Get.Security)
+	AssertDeepEqual(t, []string{"SessionAuth"}, schemaKeys(spec.Components.SecuritySchemes))
+	if violations := annot8.ValidateSecuritySchemes(&spec); len(violations) != 0 {
+		t.Fatalf("expected no violations with a declared scheme, got %v", violations)
+	}
+}
@@ -9,3 +9,9 @@ func RequireTerminal() func(http.Handler) http.Handler {
 func IsTenant(next http.Handler) http.Handler { return next }
 
 func RequireDualIdentity(next http.Handler) http.Handler { return next }
+
+func NotIsTenantRedirect(next http.Handler) http.Handler { return next }
+
+func GatewayAuth(next http.Handler) http.Handler { return next }
+
+func OptionalAuth(next http.Handler) http.Handler { return next }
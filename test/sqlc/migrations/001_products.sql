@@ -0,0 +1,23 @@
+-- +goose Up
+CREATE TABLE products (
+    id          BIGSERIAL PRIMARY KEY,
+    sku         VARCHAR(32) NOT NULL UNIQUE,
+    name        character varying(120) NOT NULL,
+    description TEXT,
+    status      TEXT NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'active', 'retired')),
+    price       NUMERIC(10, 2) NOT NULL,
+    stock       INTEGER NOT NULL DEFAULT 0,
+    rating      INTEGER NOT NULL,
+    code        VARCHAR(16) NOT NULL,
+    obsolete    TEXT,
+    created_at  TIMESTAMPTZ,
+    CONSTRAINT products_stock_check CHECK ((stock >= 0)),
+    CONSTRAINT products_rating_check CHECK (rating BETWEEN 1 AND 5)
+);
+
+COMMENT ON TABLE products IS 'Items offered in the catalog';
+COMMENT ON COLUMN products.sku IS 'Stock keeping unit';
+COMMENT ON COLUMN public.products.name IS 'Display name; it''s shown to customers';
+
+-- +goose Down
+DROP TABLE products;
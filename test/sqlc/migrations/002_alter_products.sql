@@ -0,0 +1,19 @@
+-- +goose Up
+ALTER TABLE products
+    ALTER COLUMN created_at SET NOT NULL,
+    DROP COLUMN obsolete;
+
+-- pg_dump spelling of an enum-like check.
+ALTER TABLE ONLY public.products
+    ADD COLUMN channel character varying(10) DEFAULT 'web'::character varying NOT NULL,
+    ADD CONSTRAINT products_channel_check CHECK (((channel)::text = ANY ((ARRAY['web'::character varying, 'pos'::character varying])::text[])));
+
+CREATE SCHEMA inventory;
+
+CREATE TABLE inventory.stock_levels (
+    product_id BIGINT NOT NULL REFERENCES products (id),
+    quantity   INTEGER NOT NULL CHECK (quantity > 0 AND quantity < 10000)
+);
+
+-- +goose Down
+ALTER TABLE products ALTER COLUMN created_at DROP NOT NULL;
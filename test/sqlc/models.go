@@ -0,0 +1,34 @@
+package sqlc
+
+import "time"
+
+// Product is the model sqlc generates for the products table; its
+// constraints live in migrations/ and are read through sqlc.yaml.
+type Product struct {
+	ID          int64      `json:"id"`
+	Sku         string     `json:"sku"`
+	Name        string     `json:"name"`
+	Description *string    `json:"description"`
+	Status      string     `json:"status"`
+	Price       float64    `json:"price"`
+	Stock       int32      `json:"stock"`
+	Rating      int32      `json:"rating"`
+	Code        string     `json:"code"     openapi:"maxLength=8"`
+	CreatedAt   *time.Time `json:"created_at"`
+	Channel     string     `json:"channel"`
+}
+
+// InventoryStockLevel is the model of inventory.stock_levels.
+type InventoryStockLevel struct {
+	ProductID int64 `json:"product_id"`
+	Quantity  int32 `json:"quantity"`
+}
+
+// Shipment is generated with the settings of overrides/sqlc.yaml: camelCase
+// JSON tags and the url column renamed to Link.
+type Shipment struct {
+	ID           string  `json:"id"`
+	TrackingCode string  `json:"trackingCode"`
+	Link         *string `json:"url"`
+	ShippedOn    *string `json:"shippedOn"`
+}
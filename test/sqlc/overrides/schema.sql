@@ -0,0 +1,7 @@
+CREATE TABLE shipments (
+    id            UUID PRIMARY KEY,
+    tracking_code VARCHAR(24) NOT NULL,
+    url           TEXT,
+    shipped_on    DATE
+);
+COMMENT ON COLUMN shipments.url IS 'Carrier tracking page';
@@ -0,0 +1,208 @@
+package annot8fixtures_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AxelTahmid/annot8"
+)
+
+func sqlcSchemas(t *testing.T) map[string]annot8.Schema {
+	t.Helper()
+	sg := NewTestSchemaGenerator()
+	if err := sg.LoadSQLCConfig("sqlc/sqlc.yaml"); err != nil {
+		t.Fatalf("LoadSQLCConfig: %v", err)
+	}
+	sg.GenerateSchema("sqlc.Product")
+	sg.GenerateSchema("sqlc.InventoryStockLevel")
+	return sg.GetSchemas()
+}
+
+func TestSQLC_ColumnConstraints(t *testing.T) {
+	product := FindSchemaBySuffix(t, sqlcSchemas(t), "sqlc.Product")
+	props := product.Properties
+
+	AssertEqual(t, "Items offered in the catalog", product.Description)
+
+	// varchar(n) and character varying(n) lengths, COMMENT ON COLUMN text.
+	AssertEqual(t, 32, *props["sku"].MaxLength)
+	AssertEqual(t, "Stock keeping unit", props["sku"].Description)
+	AssertEqual(t, 120, *props["name"].MaxLength)
+	AssertEqual(t, "Display name; it's shown to customers", props["name"].Description)
+
+	// CHECK (status IN (...)), and the = ANY (ARRAY[...]) form pg_dump writes.
+	AssertDeepEqual(t, []any{"draft", "active", "retired"}, props["status"].Enum)
+	AssertDeepEqual(t, []any{"web", "pos"}, props["channel"].Enum)
+	AssertEqual(t, 10, *props["channel"].MaxLength)
+
+	// numeric(10, 2) bounds the integer digits.
+	AssertEqual(t, 1e8, *props["price"].ExclusiveMaximum)
+	AssertEqual(t, -1e8, *props["price"].ExclusiveMinimum)
+
+	// Table CHECK constraints: a comparison and BETWEEN.
+	AssertEqual(t, 0.0, *props["stock"].Minimum)
+	AssertEqual(t, 1.0, *props["rating"].Minimum)
+	AssertEqual(t, 5.0, *props["rating"].Maximum)
+
+	// Struct tags still win over the DDL.
+	AssertEqual(t, 8, *props["code"].MaxLength)
+}
+
+func TestSQLC_Nullability(t *testing.T) {
+	props := FindSchemaBySuffix(t, sqlcSchemas(t), "sqlc.Product").Properties
+
+	// Nullable column: the pointer stays nullable.
+	AssertDeepEqual(t, []string{"string", "null"}, props["description"].Type)
+	// SET NOT NULL in a later migration removes null from the pointer type.
+	AssertEqual(t, "string", props["created_at"].Type)
+	AssertEqual(t, "date-time", props["created_at"].Format)
+	AssertEqual(t, "RFC3339 date-time", props["created_at"].Description)
+}
+
+func TestSQLC_MigrationsAndSchemas(t *testing.T) {
+	level := FindSchemaBySuffix(t, sqlcSchemas(t), "sqlc.InventoryStockLevel")
+
+	// inventory.stock_levels maps to InventoryStockLevel; both bounds of
+	// an AND condition apply.
+	quantity := level.Properties["quantity"]
+	AssertEqual(t, 0.0, *quantity.ExclusiveMinimum)
+	AssertEqual(t, 10000.0, *quantity.ExclusiveMaximum)
+}
+
+func TestSQLC_NoConfig(t *testing.T) {
+	sg := NewTestSchemaGenerator()
+	sg.GenerateSchema("sqlc.Product")
+	props := FindSchemaBySuffix(t, sg.GetSchemas(), "sqlc.Product").Properties
+	if props["sku"].MaxLength != nil {
+		t.Fatal("constraints should only be read from a loaded sqlc config")
+	}
+
+	if err := sg.LoadSQLCConfig("sqlc/missing.yaml"); err == nil {
+		t.Fatal("expected an error for a missing config")
+	}
+}
+
+func sqlcOverrideGenerator(t *testing.T) *annot8.SchemaGenerator {
+	t.Helper()
+	sg := NewTestSchemaGenerator()
+	if err := sg.LoadSQLCConfig("sqlc/overrides/sqlc.yaml"); err != nil {
+		t.Fatalf("LoadSQLCConfig: %v", err)
+	}
+	return sg
+}
+
+func TestSQLC_OverridesMapTypes(t *testing.T) {
+	sg := sqlcOverrideGenerator(t)
+
+	// go_type overrides found in the preset catalog use the preset schema.
+	id := sg.GenerateSchema("github.com/google/uuid.UUID")
+	AssertEqual(t, "uuid", id.Format)
+	amount := sg.GenerateSchema("github.com/shopspring/decimal.Decimal")
+	AssertEqual(t, "string", amount.Type)
+	AssertEqual(t, `^-?[0-9]+(\.[0-9]+)?$`, amount.Pattern)
+
+	// Other go_types are described by the overridden database type, or by
+	// the column's type for column overrides; both are written as flow
+	// mappings in the fixture.
+	span := sg.GenerateSchema("example.com/logistics/span.Duration")
+	AssertEqual(t, "duration", span.Format)
+	code := sg.GenerateSchema("example.com/logistics/tracking.Code")
+	AssertEqual(t, "string", code.Type)
+
+	// sql_package pgx/v5 brings the pgtype nullable wrappers.
+	text := sg.GenerateSchema("github.com/jackc/pgx/v5/pgtype.Text")
+	AssertDeepEqual(t, []string{"string", "null"}, text.Type)
+}
+
+func TestSQLC_OverridesKeepExplicitMappings(t *testing.T) {
+	sg := NewTestSchemaGenerator()
+	sg.GenerateSchema("time.Time")
+	if err := sg.LoadSQLCConfig("sqlc/overrides/sqlc.yaml"); err != nil {
+		t.Fatalf("LoadSQLCConfig: %v", err)
+	}
+	// time.Time was configured by the test helpers; it is not replaced.
+	AssertEqual(t, "RFC3339 date-time", sg.GenerateSchema("time.Time").Description)
+}
+
+func TestSQLC_RenameAndJSONCaseStyle(t *testing.T) {
+	sg := sqlcOverrideGenerator(t)
+	sg.GenerateSchema("sqlc.Shipment")
+	props := FindSchemaBySuffix(t, sg.GetSchemas(), "sqlc.Shipment").Properties
+
+	// uuid and date columns generated as strings take the type's format.
+	AssertEqual(t, "uuid", props["id"].Format)
+	AssertEqual(t, "date", props["shippedOn"].Format)
+	// camelCase JSON tags still find their columns.
+	AssertEqual(t, 24, *props["trackingCode"].MaxLength)
+	// rename: url -> Link; the pointer stays nullable for a NULL column.
+	AssertEqual(t, "Carrier tracking page", props["url"].Description)
+	AssertDeepEqual(t, []string{"string", "null"}, props["url"].Type)
+}
+
+func TestSQLC_VersionOneJSONConfig(t *testing.T) {
+	sg := NewTestSchemaGenerator()
+	if err := sg.LoadSQLCConfig("sqlc/v1/sqlc.json"); err != nil {
+		t.Fatalf("LoadSQLCConfig: %v", err)
+	}
+	sg.GenerateSchema("sqlc.Product")
+	props := FindSchemaBySuffix(t, sg.GetSchemas(), "sqlc.Product").Properties
+	AssertEqual(t, 32, *props["sku"].MaxLength)
+}
+
+func TestSQLC_SharedYAMLConfig(t *testing.T) {
+	// The fixture is written the way sqlc projects write their configs:
+	// anchors, merge keys, block scalars and flow mappings.
+	sg := NewTestSchemaGenerator()
+	if err := sg.LoadSQLCConfig("sqlc/shared/sqlc.yaml"); err != nil {
+		t.Fatalf("LoadSQLCConfig: %v", err)
+	}
+
+	sg.GenerateSchema("sqlc.Product")
+	props := FindSchemaBySuffix(t, sg.GetSchemas(), "sqlc.Product").Properties
+	AssertEqual(t, 32, *props["sku"].MaxLength)
+	AssertDeepEqual(t, []any{"draft", "active", "retired"}, props["status"].Enum)
+
+	AssertEqual(t, "uuid", sg.GenerateSchema("github.com/google/uuid.UUID").Format)
+	AssertEqual(t, `^-?[0-9]+(\.[0-9]+)?$`, sg.GenerateSchema("github.com/shopspring/decimal.Decimal").Pattern)
+	AssertDeepEqual(t, []string{"string", "null"}, sg.GenerateSchema("github.com/jackc/pgx/v5/pgtype.Text").Type)
+}
+
+func TestSQLC_InvalidYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sqlc.yaml")
+	if err := os.WriteFile(path, []byte("version: \"2\"\nsql:\n  - engine: postgresql\n   schema: [\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	err := NewTestSchemaGenerator().LoadSQLCConfig(path)
+	if err == nil {
+		t.Fatal("expected an error for invalid YAML")
+	}
+	if !strings.Contains(err.Error(), path) || !strings.Contains(err.Error(), "line") {
+		t.Fatalf("expected the error to name %s and a line, got %v", path, err)
+	}
+}
+
+func TestSQLC_TruncatedStatements(t *testing.T) {
+	out, err := filepath.Abs("sqlc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	config := "version: \"2\"\nsql:\n  - engine: postgresql\n    schema: schema.sql\n    gen:\n      go:\n        package: sqlc\n        out: " + out + "\n"
+	schema := "CREATE TABLE products (sku VARCHAR(32) NOT NULL);\nCREATE TEMP;\nCREATE TABLE IF NOT EXISTS;\nCREATE OR REPLACE"
+	if err := os.WriteFile(filepath.Join(dir, "sqlc.yaml"), []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "schema.sql"), []byte(schema), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	sg := NewTestSchemaGenerator()
+	if err := sg.LoadSQLCConfig(filepath.Join(dir, "sqlc.yaml")); err != nil {
+		t.Fatalf("LoadSQLCConfig: %v", err)
+	}
+	sg.GenerateSchema("sqlc.Product")
+	props := FindSchemaBySuffix(t, sg.GetSchemas(), "sqlc.Product").Properties
+	AssertEqual(t, 32, *props["sku"].MaxLength)
+}
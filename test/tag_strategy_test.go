@@ -0,0 +1,120 @@
+package annot8fixtures_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/AxelTahmid/annot8"
+)
+
+type OrderHandler struct{}
+
+func (h *OrderHandler) List(w http.ResponseWriter, r *http.Request) {}
+
+type CategoryController struct{}
+
+func (c *CategoryController) Get(w http.ResponseWriter, r *http.Request) {}
+
+func tagStrategySpec(configure func(g *annot8.Generator)) annot8.Spec {
+	r := chi.NewRouter()
+	stub := func(w http.ResponseWriter, r *http.Request) {}
+	orders := &OrderHandler{}
+	categories := &CategoryController{}
+
+	r.Route("/api/v2", func(r chi.Router) {
+		r.Route("/orders", func(r chi.Router) {
+			r.Get("/", orders.List)
+			r.Get("/{id}/items", stub)
+		})
+		r.Route("/admin/users", func(r chi.Router) {
+			r.Get("/", stub)
+		})
+		r.Get("/catalog/categories/{id}", categories.Get)
+	})
+	r.Get("/health", stub)
+
+	g := NewTestGenerator()
+	if configure != nil {
+		configure(g)
+	}
+	return g.GenerateSpec(r, annot8.Config{Title: "Tags", Version: "1.0.0"})
+}
+
+func TestTagStrategy_RouteSkipsVersions(t *testing.T) {
+	spec := tagStrategySpec(nil)
+
+	AssertDeepEqual(t, []string{"orders"}, spec.Paths["/api/v2/orders"].Get.Tags)
+	AssertDeepEqual(t, []string{"orders"}, spec.Paths["/api/v2/orders/{id}/items"].Get.Tags)
+	AssertDeepEqual(t, []string{"admin"}, spec.Paths["/api/v2/admin/users"].Get.Tags)
+	AssertDeepEqual(t, []string{"health"}, spec.Paths["/health"].Get.Tags)
+	if spec.TagGroups != nil {
+		t.Fatalf("expected no tag groups by default, got %+v", spec.TagGroups)
+	}
+}
+
+func TestTagStrategy_Builtin(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy annot8.TagStrategy
+		path     string
+		want     []string
+	}{
+		{"mount", annot8.TagByMount, "/api/v2/admin/users", []string{"users"}},
+		{"mount of a nested route", annot8.TagByMount, "/api/v2/orders/{id}/items", []string{"orders"}},
+		{"mount falls back to the route", annot8.TagByMount, "/health", []string{"health"}},
+		{"receiver", annot8.TagByReceiver, "/api/v2/orders", []string{"Orders"}},
+		{"receiver without Controller", annot8.TagByReceiver, "/api/v2/catalog/categories/{id}", []string{"Categories"}},
+		{"receiver falls back to the route", annot8.TagByReceiver, "/health", []string{"health"}},
+		{"package", annot8.TagByPackage, "/api/v2/orders", []string{"test_test"}},
+		{"custom", func(ctx annot8.TagContext) []string {
+			if strings.HasPrefix(ctx.Route, "/api/v2/admin") {
+				return []string{"Administration"}
+			}
+			return nil
+		}, "/api/v2/admin/users", []string{"Administration"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := tagStrategySpec(func(g *annot8.Generator) { g.SetTagStrategy(tt.strategy) })
+			op := spec.Paths[tt.path].Get
+			if op == nil {
+				t.Fatalf("expected GET %s, got %v", tt.path, schemaKeys(spec.Paths))
+			}
+			AssertDeepEqual(t, tt.want, op.Tags)
+		})
+	}
+}
+
+func TestTagStrategy_Groups(t *testing.T) {
+	spec := tagStrategySpec(func(g *annot8.Generator) {
+		g.SetTagStrategy(annot8.TagByMount)
+		g.SetTagGroups(annot8.TagGroups{
+			Groups: []annot8.TagGroup{{Name: "Sales", Tags: []string{"orders", "invoices"}}},
+			Assign: func(ctx annot8.TagContext, tag string) string {
+				if strings.Contains(ctx.Mount, "/admin") {
+					return "Administration"
+				}
+				return ""
+			},
+			Ungrouped: "Other",
+		})
+	})
+
+	AssertDeepEqual(t, []annot8.TagGroup{
+		{Name: "Sales", Tags: []string{"orders"}},
+		{Name: "Administration", Tags: []string{"users"}},
+		{Name: "Other", Tags: []string{"catalog", "health"}},
+	}, spec.TagGroups)
+
+	raw, err := json.Marshal(spec)
+	if err != nil {
+		t.Fatalf("marshal spec: %v", err)
+	}
+	if !strings.Contains(string(raw), `"x-tagGroups":[{"name":"Sales","tags":["orders"]}`) {
+		t.Fatalf("expected x-tagGroups in the spec, got %s", raw)
+	}
+}
@@ -0,0 +1,39 @@
+package annot8fixtures
+
+// ClosedAccount is closed only when the generator option is set.
+type ClosedAccount struct {
+	ID    string `json:"id"`
+	Email string `json:"email"`
+}
+
+// ClosedBase is embedded by ClosedMember.
+type ClosedBase struct {
+	ID string `json:"id"`
+}
+
+// ClosedMember embeds ClosedBase next to its own fields.
+type ClosedMember struct {
+	ClosedBase
+	Name string `json:"name"`
+}
+
+// ClosedSettings opts out of the generator option.
+//
+// @open
+type ClosedSettings struct {
+	Theme string `json:"theme"`
+}
+
+// ClosedStrict is closed without the generator option.
+//
+// @closed
+type ClosedStrict struct {
+	Code string `json:"code"`
+}
+
+// ClosedHolder overrides the closure of the objects its fields hold.
+type ClosedHolder struct {
+	Account  ClosedAccount    `json:"account"  openapi:"closed=false"`
+	Settings *ClosedSettings  `json:"settings" openapi:"closed=true"`
+	History  []ClosedSettings `json:"history"  openapi:"closed=true"`
+}
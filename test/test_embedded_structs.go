@@ -0,0 +1,98 @@
+package annot8fixtures
+
+import billing "github.com/AxelTahmid/annot8/test/billing/models"
+
+// EmbedBase is embedded by the other Embed* fixtures.
+type EmbedBase struct {
+	ID        int    `json:"id"`
+	CreatedAt string `json:"created_at"`
+}
+
+// EmbedLeft and EmbedRight both declare "Name" at the same depth.
+type EmbedLeft struct {
+	Name string
+	Left bool `json:"left"`
+}
+
+// EmbedRight conflicts with EmbedLeft on "Name".
+type EmbedRight struct {
+	Name  string
+	Right bool `json:"right"`
+}
+
+// EmbedTaggedName declares the key "Name" through a tag.
+type EmbedTaggedName struct {
+	Label int `json:"Name"`
+}
+
+// EmbedPlainName declares the key "Name" without a tag.
+type EmbedPlainName struct {
+	Name string
+}
+
+type embedHidden struct {
+	Secret string `json:"secret"`
+}
+
+// EmbedCode is a non-struct type that marshals as a member named EmbedCode.
+type EmbedCode string
+
+// EmbedPromoted promotes the fields of EmbedBase.
+type EmbedPromoted struct {
+	EmbedBase
+	Title string `json:"title"`
+}
+
+// EmbedShadow shadows EmbedBase.ID with a shallower field.
+type EmbedShadow struct {
+	EmbedBase
+	ID string `json:"id"`
+}
+
+// EmbedDeep shadows a field promoted two levels down.
+type EmbedDeep struct {
+	EmbedPromoted
+	CreatedAt int64 `json:"created_at"`
+}
+
+// EmbedConflict loses "Name": both embeds declare it at the same depth.
+type EmbedConflict struct {
+	EmbedLeft
+	EmbedRight
+}
+
+// EmbedTaggedWins keeps the tagged "Name" over the untagged one.
+type EmbedTaggedWins struct {
+	EmbedTaggedName
+	EmbedPlainName
+}
+
+// EmbedNamed embeds EmbedBase under an explicit name.
+type EmbedNamed struct {
+	EmbedBase `json:"base"`
+	Title     string `json:"title"`
+}
+
+// EmbedPointer promotes fields through a pointer; they are optional.
+type EmbedPointer struct {
+	*EmbedBase
+	Title string `json:"title"`
+}
+
+// EmbedUnexported promotes the exported fields of an unexported struct.
+type EmbedUnexported struct {
+	embedHidden
+	Title string `json:"title"`
+}
+
+// EmbedWithCode embeds a non-struct named type.
+type EmbedWithCode struct {
+	EmbedCode
+	Title string `json:"title"`
+}
+
+// EmbedForeign promotes the fields of a struct from another package.
+type EmbedForeign struct {
+	billing.Invoice
+	Paid bool `json:"paid"`
+}
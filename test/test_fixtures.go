@@ -113,3 +113,36 @@ func (TestTimestampJSONMarshaler) MarshalJSON() ([]byte, error) {
 func (*TestTimestampJSONMarshaler) UnmarshalJSON([]byte) error {
 	return nil
 }
+
+// TagTypedValues carries example, default and enum tag values of every type.
+type TagTypedValues struct {
+	Limit    int               `json:"limit"    openapi:"default=10,example=25,enum=10|25|50"`
+	Ratio    float64           `json:"ratio"    openapi:"default=0.5"`
+	Active   *bool             `json:"active"   openapi:"default=true,example=null"`
+	Tags     []string          `json:"tags"     openapi:"example=[red,green]"`
+	Sizes    []int             `json:"sizes"    openapi:"default=[1,2,3]"`
+	Meta     map[string]int    `json:"meta"     openapi:"example={\"a\":1,\"b\":2}"`
+	Name     string            `json:"name"     openapi:"example=10,default=anonymous"`
+	Extra    any               `json:"extra"    openapi:"example={\"nested\":[1,true]}"`
+	Broken   int               `json:"broken"   openapi:"default=ten,enum=1|two"`
+	Mismatch []int             `json:"mismatch" openapi:"example=[1,x]"`
+	Labels   map[string]string `json:"labels"   openapi:"default=none"`
+}
+
+// TagSchemaKeywords exercises the JSON Schema 2020-12 keywords of the openapi tag.
+type TagSchemaKeywords struct {
+	Amount   float64           `json:"amount"   openapi:"multipleOf=0.01,const=9.99,$comment=fixed price"`
+	Scores   []int             `json:"scores"   openapi:"contains={\"minimum\":90},minContains=1,maxContains=3"`
+	Pair     []any             `json:"pair"     openapi:"prefixItems=[string,{\"type\":\"integer\"}]"`
+	Headers  map[string]string `json:"headers"  openapi:"patternProperties={\"^x-\":\"string\"},propertyNames={\"pattern\":\"^[a-z-]+$\"},minProperties=1,maxProperties=8"`
+	Settings map[string]any    `json:"settings" openapi:"unevaluatedProperties=false,dependentRequired={\"card\":[\"cvv\"]},dependentSchemas={\"card\":{\"required\":[\"expiry\"]}}"`
+	Mode     map[string]any    `json:"mode"     openapi:"if={\"required\":[\"fast\"]},then={\"maxProperties\":1},else=object"`
+	Avatar   string            `json:"avatar"   openapi:"contentMediaType=image/png,contentEncoding=base64,$id=urn:avatar"`
+	Bad      []any             `json:"bad"      openapi:"contains=thing,prefixItems=string"`
+}
+
+// Page is a generic response page.
+type Page[T any] struct {
+	Items []T    `json:"items"`
+	Next  string `json:"next,omitempty"`
+}
@@ -0,0 +1,16 @@
+package annot8fixtures
+
+import (
+	billing "github.com/AxelTahmid/annot8/test/billing/models"
+	order "github.com/AxelTahmid/annot8/test/billing/models"
+	"github.com/AxelTahmid/annot8/test/catalog/models"
+)
+
+// CartLine references two same-named types from different import paths.
+// The order alias deliberately shadows the test/order package name.
+type CartLine struct {
+	Product models.Item     `json:"product"`
+	Charge  billing.Item    `json:"charge"`
+	Billed  order.Item      `json:"billed"`
+	Invoice billing.Invoice `json:"invoice"`
+}
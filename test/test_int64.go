@@ -0,0 +1,47 @@
+package annot8fixtures
+
+import "strconv"
+
+// OrderID is a database key emitted as a JSON number whatever the generator
+// default is.
+//
+// @int64 integer
+type OrderID int64
+
+// Int64Wire mixes 64-bit integers with and without representation overrides.
+type Int64Wire struct {
+	ID       OrderID  `json:"id"`
+	Balance  int64    `json:"balance"`
+	Quoted   int64    `json:"quoted,string"`
+	Serial   uint64   `json:"serial"   openapi:"int64=number"`
+	Optional *int64   `json:"optional,omitempty"`
+	History  []uint64 `json:"history"`
+}
+
+// Int64Query is expanded into query parameters.
+type Int64Query struct {
+	AfterID int64  `json:"after_id"`
+	Cursor  uint64 `json:"cursor"   openapi:"int64=string"`
+}
+
+// Snowflake is a 64-bit ID written by its own JSON marshaler.
+type Snowflake int64
+
+func (s Snowflake) MarshalJSON() ([]byte, error) {
+	return strconv.AppendInt(nil, int64(s), 10), nil
+}
+
+// LedgerID is always quoted by its marshaler.
+//
+// @int64 string
+type LedgerID int64
+
+func (l LedgerID) MarshalJSON() ([]byte, error) {
+	return strconv.AppendQuote(nil, strconv.FormatInt(int64(l), 10)), nil
+}
+
+// Int64Marshalers holds 64-bit integers with custom JSON encodings.
+type Int64Marshalers struct {
+	ID     Snowflake `json:"id"`
+	Ledger LedgerID  `json:"ledger"`
+}
@@ -0,0 +1,27 @@
+package annot8fixtures
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// JSONFidelity exercises encoding/json behaviors that differ from a naive
+// reading of the Go types.
+type JSONFidelity struct {
+	Count    int64              `json:"count,string"`
+	Ratio    float64            `json:"ratio,string"`
+	Enabled  bool               `json:"enabled,string"`
+	Limit    *int               `json:"limit,string"`
+	Total    Cents              `json:"total,string"`
+	Payload  []byte             `json:"payload"`
+	Checksum [4]byte            `json:"checksum"`
+	Point    [2]float64         `json:"point"`
+	Raw      json.RawMessage    `json:"raw"`
+	Extra    any                `json:"extra"`
+	Meta     map[string]any     `json:"meta"`
+	Amount   json.Number        `json:"amount"`
+	Timeout  time.Duration      `json:"timeout"`
+	Scores   map[int]string     `json:"scores"`
+	Counters map[uint16]int     `json:"counters"`
+	ByStatus map[StatusEnum]int `json:"by_status"`
+}
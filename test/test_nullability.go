@@ -0,0 +1,11 @@
+package annot8fixtures
+
+import "time"
+
+// NullableFields holds each kind of nullable member.
+type NullableFields struct {
+	Nickname *string          `json:"nickname"`
+	Seen     *time.Time       `json:"seen"`
+	Profile  *VariantSettings `json:"profile"`
+	Tags     *[]string        `json:"tags"`
+}
@@ -0,0 +1,24 @@
+package annot8fixtures
+
+import (
+	"database/sql"
+	"net/netip"
+	"net/url"
+	"time"
+)
+
+// StdLibTypes uses every standard library type covered by Presets.StdLib.
+type StdLibTypes struct {
+	CreatedAt time.Time       `json:"created_at"`
+	DeletedAt *time.Time      `json:"deleted_at"`
+	Nickname  sql.NullString  `json:"nickname"`
+	Visits    sql.NullInt64   `json:"visits"`
+	Score     sql.NullFloat64 `json:"score"`
+	Active    sql.NullBool    `json:"active"`
+	SeenAt    sql.NullTime    `json:"seen_at"`
+	Addr      netip.Addr      `json:"addr"`
+	Network   netip.Prefix    `json:"network"`
+	Endpoint  netip.AddrPort  `json:"endpoint"`
+	Homepage  url.URL         `json:"homepage"`
+	Callback  *url.URL        `json:"callback"`
+}
@@ -0,0 +1,24 @@
+package annot8fixtures
+
+import "time"
+
+// VariantAccount mixes server-generated and secret fields.
+type VariantAccount struct {
+	ID        string          `json:"id"         openapi:"readOnly=true"`
+	Email     string          `json:"email"`
+	Password  string          `json:"password"   openapi:"writeOnly=true"`
+	CreatedAt time.Time       `json:"created_at" openapi:"readOnly=true"`
+	Profile   VariantProfile  `json:"profile"`
+	Settings  VariantSettings `json:"settings"`
+}
+
+// VariantProfile is nested and has its own readOnly field.
+type VariantProfile struct {
+	DisplayName string `json:"display_name"`
+	Verified    bool   `json:"verified"     openapi:"readOnly=true"`
+}
+
+// VariantSettings has neither readOnly nor writeOnly fields.
+type VariantSettings struct {
+	Theme string `json:"theme"`
+}
@@ -0,0 +1,40 @@
+package annot8fixtures
+
+import (
+	"time"
+
+	billing "github.com/AxelTahmid/annot8/test/billing/models"
+)
+
+// Cents is a defined type; it becomes a named component.
+type Cents int64
+
+// Amount is an alias of Cents and must not become a component of its own.
+type Amount = Cents
+
+// Timestamp aliases an external type and resolves to its mapping.
+type Timestamp = time.Time
+
+// Tags aliases a composite type and is inlined where used.
+type Tags = []string
+
+// LineItems is a named slice whose items reference another package's type.
+type LineItems []billing.Item
+
+// Labels is a named map.
+type Labels map[string]string
+
+// LedgerEntry is defined over a struct from another package and takes its fields.
+type LedgerEntry billing.Invoice
+
+// Ledger uses every flavour of named and aliased type.
+type Ledger struct {
+	Total     Cents       `json:"total"`
+	Balance   Amount      `json:"balance"`
+	PostedAt  Timestamp   `json:"posted_at"`
+	UpdatedAt *Timestamp  `json:"updated_at,omitempty"`
+	Tags      Tags        `json:"tags"`
+	Lines     LineItems   `json:"lines"`
+	Labels    Labels      `json:"labels"`
+	Entry     LedgerEntry `json:"entry"`
+}
@@ -0,0 +1,31 @@
+package annot8fixtures
+
+// ValidatedSignup exercises go-playground/validator rule translation.
+type ValidatedSignup struct {
+	Username string            `json:"username"           validate:"required,alphanum,startswith=u_,gt=3,lt=21"`
+	Phone    string            `json:"phone,omitempty"    validate:"omitempty,e164"`
+	Host     string            `json:"host,omitempty"     validate:"hostname"`
+	Addr     string            `json:"addr,omitempty"     validate:"ip"`
+	Network  string            `json:"network,omitempty"  validate:"cidr"`
+	Birthday string            `json:"birthday,omitempty" validate:"datetime=2006-01-02"`
+	Slot     string            `json:"slot,omitempty"     validate:"datetime=15:04"`
+	Age      int               `json:"age"                validate:"gte=18,lt=130,ne=99"`
+	Tags     []string          `json:"tags"               validate:"max=5,dive,min=2,endswith=!"`
+	Limits   map[string]int    `json:"limits"             validate:"min=1,max=8,dive,keys,alpha,endkeys,gt=0"`
+	Labels   map[string]string `json:"labels"             validate:"len=2"`
+	Channel  string            `json:"channel,omitempty"  validate:"oneof=sms email"`
+	Contact  string            `json:"contact,omitempty"  validate:"required_if=Channel sms"`
+	Email    *string           `json:"email,omitempty"    validate:"required_with=Phone,excluded_with=Host"`
+	Referrer *string           `json:"referrer,omitempty" validate:"required"`
+}
+
+// ValidatedEdgeCases exercises rules that collide with nullability, quoted
+// oneof values and rules that cannot be applied.
+type ValidatedEdgeCases struct {
+	Gateway *string           `json:"gateway,omitempty" validate:"omitempty,ip"`
+	Plan    string            `json:"plan"              validate:"oneof='free tier' pro"`
+	Level   int               `json:"level"             validate:"oneof=low high"`
+	Retries int               `json:"retries"           validate:"max=ten"`
+	Members []ValidatedSignup `json:"members"           validate:"max=3,dive,required"`
+	Owners  []ValidatedSignup `json:"owners"            validate:"dive,min=1"`
+}
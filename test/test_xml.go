@@ -0,0 +1,26 @@
+package annot8fixtures
+
+import "encoding/xml"
+
+// XMLInvoice marshals differently with encoding/json and encoding/xml.
+type XMLInvoice struct {
+	XMLName  xml.Name    `xml:"urn:billing invoice"`
+	ID       string      `json:"id"       xml:"id,attr"`
+	Currency string      `json:"currency" xml:"currency,attr,omitempty"`
+	Customer XMLCustomer `json:"customer" xml:"customer"`
+	Lines    []XMLLine   `json:"lines"    xml:"lines>line"`
+	Notes    string      `json:"notes"    xml:"meta>notes,omitempty"`
+	Internal string      `json:"internal" xml:"-"`
+}
+
+// XMLCustomer has no XMLName, so its element is named after the type.
+type XMLCustomer struct {
+	Name string `json:"name" xml:",chardata"`
+	Tier string `json:"tier" xml:"tier,attr"`
+}
+
+// XMLLine is a repeated element.
+type XMLLine struct {
+	SKU      string `json:"sku"      xml:"sku"`
+	Quantity int    `json:"quantity" xml:"qty"`
+}
@@ -0,0 +1,9 @@
+module github.com/AxelTahmid/annot8/test/testdata/presets
+
+go 1.25.0
+
+require (
+	github.com/google/uuid v1.6.0
+	github.com/jackc/pgx/v5 v5.9.2
+	github.com/shopspring/decimal v1.4.0
+)
@@ -0,0 +1,114 @@
+// Command presets records the encoding/json output of the third-party types
+// covered by annot8's presets. The libraries are not dependencies of annot8,
+// so this program lives in its own module; regenerate the samples with
+// go generate in the test directory after bumping them.
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+)
+
+const pgtypePkg = "github.com/jackc/pgx/v5/pgtype."
+
+func main() {
+	if len(os.Args) != 2 {
+		log.Fatal("usage: presets <output file>")
+	}
+
+	when := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	id := uuid.MustParse("550e8400-e29b-41d4-a716-446655440000")
+
+	samples := map[string][]any{
+		pgtypePkg + "Text":   {pgtype.Text{String: "hello", Valid: true}, pgtype.Text{}},
+		pgtypePkg + "Bool":   {pgtype.Bool{Bool: true, Valid: true}, pgtype.Bool{}},
+		pgtypePkg + "Int2":   {pgtype.Int2{Int16: -32768, Valid: true}, pgtype.Int2{}},
+		pgtypePkg + "Int4":   {pgtype.Int4{Int32: 2147483647, Valid: true}, pgtype.Int4{}},
+		pgtypePkg + "Int8":   {pgtype.Int8{Int64: 9007199254740993, Valid: true}, pgtype.Int8{}},
+		pgtypePkg + "Float4": {pgtype.Float4{Float32: 1.5, Valid: true}, pgtype.Float4{}},
+		pgtypePkg + "Float8": {pgtype.Float8{Float64: 1.5, Valid: true}, pgtype.Float8{}},
+		pgtypePkg + "Numeric": {
+			pgtype.Numeric{Int: big.NewInt(12345), Exp: -3, Valid: true},
+			pgtype.Numeric{NaN: true, Valid: true},
+			pgtype.Numeric{InfinityModifier: pgtype.Infinity, Valid: true},
+			pgtype.Numeric{InfinityModifier: pgtype.NegativeInfinity, Valid: true},
+			pgtype.Numeric{},
+		},
+		pgtypePkg + "Date": {
+			pgtype.Date{Time: when, Valid: true},
+			pgtype.Date{InfinityModifier: pgtype.Infinity, Valid: true},
+			pgtype.Date{},
+		},
+		pgtypePkg + "Timestamp": {
+			pgtype.Timestamp{Time: when, Valid: true},
+			pgtype.Timestamp{InfinityModifier: pgtype.NegativeInfinity, Valid: true},
+			pgtype.Timestamp{},
+		},
+		pgtypePkg + "Timestamptz": {
+			pgtype.Timestamptz{Time: when, Valid: true},
+			pgtype.Timestamptz{InfinityModifier: pgtype.Infinity, Valid: true},
+			pgtype.Timestamptz{},
+		},
+		pgtypePkg + "UUID": {pgtype.UUID{Bytes: id, Valid: true}, pgtype.UUID{}},
+		pgtypePkg + "Interval": {
+			pgtype.Interval{Microseconds: 3600000000, Days: 1, Months: 2, Valid: true},
+			pgtype.Interval{},
+		},
+		pgtypePkg + "Range": {
+			pgtype.Range[pgtype.Int4]{
+				Lower:     pgtype.Int4{Int32: 1, Valid: true},
+				Upper:     pgtype.Int4{Int32: 10, Valid: true},
+				LowerType: pgtype.Inclusive,
+				UpperType: pgtype.Exclusive,
+				Valid:     true,
+			},
+			pgtype.Range[pgtype.Date]{
+				Lower:     pgtype.Date{Time: when, Valid: true},
+				LowerType: pgtype.Inclusive,
+				UpperType: pgtype.Unbounded,
+				Valid:     true,
+			},
+			pgtype.Range[pgtype.Int4]{},
+		},
+
+		"github.com/google/uuid.UUID":     {id, uuid.Nil},
+		"github.com/google/uuid.NullUUID": {uuid.NullUUID{UUID: id, Valid: true}, uuid.NullUUID{}},
+
+		"github.com/shopspring/decimal.Decimal": {
+			decimal.RequireFromString("123.45"),
+			decimal.RequireFromString("-0.001"),
+			decimal.RequireFromString("1e3"),
+			decimal.Zero,
+		},
+		"github.com/shopspring/decimal.NullDecimal": {
+			decimal.NewNullDecimal(decimal.RequireFromString("123.45")),
+			decimal.NullDecimal{},
+		},
+	}
+
+	recorded := make(map[string][]json.RawMessage, len(samples))
+	for typeKey, values := range samples {
+		for _, value := range values {
+			raw, err := json.Marshal(value)
+			if err != nil {
+				log.Fatalf("%s: %v", typeKey, err)
+			}
+			recorded[typeKey] = append(recorded[typeKey], raw)
+		}
+	}
+
+	out, err := json.MarshalIndent(recorded, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(os.Args[1], append(out, '\n'), 0o600); err != nil {
+		log.Fatal(err)
+	}
+}
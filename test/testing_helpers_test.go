@@ -1,13 +1,17 @@
 package annot8fixtures_test
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/AxelTahmid/annot8"
 )
@@ -101,3 +105,174 @@ func HasSchemaWithSuffix(schemas map[string]annot8.Schema, suffix string) bool {
 	}
 	return false
 }
+
+// AssertMarshalMatchesSchema marshals value with encoding/json and fails the
+// test if the output does not satisfy schema. Refs resolve against schemas.
+// Only the keywords the generator emits are checked.
+func AssertMarshalMatchesSchema(t *testing.T, schemas map[string]annot8.Schema, schema *annot8.Schema, value any) {
+	t.Helper()
+	raw, err := json.Marshal(value)
+	AssertNoError(t, err)
+	AssertJSONMatchesSchema(t, schemas, schema, raw)
+}
+
+// AssertJSONMatchesSchema fails the test if the JSON document raw does not
+// satisfy schema. Refs resolve against schemas.
+func AssertJSONMatchesSchema(t *testing.T, schemas map[string]annot8.Schema, schema *annot8.Schema, raw []byte) {
+	t.Helper()
+	dec := json.NewDecoder(strings.NewReader(string(raw)))
+	dec.UseNumber()
+	var decoded any
+	AssertNoError(t, dec.Decode(&decoded))
+
+	if problems := schemaMismatches(schemas, schema, decoded, "$"); len(problems) > 0 {
+		t.Fatalf("marshaled %s does not match schema:\n%s", raw, strings.Join(problems, "\n"))
+	}
+}
+
+func schemaMismatches(schemas map[string]annot8.Schema, s *annot8.Schema, v any, path string) []string {
+	if s == nil {
+		return nil
+	}
+	if s.Ref != "" {
+		target, ok := schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+		if !ok {
+			return []string{path + ": unresolved ref " + s.Ref}
+		}
+		return schemaMismatches(schemas, &target, v, path)
+	}
+
+	var problems []string
+	if len(s.AnyOf) > 0 || len(s.OneOf) > 0 {
+		matched := false
+		for _, sub := range append(append([]*annot8.Schema{}, s.AnyOf...), s.OneOf...) {
+			if len(schemaMismatches(schemas, sub, v, path)) == 0 {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			problems = append(problems, path+": no anyOf/oneOf branch matches")
+		}
+	}
+	for _, sub := range s.AllOf {
+		problems = append(problems, schemaMismatches(schemas, sub, v, path)...)
+	}
+
+	if types := schemaTypes(s.Type); len(types) > 0 && !slicesContain(types, jsonTypeOf(v)) &&
+		!(jsonTypeOf(v) == "integer" && slicesContain(types, "number")) {
+		problems = append(problems, fmt.Sprintf("%s: %v is %s, want %v", path, v, jsonTypeOf(v), types))
+	}
+	if len(s.Enum) > 0 && !slicesContain(s.Enum, v) {
+		problems = append(problems, fmt.Sprintf("%s: %v not in enum %v", path, v, s.Enum))
+	}
+
+	switch val := v.(type) {
+	case string:
+		if s.Pattern != "" && !regexp.MustCompile(s.Pattern).MatchString(val) {
+			problems = append(problems, fmt.Sprintf("%s: %q does not match %s", path, val, s.Pattern))
+		}
+		if problem := formatMismatch(s.Format, val); problem != "" {
+			problems = append(problems, fmt.Sprintf("%s: %q is not a %s", path, val, problem))
+		}
+		if s.ContentEncoding == "base64" {
+			if _, err := base64.StdEncoding.DecodeString(val); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %q is not base64", path, val))
+			}
+		}
+	case []any:
+		if s.MinItems != nil && len(val) < *s.MinItems {
+			problems = append(problems, fmt.Sprintf("%s: %d items, want at least %d", path, len(val), *s.MinItems))
+		}
+		if s.MaxItems != nil && len(val) > *s.MaxItems {
+			problems = append(problems, fmt.Sprintf("%s: %d items, want at most %d", path, len(val), *s.MaxItems))
+		}
+		for i, item := range val {
+			problems = append(problems, schemaMismatches(schemas, s.Items, item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := val[name]; !ok {
+				problems = append(problems, path+": missing required "+name)
+			}
+		}
+		for key, item := range val {
+			problems = append(problems, schemaMismatches(schemas, s.PropertyNames, key, path+" key "+key)...)
+			if prop, ok := s.Properties[key]; ok {
+				problems = append(problems, schemaMismatches(schemas, prop, item, path+"."+key)...)
+				continue
+			}
+			if ap, ok := s.AdditionalProperties.(*annot8.Schema); ok {
+				problems = append(problems, schemaMismatches(schemas, ap, item, path+"."+key)...)
+			} else if s.Properties != nil {
+				problems = append(problems, path+": unexpected property "+key)
+			}
+		}
+	}
+	return problems
+}
+
+// formatMismatch returns format when value does not satisfy it. Only the
+// formats presets and marshaler schemas rely on are checked.
+func formatMismatch(format, value string) string {
+	var err error
+	switch format {
+	case "date-time":
+		_, err = time.Parse(time.RFC3339Nano, value)
+	case "date":
+		_, err = time.Parse(time.DateOnly, value)
+	case "uuid":
+		if !regexp.MustCompile(`^[0-9a-fA-F]{8}(-[0-9a-fA-F]{4}){3}-[0-9a-fA-F]{12}$`).MatchString(value) {
+			return format
+		}
+	}
+	if err != nil {
+		return format
+	}
+	return ""
+}
+
+func schemaTypes(t any) []any {
+	switch tt := t.(type) {
+	case string:
+		return []any{tt}
+	case []string:
+		out := make([]any, len(tt))
+		for i, v := range tt {
+			out[i] = v
+		}
+		return out
+	case []any:
+		return tt
+	}
+	return nil
+}
+
+func jsonTypeOf(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if strings.ContainsAny(val.String(), ".eE") {
+			return "number"
+		}
+		return "integer"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func slicesContain(values []any, v any) bool {
+	for _, candidate := range values {
+		if reflect.DeepEqual(candidate, v) {
+			return true
+		}
+	}
+	return false
+}
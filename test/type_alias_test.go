@@ -0,0 +1,76 @@
+package annot8fixtures_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AxelTahmid/annot8"
+)
+
+func TestTypeAliases_LedgerSchema(t *testing.T) {
+	gen := NewTestSchemaGenerator()
+	gen.GenerateSchema("annot8fixtures.Ledger")
+	schemas := gen.GetSchemas()
+
+	ledger, ok := schemas["annot8fixtures.Ledger"]
+	if !ok {
+		t.Fatalf("expected Ledger schema, got keys %v", schemaKeys(schemas))
+	}
+
+	t.Run("aliases are transparent", func(t *testing.T) {
+		AssertEqual(t, "#/components/schemas/annot8fixtures.Cents", ledger.Properties["total"].Ref)
+		AssertEqual(t, "#/components/schemas/annot8fixtures.Cents", ledger.Properties["balance"].Ref)
+		for _, name := range []string{"annot8fixtures.Amount", "annot8fixtures.Timestamp", "annot8fixtures.Tags"} {
+			if _, exists := schemas[name]; exists {
+				t.Errorf("alias %s should not be a component", name)
+			}
+		}
+	})
+
+	t.Run("alias of external type uses its mapping", func(t *testing.T) {
+		posted := ledger.Properties["posted_at"]
+		AssertEqual(t, "date-time", posted.Format)
+		AssertEqual(t, "string", posted.Type.(string))
+
+		updated := ledger.Properties["updated_at"]
+		types, ok := updated.Type.([]any)
+		if !ok || len(types) != 2 || types[1] != "null" {
+			t.Errorf("expected nullable date-time for *Timestamp, got %#v", updated)
+		}
+	})
+
+	t.Run("alias of composite type is inlined", func(t *testing.T) {
+		tags := ledger.Properties["tags"]
+		AssertEqual(t, "array", tags.Type.(string))
+		AssertEqual(t, "string", tags.Items.Type.(string))
+	})
+
+	t.Run("defined types are named components", func(t *testing.T) {
+		cents := schemas["annot8fixtures.Cents"]
+		AssertEqual(t, "int64", cents.Format)
+
+		entry := ledger.Properties["entry"]
+		AssertEqual(t, "#/components/schemas/annot8fixtures.LedgerEntry", entry.Ref)
+		ledgerEntry := schemas["annot8fixtures.LedgerEntry"]
+		if _, ok := ledgerEntry.Properties["number"]; !ok {
+			t.Errorf("LedgerEntry should take the fields of billing Invoice, got %#v", ledgerEntry)
+		}
+	})
+
+	t.Run("named collections reference their elements", func(t *testing.T) {
+		AssertEqual(t, "#/components/schemas/annot8fixtures.LineItems", ledger.Properties["lines"].Ref)
+		lines := schemas["annot8fixtures.LineItems"]
+		AssertEqual(t, "array", lines.Type.(string))
+		if lines.Items == nil || !strings.HasSuffix(lines.Items.Ref, "billing/models.Item") {
+			t.Errorf("expected LineItems items to reference billing Item, got %#v", lines.Items)
+		}
+
+		AssertEqual(t, "#/components/schemas/annot8fixtures.Labels", ledger.Properties["labels"].Ref)
+		labels := schemas["annot8fixtures.Labels"]
+		AssertEqual(t, "object", labels.Type.(string))
+		values, ok := labels.AdditionalProperties.(*annot8.Schema)
+		if !ok || values.Type != "string" {
+			t.Errorf("expected Labels values to be strings, got %#v", labels.AdditionalProperties)
+		}
+	})
+}
@@ -0,0 +1,164 @@
+package annot8fixtures_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/AxelTahmid/annot8"
+)
+
+func floatPtr(v float64) *float64 { return &v }
+
+func TestValidateRules_Keywords(t *testing.T) {
+	gen := NewTestSchemaGenerator()
+	gen.GenerateSchema("annot8fixtures.ValidatedSignup")
+	props := gen.GetSchemas()["annot8fixtures.ValidatedSignup"].Properties
+
+	tests := []struct {
+		field string
+		check func(t *testing.T, s *annot8.Schema)
+	}{
+		{"username", func(t *testing.T, s *annot8.Schema) {
+			AssertEqual(t, "^[a-zA-Z0-9]+$", s.Pattern)
+			if len(s.AllOf) != 1 || s.AllOf[0].Pattern != "^u_" {
+				t.Fatalf("expected startswith as an extra pattern, got %+v", s.AllOf)
+			}
+			AssertEqual(t, 4, *s.MinLength)
+			AssertEqual(t, 20, *s.MaxLength)
+		}},
+		{"phone", func(t *testing.T, s *annot8.Schema) {
+			AssertEqual(t, `^\+[1-9]?[0-9]{7,14}$`, s.Pattern)
+		}},
+		{"host", func(t *testing.T, s *annot8.Schema) {
+			AssertEqual(t, "hostname", s.Format)
+		}},
+		{"addr", func(t *testing.T, s *annot8.Schema) {
+			if len(s.AllOf) != 1 || len(s.AllOf[0].AnyOf) != 2 ||
+				s.AllOf[0].AnyOf[0].Format != "ipv4" || s.AllOf[0].AnyOf[1].Format != "ipv6" {
+				t.Fatalf("expected an ipv4/ipv6 anyOf under allOf, got %+v", s.AllOf)
+			}
+		}},
+		{"network", func(t *testing.T, s *annot8.Schema) {
+			re := regexp.MustCompile(s.Pattern)
+			for _, v := range []string{"10.0.0.0/8", "2001:db8::/32"} {
+				if !re.MatchString(v) {
+					t.Errorf("expected %q to match %s", v, s.Pattern)
+				}
+			}
+			if re.MatchString("10.0.0.0") {
+				t.Errorf("expected prefix length to be required by %s", s.Pattern)
+			}
+		}},
+		{"birthday", func(t *testing.T, s *annot8.Schema) {
+			AssertEqual(t, "date", s.Format)
+		}},
+		{"slot", func(t *testing.T, s *annot8.Schema) {
+			AssertEqual(t, "^[0-9]{2}:[0-9]{2}$", s.Pattern)
+		}},
+		{"age", func(t *testing.T, s *annot8.Schema) {
+			AssertDeepEqual(t, floatPtr(18), s.Minimum)
+			AssertDeepEqual(t, floatPtr(130), s.ExclusiveMaximum)
+			AssertDeepEqual(t, any(float64(99)), s.Not.Const)
+		}},
+		{"tags", func(t *testing.T, s *annot8.Schema) {
+			AssertEqual(t, 5, *s.MaxItems)
+			AssertEqual(t, 2, *s.Items.MinLength)
+			AssertEqual(t, "!$", s.Items.Pattern)
+		}},
+		{"limits", func(t *testing.T, s *annot8.Schema) {
+			AssertEqual(t, 1, *s.MinProperties)
+			AssertEqual(t, 8, *s.MaxProperties)
+			AssertEqual(t, "^[a-zA-Z]+$", s.PropertyNames.Pattern)
+			values := s.AdditionalProperties.(*annot8.Schema)
+			AssertDeepEqual(t, floatPtr(0), values.ExclusiveMinimum)
+		}},
+		{"labels", func(t *testing.T, s *annot8.Schema) {
+			AssertEqual(t, 2, *s.MinProperties)
+			AssertEqual(t, 2, *s.MaxProperties)
+		}},
+		{"channel", func(t *testing.T, s *annot8.Schema) {
+			AssertDeepEqual(t, []any{"sms", "email"}, s.Enum)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			s, ok := props[tt.field]
+			if !ok {
+				t.Fatalf("missing property %q", tt.field)
+			}
+			tt.check(t, s)
+		})
+	}
+}
+
+func TestValidateRules_CrossField(t *testing.T) {
+	gen := NewTestSchemaGenerator()
+	gen.GenerateSchema("annot8fixtures.ValidatedSignup")
+	schema := gen.GetSchemas()["annot8fixtures.ValidatedSignup"]
+
+	AssertDeepEqual(t, map[string][]string{"phone": {"email"}}, schema.DependentRequired)
+	if len(schema.AllOf) != 2 {
+		t.Fatalf("expected two conditional clauses, got %+v", schema.AllOf)
+	}
+
+	excluded := schema.AllOf[1]
+	AssertDeepEqual(t, []string{"host"}, excluded.If.Required)
+	AssertDeepEqual(t, []string{"email"}, excluded.Then.Not.Required)
+
+	requiredIf := schema.AllOf[0]
+	if requiredIf.AllOf != nil {
+		t.Fatalf("unexpected nesting: %+v", requiredIf)
+	}
+	AssertDeepEqual(t, []string{"channel"}, requiredIf.If.Required)
+	AssertDeepEqual(t, any("sms"), requiredIf.If.Properties["channel"].Const)
+	AssertDeepEqual(t, []string{"contact"}, requiredIf.Then.Required)
+}
+
+func TestValidateRules_RequiredPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   annot8.RequiredPolicy
+		required []string
+	}{
+		{"json", annot8.RequiredFromJSON, []string{"username", "age", "tags", "limits", "labels"}},
+		{"validate", annot8.RequiredFromValidate, []string{"username", "referrer"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := NewTestSchemaGenerator()
+			gen.SetRequiredPolicy(tt.policy)
+			gen.GenerateSchema("annot8fixtures.ValidatedSignup")
+			AssertDeepEqual(t, tt.required, gen.GetSchemas()["annot8fixtures.ValidatedSignup"].Required)
+		})
+	}
+}
+
+func TestValidateRules_EdgeCases(t *testing.T) {
+	gen := NewTestSchemaGenerator()
+	gen.SetNullabilityStrategy(annot8.NullAnyOf)
+	gen.GenerateSchema("annot8fixtures.ValidatedEdgeCases")
+	props := gen.GetSchemas()["annot8fixtures.ValidatedEdgeCases"].Properties
+
+	// The nullable anyOf and the ip alternatives stay separate.
+	gateway := props["gateway"]
+	if len(gateway.AnyOf) != 2 || gateway.AnyOf[1].Type != "null" {
+		t.Fatalf("expected a nullable anyOf, got %+v", gateway.AnyOf)
+	}
+	if len(gateway.AllOf) != 1 || len(gateway.AllOf[0].AnyOf) != 2 {
+		t.Fatalf("expected the ip alternatives under allOf, got %+v", gateway.AllOf)
+	}
+
+	AssertDeepEqual(t, []any{"free tier", "pro"}, props["plan"].Enum)
+	if props["level"].Enum != nil {
+		t.Fatalf("expected no enum for non-numeric values, got %v", props["level"].Enum)
+	}
+	AssertEqual(t, 3, *props["members"].MaxItems)
+
+	AssertDeepEqual(t, []string{
+		`annot8fixtures.ValidatedEdgeCases.level: validate oneof=low high value "low" is not a number`,
+		`annot8fixtures.ValidatedEdgeCases.owners: validate dive cannot constrain referenced elements #/components/schemas/annot8fixtures.ValidatedSignup`,
+		`annot8fixtures.ValidatedEdgeCases.retries: validate max=ten bound "ten" is not a number`,
+	}, gen.Diagnostics())
+}
@@ -205,3 +205,17 @@ func TestValidateRefs_NonSchemaComponents(t *testing.T) {
 		t.Fatalf("unexpected violation: %q", violations[0])
 	}
 }
+
+func TestValidateSchemaTags_ReportsUncoercibleValues(t *testing.T) {
+	g := NewTestGenerator()
+	g.GenerateSchema("annot8fixtures.TagTypedValues")
+	spec := g.GenerateSpec(chi.NewRouter(), annot8.Config{Title: "Tags", Version: "1.0.0"})
+
+	violations := annot8.ValidateSchemaTags(&spec)
+	if len(violations) != 4 {
+		t.Fatalf("expected 4 tag value violations, got %v", violations)
+	}
+	if !strings.Contains(violations[0], `default "ten" is not an integer`) {
+		t.Fatalf("unexpected first violation %q", violations[0])
+	}
+}
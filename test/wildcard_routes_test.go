@@ -0,0 +1,68 @@
+package annot8fixtures_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/AxelTahmid/annot8"
+)
+
+func wildcardSpec(name string) annot8.Spec {
+	r := chi.NewRouter()
+	stub := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	r.Get("/static/*", stub)
+	r.Get("/files/{bucket}/*", stub)
+	r.Get("/files/{bucket}/{name}/{version}", stub)
+	r.Get("/files/{bucket}/{name}/meta", stub)
+
+	g := NewTestGenerator()
+	if name != "" {
+		g.SetWildcardParam(name)
+	}
+	return g.GenerateSpec(r, annot8.Config{Title: "Wildcards", Version: "1.0.0"})
+}
+
+func TestWildcardRoutes_NamedParameter(t *testing.T) {
+	spec := wildcardSpec("")
+
+	op := spec.Paths["/static/{path}"].Get
+	if op == nil {
+		t.Fatalf("expected /static/{path}, got %v", schemaKeys(spec.Paths))
+	}
+	AssertEqual(t, "getStaticByPath", op.OperationID)
+	AssertDeepEqual(t, []string{"static"}, op.Tags)
+	if len(op.Parameters) != 1 {
+		t.Fatalf("expected one path parameter, got %+v", op.Parameters)
+	}
+	p := op.Parameters[0]
+	AssertEqual(t, "path", p.Name)
+	AssertEqual(t, "path", p.In)
+	AssertEqual(t, true, p.Required)
+	AssertEqual(t, true, p.AllowReserved)
+	AssertEqual(t, true, p.Wildcard)
+
+	files := spec.Paths["/files/{bucket}/{path}"].Get
+	if files == nil || len(files.Parameters) != 2 {
+		t.Fatalf("expected bucket and path parameters, got %+v", files)
+	}
+}
+
+func TestWildcardRoutes_ConfiguredName(t *testing.T) {
+	spec := wildcardSpec("rest")
+
+	if _, ok := spec.Paths["/static/{rest}"]; !ok {
+		t.Fatalf("expected /static/{rest}, got %v", schemaKeys(spec.Paths))
+	}
+	AssertEqual(t, "rest", spec.Paths["/static/{rest}"].Get.Parameters[0].Name)
+}
+
+func TestWildcardRoutes_Ambiguity(t *testing.T) {
+	spec := wildcardSpec("")
+
+	// The wildcard absorbs {name}/{version}; a static tail segment still wins.
+	AssertDeepEqual(t, []string{
+		`ambiguous paths: "/files/{bucket}/{name}/{version}" and "/files/{bucket}/{path}"`,
+	}, annot8.ValidateAmbiguousPaths(&spec))
+}
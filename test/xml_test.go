@@ -0,0 +1,87 @@
+package annot8fixtures_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/AxelTahmid/annot8"
+)
+
+type xmlHandler struct{}
+
+// @Summary Create invoice
+// @Tags invoices
+// @Accept application/json
+// @Accept application/xml
+// @Produce application/json
+// @Produce application/xml
+// @Param invoice body annot8fixtures.XMLInvoice true "Invoice"
+// @Success 201 {object} annot8fixtures.XMLInvoice "Created"
+func (h *xmlHandler) create(w http.ResponseWriter, r *http.Request) {}
+
+func xmlSpec(t *testing.T) annot8.Spec {
+	t.Helper()
+	r := chi.NewRouter()
+	r.Post("/invoices", http.HandlerFunc((&xmlHandler{}).create))
+	return NewTestGenerator().GenerateSpec(r, annot8.Config{Title: "XML", Version: "1.0.0"})
+}
+
+func TestXML_ContentPerMediaType(t *testing.T) {
+	spec := xmlSpec(t)
+	op := spec.Paths["/invoices"].Post
+
+	body := op.RequestBody.Content
+	AssertEqual(t, "#/components/schemas/annot8fixtures.XMLInvoice", body["application/json"].Schema.Ref)
+	AssertEqual(t, "#/components/schemas/annot8fixtures.XMLInvoiceXML", body["application/xml"].Schema.Ref)
+
+	created := op.Responses["201"].Content
+	AssertEqual(t, "#/components/schemas/annot8fixtures.XMLInvoice", created["application/json"].Schema.Ref)
+	AssertEqual(t, "#/components/schemas/annot8fixtures.XMLInvoiceXML", created["application/xml"].Schema.Ref)
+}
+
+func TestXML_JSONComponentUnchanged(t *testing.T) {
+	schemas := xmlSpec(t).Components.Schemas
+
+	// encoding/json marshals XMLName like any other exported field.
+	invoice := schemas["annot8fixtures.XMLInvoice"]
+	AssertDeepEqual(t, []string{"XMLName", "currency", "customer", "id", "internal", "lines", "notes"}, schemaKeys(invoice.Properties))
+	if invoice.XML != nil {
+		t.Fatalf("JSON component should not carry an xml object: %+v", invoice.XML)
+	}
+}
+
+func TestXML_ComponentFollowsXMLTags(t *testing.T) {
+	schemas := xmlSpec(t).Components.Schemas
+
+	invoice := schemas["annot8fixtures.XMLInvoiceXML"]
+	AssertDeepEqual(t, &annot8.XML{Name: "invoice", Namespace: "urn:billing"}, invoice.XML)
+	AssertDeepEqual(t, []string{"currency", "customer", "id", "lines", "meta"}, schemaKeys(invoice.Properties))
+	AssertDeepEqual(t, []string{"id", "customer", "lines"}, invoice.Required)
+
+	AssertDeepEqual(t, &annot8.XML{Name: "id", Attribute: true}, invoice.Properties["id"].XML)
+	AssertDeepEqual(t, &annot8.XML{Name: "currency", Attribute: true}, invoice.Properties["currency"].XML)
+
+	// lines>line: a wrapped array of <line> elements.
+	lines := invoice.Properties["lines"]
+	AssertDeepEqual(t, &annot8.XML{Name: "lines", Wrapped: true}, lines.XML)
+	AssertEqual(t, "#/components/schemas/annot8fixtures.XMLLineXML", lines.Items.AllOf[0].Ref)
+	AssertDeepEqual(t, &annot8.XML{Name: "line"}, lines.Items.XML)
+
+	// meta>notes: nested elements.
+	meta := invoice.Properties["meta"]
+	AssertEqual(t, "string", meta.Properties["notes"].Type)
+
+	// XMLName names the element; it is not a member.
+	if _, ok := schemas["xml.NameXML"]; ok {
+		t.Fatal("XMLName should not produce an XML component")
+	}
+
+	line := schemas["annot8fixtures.XMLLineXML"]
+	AssertDeepEqual(t, []string{"qty", "sku"}, schemaKeys(line.Properties))
+
+	customer := schemas["annot8fixtures.XMLCustomerXML"]
+	AssertDeepEqual(t, &annot8.XML{Name: "XMLCustomer"}, customer.XML)
+	AssertDeepEqual(t, &annot8.XML{Name: "tier", Attribute: true}, customer.Properties["tier"].XML)
+}
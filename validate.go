@@ -53,6 +53,92 @@ func ValidateAnnotations(spec *Spec) []string {
 	return violations
 }
 
+// ValidatePathParameters reports @Param path declarations whose type or
+// format contradicts the route's regex constraint (documenting {id:[0-9]+}
+// as a uuid, say). The declaration is what the spec documents.
+func ValidatePathParameters(spec *Spec) []string {
+	if spec == nil {
+		return []string{"spec is nil"}
+	}
+
+	var violations []string
+	for _, item := range collectOperations(spec) {
+		label := operationLabel(item.path, item.method, item.op)
+		for _, conflict := range item.op.pathParamConflicts {
+			violations = append(violations, label+": "+conflict)
+		}
+	}
+
+	sort.Strings(violations)
+	return violations
+}
+
+// ValidateSecuritySchemes reports security requirements naming schemes the
+// spec does not declare, OAuth2 scopes no flow of the scheme defines, and
+// declared schemes missing what their type needs (an oauth2 flow's tokenUrl,
+// an openIdConnectUrl).
+func ValidateSecuritySchemes(spec *Spec) []string {
+	if spec == nil {
+		return []string{"spec is nil"}
+	}
+
+	var schemes map[string]SecurityScheme
+	if spec.Components != nil {
+		schemes = spec.Components.SecuritySchemes
+	}
+
+	var violations []string
+	forEachSecurityRequirement(spec, func(label string, req SecurityRequirement) {
+		if label == "" {
+			label = "root security"
+		}
+		for name, scopes := range req {
+			scheme, ok := schemes[name]
+			if !ok {
+				violations = append(violations, fmt.Sprintf("%s: security scheme %q is not declared", label, name))
+				continue
+			}
+			if scheme.Type != "oauth2" {
+				continue
+			}
+			for _, scope := range scopes {
+				if !oauthScopeDefined(scheme, scope) {
+					violations = append(violations, fmt.Sprintf("%s: scope %q is not defined by oauth2 scheme %q", label, scope, name))
+				}
+			}
+		}
+	})
+	for name, scheme := range schemes {
+		for _, problem := range securitySchemeProblems(scheme) {
+			violations = append(violations, fmt.Sprintf("security scheme %q: %s", name, problem))
+		}
+	}
+
+	sort.Strings(violations)
+	return violations
+}
+
+// UnmatchedSecurityMiddleware reports middleware on secured operations that
+// no security rule identified, such as an authentication middleware that was
+// never registered. It is a review aid rather than a validation: logging and
+// recovery middleware appear too.
+func UnmatchedSecurityMiddleware(spec *Spec) []string {
+	if spec == nil {
+		return []string{"spec is nil"}
+	}
+
+	var report []string
+	for _, item := range collectOperations(spec) {
+		label := operationLabel(item.path, item.method, item.op)
+		for _, name := range item.op.unmatchedMiddleware {
+			report = append(report, fmt.Sprintf("%s: middleware %s matched no security rule", label, name))
+		}
+	}
+
+	sort.Strings(report)
+	return report
+}
+
 // ValidateOperationIDs reports missing or duplicate operation IDs.
 func ValidateOperationIDs(spec *Spec) []string {
 	if spec == nil {
@@ -108,12 +194,22 @@ func ValidateAmbiguousPaths(spec *Spec) []string {
 	}
 	sort.Strings(paths)
 
+	// Catch-all routes end in a parameter matching the rest of the path.
+	wildcards := make(map[string]bool)
+	for _, item := range collectOperations(spec) {
+		for _, p := range item.op.Parameters {
+			if p.Wildcard {
+				wildcards[item.path] = true
+			}
+		}
+	}
+
 	var violations []string
 	for i := 0; i < len(paths); i++ {
 		for j := i + 1; j < len(paths); j++ {
 			leftPath := paths[i]
 			rightPath := paths[j]
-			if !pathsAreAmbiguous(leftPath, rightPath) {
+			if !pathsAreAmbiguous(leftPath, rightPath, wildcards[leftPath], wildcards[rightPath]) {
 				continue
 			}
 			if !hasMethodOverlap(methodsByPath[leftPath], methodsByPath[rightPath]) {
@@ -127,6 +223,16 @@ func ValidateAmbiguousPaths(spec *Spec) []string {
 	return violations
 }
 
+// ValidateSchemaTags reports struct tag values that could not be coerced to
+// their field's schema type or parsed as schemas and were left out of the
+// spec.
+func ValidateSchemaTags(spec *Spec) []string {
+	if spec == nil {
+		return []string{"spec is nil"}
+	}
+	return append([]string(nil), spec.schemaDiagnostics...)
+}
+
 // ValidateRefs reports unresolved #/components/* references.
 func ValidateRefs(spec *Spec) []string {
 	if spec == nil {
@@ -312,10 +418,36 @@ func parseComponentRef(ref string) (componentType string, name string, ok bool)
 	return componentType, name, true
 }
 
-func pathsAreAmbiguous(left, right string) bool {
+// pathsAreAmbiguous reports whether two templates match the same URL and
+// only templated segments tell them apart. A wildcard template's last
+// segment matches one or more segments, so it absorbs the tail of a longer
+// template; a static segment in that tail still makes the longer one win.
+func pathsAreAmbiguous(left, right string, leftWildcard, rightWildcard bool) bool {
 	leftSegments := splitPathSegments(left)
 	rightSegments := splitPathSegments(right)
 
+	if leftWildcard != rightWildcard {
+		wild, other := leftSegments, rightSegments
+		if rightWildcard {
+			wild, other = rightSegments, leftSegments
+		}
+		if len(wild) == 0 || len(other) < len(wild) {
+			return false
+		}
+		for _, segment := range other[len(wild)-1:] {
+			if !isTemplatedSegment(segment) {
+				return false
+			}
+		}
+		leftSegments, rightSegments = wild[:len(wild)-1], other[:len(wild)-1]
+		for i := range leftSegments {
+			if leftSegments[i] != rightSegments[i] && (!isTemplatedSegment(leftSegments[i]) || !isTemplatedSegment(rightSegments[i])) {
+				return false
+			}
+		}
+		return true
+	}
+
 	if len(leftSegments) != len(rightSegments) {
 		return false
 	}